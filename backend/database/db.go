@@ -2,9 +2,11 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	_ "github.com/lib/pq"
@@ -12,45 +14,108 @@ import (
 
 var DB *sql.DB
 
+// ErrNotFound is returned by reads and updates when the row doesn't exist (or is soft-deleted),
+// so callers can tell a genuine miss from a database failure.
+var ErrNotFound = errors.New("not found")
+
 // ─── Models ──────────────────────────────────────────────────────────────────
 
 type Search struct {
-	ID            string    `json:"id"`
-	Origin        string    `json:"origin"`
-	Destination   string    `json:"destination"`
-	DepartureDate string    `json:"departure_date"`
-	ReturnDate    string    `json:"return_date"`
-	Budget        float64   `json:"budget"`
-	Passengers    int       `json:"passengers"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            string     `json:"id"`
+	Origin        string     `json:"origin"`
+	Destination   string     `json:"destination"`
+	DepartureDate string     `json:"departure_date"`
+	ReturnDate    string     `json:"return_date"`
+	Budget        MinorUnits `json:"budget"`
+	Passengers    int        `json:"passengers"`
+	NumNights     int        `json:"num_nights"`
+	CreatedAt     time.Time  `json:"created_at"`
+	// Passengers counts adults; children and infants are priced separately.
+	Children int `json:"children"`
+	Infants  int `json:"infants"`
+	// Currency is the ISO 4217 code the search's prices and budget are in.
+	Currency string `json:"currency"`
 }
 
 type Itinerary struct {
+	ID           string     `json:"id"`
+	SearchID     string     `json:"search_id"`
+	FlightsJSON  string     `json:"flights_json"`
+	HotelsJSON   string     `json:"hotels_json"`
+	AISummary    string     `json:"ai_summary"`
+	PDFData      []byte     `json:"pdf_data,omitempty"` // stored in DB, no filesystem needed
+	TravelerName string     `json:"traveler_name"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"` // set by soft delete; nil for live rows
+	// The offers chosen in GenerateHandler; nil for the search's own unselected itinerary.
+	SelectedFlightIndex *int `json:"selected_flight_index,omitempty"`
+	SelectedHotelIndex  *int `json:"selected_hotel_index,omitempty"`
+	// PDFOptions records the render options (sections, cover page…) the PDF was made with,
+	// so an identical generate request can reuse it.
+	PDFOptions string `json:"pdf_options,omitempty"`
+}
+
+// ItinerarySummary is an itinerary's metadata for listings, without the PDF and cached offers.
+type ItinerarySummary struct {
 	ID           string    `json:"id"`
 	SearchID     string    `json:"search_id"`
-	FlightsJSON  string    `json:"flights_json"`
-	HotelsJSON   string    `json:"hotels_json"`
-	AISummary    string    `json:"ai_summary"`
-	PDFData      []byte    `json:"pdf_data,omitempty"` // stored in DB, no filesystem needed
 	TravelerName string    `json:"traveler_name"`
 	CreatedAt    time.Time `json:"created_at"`
+	HasPDF       bool      `json:"has_pdf"`
+	PDFSize      int       `json:"pdf_size"` // bytes; 0 without a PDF
+
+	SelectedFlightIndex *int `json:"selected_flight_index,omitempty"`
+	SelectedHotelIndex  *int `json:"selected_hotel_index,omitempty"`
+}
+
+// ItineraryFilter narrows ListItineraries; zero values don't filter.
+type ItineraryFilter struct {
+	SearchID string
+	HasPDF   *bool
+}
+
+// PriceSnapshot is the cheapest flight and hotel one search run found. RouteKey names the trip
+// that was priced, so reruns of the same search under new IDs share one history.
+type PriceSnapshot struct {
+	SearchID       string     `json:"search_id"`
+	RouteKey       string     `json:"-"`
+	CheapestFlight MinorUnits `json:"cheapest_flight"`
+	CheapestHotel  MinorUnits `json:"cheapest_hotel"`
+	Currency       string     `json:"currency"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// MinorUnits is an amount in minor units (cents) of the row's currency, stored as BIGINT so
+// budgets and prices stay exact, like services.Money. It marshals to a decimal number.
+type MinorUnits int64
+
+// Float64 returns the decimal amount.
+func (m MinorUnits) Float64() float64 {
+	return float64(m) / 100
+}
+
+func (m MinorUnits) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(m.Float64(), 'f', -1, 64)), nil
 }
 
 // ─── Init ─────────────────────────────────────────────────────────────────────
 
 func InitDB() {
-	dsn := buildDSN()
-
 	var err error
-	DB, err = sql.Open("postgres", dsn)
+	DB, err = openDB()
 	if err != nil {
 		log.Fatalf("❌ Failed to open database: %v", err)
 	}
 
-	// Connection pool settings suitable for Railway's free PostgreSQL
-	DB.SetMaxOpenConns(10)
-	DB.SetMaxIdleConns(5)
-	DB.SetConnMaxLifetime(5 * time.Minute)
+	if isSQLite() {
+		// SQLite allows one writer at a time; a single connection avoids "database is locked"
+		DB.SetMaxOpenConns(1)
+	} else {
+		// Defaults suit Railway's free PostgreSQL; bigger tiers can raise them
+		DB.SetMaxOpenConns(envInt("DB_MAX_OPEN_CONNS", 10))
+		DB.SetMaxIdleConns(envInt("DB_MAX_IDLE_CONNS", 5))
+		DB.SetConnMaxLifetime(envDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute))
+	}
 
 	// Retry connection up to 10 times (Railway DB may take a moment to be ready)
 	for i := 0; i < 10; i++ {
@@ -88,6 +153,9 @@ func buildDSN() string {
 
 // ─── Migrations ───────────────────────────────────────────────────────────────
 
+// extraMigrations are registered by the other files of this package and run after the core schema.
+var extraMigrations []string
+
 func migrate() {
 	migrations := []string{
 		`CREATE TABLE IF NOT EXISTS searches (
@@ -117,10 +185,61 @@ func migrate() {
 
 		`CREATE INDEX IF NOT EXISTS idx_searches_created_at
 			ON searches(created_at DESC)`,
+
+		`CREATE TABLE IF NOT EXISTS price_snapshots (
+			id              BIGSERIAL PRIMARY KEY,
+			search_id       TEXT NOT NULL REFERENCES searches(id),
+			cheapest_flight NUMERIC(12,2),
+			cheapest_hotel  NUMERIC(12,2),
+			created_at      TIMESTAMPTZ DEFAULT NOW()
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_price_snapshots_search_id
+			ON price_snapshots(search_id, created_at)`,
+
+		`ALTER TABLE itineraries ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+
+		`ALTER TABLE searches ADD COLUMN IF NOT EXISTS num_nights INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE searches ADD COLUMN IF NOT EXISTS children INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE searches ADD COLUMN IF NOT EXISTS infants INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE searches ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD'`,
+
+		`ALTER TABLE itineraries ADD COLUMN IF NOT EXISTS selected_flight_index INTEGER`,
+		`ALTER TABLE itineraries ADD COLUMN IF NOT EXISTS selected_hotel_index INTEGER`,
+
+		`ALTER TABLE itineraries ADD COLUMN IF NOT EXISTS pdf_options TEXT NOT NULL DEFAULT ''`,
+
+		`ALTER TABLE price_snapshots ADD COLUMN IF NOT EXISTS route_key TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE price_snapshots ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'USD'`,
+		`CREATE INDEX IF NOT EXISTS idx_price_snapshots_route_key
+			ON price_snapshots(route_key, created_at)`,
+
+		// Amounts in minor units, filled in from the NUMERIC columns for existing rows. The
+		// NUMERIC budget is still written, since older binaries read it and it is NOT NULL.
+		`ALTER TABLE searches ADD COLUMN IF NOT EXISTS budget_minor BIGINT`,
+		`UPDATE searches SET budget_minor = ROUND(budget * 100) WHERE budget_minor IS NULL`,
+		`ALTER TABLE price_snapshots ADD COLUMN IF NOT EXISTS cheapest_flight_minor BIGINT`,
+		`ALTER TABLE price_snapshots ADD COLUMN IF NOT EXISTS cheapest_hotel_minor BIGINT`,
+		`UPDATE price_snapshots SET cheapest_flight_minor = ROUND(cheapest_flight * 100),
+			cheapest_hotel_minor = ROUND(cheapest_hotel * 100)
+			WHERE cheapest_flight_minor IS NULL`,
+
+		`CREATE TABLE IF NOT EXISTS booking_clicks (
+			id           BIGSERIAL PRIMARY KEY,
+			itinerary_id TEXT NOT NULL,
+			target_url   TEXT NOT NULL,
+			user_agent   TEXT NOT NULL DEFAULT '',
+			created_at   TIMESTAMPTZ DEFAULT NOW()
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_itineraries_created_at
+			ON itineraries(created_at DESC)`,
 	}
 
+	migrations = append(migrations, extraMigrations...)
+
 	for _, m := range migrations {
-		if _, err := DB.Exec(m); err != nil {
+		if err := execMigration(m); err != nil {
 			log.Fatalf("❌ Migration failed: %v\nSQL: %s", err, m)
 		}
 	}
@@ -128,35 +247,161 @@ func migrate() {
 
 // ─── CRUD ─────────────────────────────────────────────────────────────────────
 
+// execer is satisfied by both *sql.DB and *sql.Tx, so inserts can run alone or in a transaction.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
 func SaveSearch(s *Search) error {
-	_, err := DB.Exec(`
-		INSERT INTO searches (id, origin, destination, departure_date, return_date, budget, passengers)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-		s.ID, s.Origin, s.Destination, s.DepartureDate, s.ReturnDate, s.Budget, s.Passengers)
+	return saveSearch(DB, s)
+}
+
+// SaveSearchWithItinerary stores a search and its first itinerary in one transaction, so a
+// failed itinerary insert never leaves an orphan search behind.
+func SaveSearchWithItinerary(s *Search, i *Itinerary) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	if err := saveSearch(tx, s); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("save search: %w", err)
+	}
+	if err := saveItinerary(tx, i); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("save itinerary: %w", err)
+	}
+	return tx.Commit()
+}
+
+func saveSearch(db execer, s *Search) error {
+	_, err := db.Exec(`
+		INSERT INTO searches (id, origin, destination, departure_date, return_date, budget, budget_minor, passengers, num_nights, children, infants, currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		s.ID, s.Origin, s.Destination, s.DepartureDate, s.ReturnDate, s.Budget.Float64(), s.Budget, s.Passengers, s.NumNights, s.Children, s.Infants, s.Currency)
 	return err
 }
 
 func GetSearch(id string) (*Search, error) {
 	s := &Search{}
 	err := DB.QueryRow(`
-		SELECT id, origin, destination, departure_date, return_date, budget, passengers, created_at
+		SELECT id, origin, destination, departure_date, return_date, budget_minor, passengers, num_nights, created_at, children, infants, currency
 		FROM searches WHERE id = $1`, id).
 		Scan(&s.ID, &s.Origin, &s.Destination, &s.DepartureDate, &s.ReturnDate,
-			&s.Budget, &s.Passengers, &s.CreatedAt)
+			&s.Budget, &s.Passengers, &s.NumNights, &s.CreatedAt, &s.Children, &s.Infants, &s.Currency)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
+	}
+	// Searches saved before num_nights was stored
+	if s.NumNights == 0 {
+		s.NumNights = NightsBetween(s.DepartureDate, s.ReturnDate)
 	}
 	return s, nil
 }
 
+// ListSearches returns a page of searches, newest first (served by idx_searches_created_at).
+func ListSearches(limit, offset int) ([]Search, error) {
+	rows, err := DB.Query(`
+		SELECT id, origin, destination, departure_date, return_date, budget_minor, passengers, num_nights, created_at, children, infants, currency
+		FROM searches
+		ORDER BY created_at DESC LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := []Search{}
+	for rows.Next() {
+		var s Search
+		if err := rows.Scan(&s.ID, &s.Origin, &s.Destination, &s.DepartureDate, &s.ReturnDate,
+			&s.Budget, &s.Passengers, &s.NumNights, &s.CreatedAt, &s.Children, &s.Infants, &s.Currency); err != nil {
+			return nil, err
+		}
+		if s.NumNights == 0 {
+			s.NumNights = NightsBetween(s.DepartureDate, s.ReturnDate)
+		}
+		searches = append(searches, s)
+	}
+	return searches, rows.Err()
+}
+
+// CountSearches returns how many searches are stored, for paging through ListSearches.
+func CountSearches() (int, error) {
+	var n int
+	err := DB.QueryRow(`SELECT COUNT(*) FROM searches`).Scan(&n)
+	return n, err
+}
+
+// NightsBetween is the hotel night count for YYYY-MM-DD check-in/check-out dates (0 if unparsable).
+func NightsBetween(checkIn, checkOut string) int {
+	in, err1 := time.Parse("2006-01-02", checkIn)
+	out, err2 := time.Parse("2006-01-02", checkOut)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(out.Sub(in).Hours() / 24)
+}
+
 func SaveItinerary(i *Itinerary) error {
-	_, err := DB.Exec(`
-		INSERT INTO itineraries (id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-		i.ID, i.SearchID, i.FlightsJSON, i.HotelsJSON, i.AISummary, i.PDFData, i.TravelerName)
+	return saveItinerary(DB, i)
+}
+
+func saveItinerary(db execer, i *Itinerary) error {
+	_, err := db.Exec(`
+		INSERT INTO itineraries (id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name,
+			selected_flight_index, selected_hotel_index, pdf_options)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		i.ID, i.SearchID, i.FlightsJSON, i.HotelsJSON, i.AISummary, i.PDFData, i.TravelerName,
+		i.SelectedFlightIndex, i.SelectedHotelIndex, i.PDFOptions)
 	return err
 }
 
+// FindGeneratedItinerary returns the live itinerary generated for one selection of a search
+// by travelerName (newest, if older duplicates exist), or ErrNotFound.
+func FindGeneratedItinerary(searchID string, flightIndex, hotelIndex int, travelerName string) (*Itinerary, error) {
+	i := &Itinerary{}
+	err := DB.QueryRow(`
+		SELECT id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name, created_at,
+			selected_flight_index, selected_hotel_index, pdf_options
+		FROM itineraries
+		WHERE search_id = $1 AND selected_flight_index = $2 AND selected_hotel_index = $3
+			AND traveler_name = $4 AND deleted_at IS NULL
+		ORDER BY created_at DESC LIMIT 1`, searchID, flightIndex, hotelIndex, travelerName).
+		Scan(&i.ID, &i.SearchID, &i.FlightsJSON, &i.HotelsJSON,
+			&i.AISummary, &i.PDFData, &i.TravelerName, &i.CreatedAt,
+			&i.SelectedFlightIndex, &i.SelectedHotelIndex, &i.PDFOptions)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return i, nil
+}
+
+// UpsertGeneratedItinerary saves a generated itinerary keyed on (search, selected flight,
+// selected hotel, traveler name): a live row with the same key is overwritten in place and
+// i.ID set to its ID, otherwise i is inserted. There is no unique constraint behind the key
+// (older databases hold duplicates), so callers serialize on it with WithItineraryLock.
+func UpsertGeneratedItinerary(i *Itinerary) error {
+	if i.SelectedFlightIndex == nil || i.SelectedHotelIndex == nil {
+		return fmt.Errorf("upsert needs a flight and hotel selection")
+	}
+	existing, err := FindGeneratedItinerary(i.SearchID, *i.SelectedFlightIndex, *i.SelectedHotelIndex, i.TravelerName)
+	if errors.Is(err, ErrNotFound) {
+		return saveItinerary(DB, i)
+	}
+	if err != nil {
+		return err
+	}
+
+	i.ID = existing.ID
+	_, err = DB.Exec(`
+		UPDATE itineraries SET flights_json = $1, hotels_json = $2, ai_summary = $3, pdf_data = $4, pdf_options = $5
+		WHERE id = $6`,
+		i.FlightsJSON, i.HotelsJSON, i.AISummary, i.PDFData, i.PDFOptions, i.ID)
+	return err
+}
+
+// UpdateItineraryPDF overwrites an itinerary's stored PDF. Callers that regenerate a PDF
+// should run generation and this write inside WithItineraryLock.
 func UpdateItineraryPDF(id string, pdfData []byte, travelerName string) error {
 	_, err := DB.Exec(`
 		UPDATE itineraries SET pdf_data = $1, traveler_name = $2 WHERE id = $3`,
@@ -167,12 +412,14 @@ func UpdateItineraryPDF(id string, pdfData []byte, travelerName string) error {
 func GetItinerary(id string) (*Itinerary, error) {
 	i := &Itinerary{}
 	err := DB.QueryRow(`
-		SELECT id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name, created_at
-		FROM itineraries WHERE id = $1`, id).
+		SELECT id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name, created_at,
+			selected_flight_index, selected_hotel_index
+		FROM itineraries WHERE id = $1 AND deleted_at IS NULL`, id).
 		Scan(&i.ID, &i.SearchID, &i.FlightsJSON, &i.HotelsJSON,
-			&i.AISummary, &i.PDFData, &i.TravelerName, &i.CreatedAt)
+			&i.AISummary, &i.PDFData, &i.TravelerName, &i.CreatedAt,
+			&i.SelectedFlightIndex, &i.SelectedHotelIndex)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 	return i, nil
 }
@@ -180,19 +427,383 @@ func GetItinerary(id string) (*Itinerary, error) {
 func GetItineraryBySearchID(searchID string) (*Itinerary, error) {
 	i := &Itinerary{}
 	err := DB.QueryRow(`
-		SELECT id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name, created_at
-		FROM itineraries WHERE search_id = $1
+		SELECT id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name, created_at,
+			selected_flight_index, selected_hotel_index
+		FROM itineraries WHERE search_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC LIMIT 1`, searchID).
 		Scan(&i.ID, &i.SearchID, &i.FlightsJSON, &i.HotelsJSON,
-			&i.AISummary, &i.PDFData, &i.TravelerName, &i.CreatedAt)
+			&i.AISummary, &i.PDFData, &i.TravelerName, &i.CreatedAt,
+			&i.SelectedFlightIndex, &i.SelectedHotelIndex)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 	return i, nil
 }
 
+// RecordBookingClick logs a "book now" redirect for conversion analytics.
+func RecordBookingClick(itineraryID, targetURL, userAgent string) error {
+	_, err := DB.Exec(`
+		INSERT INTO booking_clicks (itinerary_id, target_url, user_agent)
+		VALUES ($1, $2, $3)`,
+		itineraryID, targetURL, userAgent)
+	return err
+}
+
+// ListItinerariesWithPDF returns a search's itineraries that have a generated PDF, newest first.
+func ListItinerariesWithPDF(searchID string, limit int) ([]Itinerary, error) {
+	rows, err := DB.Query(`
+		SELECT id, search_id, pdf_data, traveler_name, created_at
+		FROM itineraries
+		WHERE search_id = $1 AND deleted_at IS NULL AND pdf_data IS NOT NULL AND length(pdf_data) > 0
+		ORDER BY created_at DESC LIMIT $2`, searchID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itineraries := []Itinerary{}
+	for rows.Next() {
+		var i Itinerary
+		if err := rows.Scan(&i.ID, &i.SearchID, &i.PDFData, &i.TravelerName, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		itineraries = append(itineraries, i)
+	}
+	return itineraries, rows.Err()
+}
+
+// ListGeneratedItineraries returns a search's itineraries that have a generated PDF, oldest
+// first, with the PDF and the flight/hotel selection (nil for comparisons and itineraries
+// generated before selections were stored).
+func ListGeneratedItineraries(searchID string, limit int) ([]Itinerary, error) {
+	rows, err := DB.Query(`
+		SELECT id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name, created_at,
+			selected_flight_index, selected_hotel_index
+		FROM itineraries
+		WHERE search_id = $1 AND deleted_at IS NULL AND pdf_data IS NOT NULL AND length(pdf_data) > 0
+		ORDER BY created_at ASC LIMIT $2`, searchID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itineraries := []Itinerary{}
+	for rows.Next() {
+		var i Itinerary
+		if err := rows.Scan(&i.ID, &i.SearchID, &i.FlightsJSON, &i.HotelsJSON, &i.AISummary, &i.PDFData,
+			&i.TravelerName, &i.CreatedAt, &i.SelectedFlightIndex, &i.SelectedHotelIndex); err != nil {
+			return nil, err
+		}
+		itineraries = append(itineraries, i)
+	}
+	return itineraries, rows.Err()
+}
+
+// ListItineraries returns a page of live (not soft-deleted) itineraries matching f, newest
+// first, without the PDF bytes.
+func ListItineraries(f ItineraryFilter, limit, offset int) ([]ItinerarySummary, error) {
+	where, args := f.where()
+	args = append(args, limit, offset)
+	rows, err := DB.Query(fmt.Sprintf(`
+		SELECT id, search_id, COALESCE(traveler_name, ''), created_at, COALESCE(length(pdf_data), 0),
+			selected_flight_index, selected_hotel_index
+		FROM itineraries
+		WHERE %s
+		ORDER BY created_at DESC LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itineraries := []ItinerarySummary{}
+	for rows.Next() {
+		var i ItinerarySummary
+		if err := rows.Scan(&i.ID, &i.SearchID, &i.TravelerName, &i.CreatedAt, &i.PDFSize,
+			&i.SelectedFlightIndex, &i.SelectedHotelIndex); err != nil {
+			return nil, err
+		}
+		i.HasPDF = i.PDFSize > 0
+		itineraries = append(itineraries, i)
+	}
+	return itineraries, rows.Err()
+}
+
+// CountItineraries returns how many itineraries match f, for paging through ListItineraries.
+func CountItineraries(f ItineraryFilter) (int, error) {
+	where, args := f.where()
+	var n int
+	err := DB.QueryRow(`SELECT COUNT(*) FROM itineraries WHERE `+where, args...).Scan(&n)
+	return n, err
+}
+
+// where builds the filter's WHERE clause with numbered placeholders.
+func (f ItineraryFilter) where() (string, []any) {
+	clause := "deleted_at IS NULL"
+	var args []any
+	if f.SearchID != "" {
+		args = append(args, f.SearchID)
+		clause += fmt.Sprintf(" AND search_id = $%d", len(args))
+	}
+	if f.HasPDF != nil {
+		if *f.HasPDF {
+			clause += " AND pdf_data IS NOT NULL AND length(pdf_data) > 0"
+		} else {
+			clause += " AND (pdf_data IS NULL OR length(pdf_data) = 0)"
+		}
+	}
+	return clause, args
+}
+
+// SoftDeleteItinerary hides an itinerary from every read without losing the row.
+// Returns ErrNotFound if it doesn't exist or is already deleted.
+func SoftDeleteItinerary(id string) error {
+	res, err := DB.Exec(`
+		UPDATE itineraries SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// RestoreItinerary undoes a soft delete. Returns ErrNotFound if the itinerary isn't deleted.
+func RestoreItinerary(id string) error {
+	res, err := DB.Exec(`
+		UPDATE itineraries SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// DeleteItinerary permanently removes an itinerary, PDF and traveler name included, whether
+// or not it was soft-deleted. Returns ErrNotFound if it doesn't exist.
+func DeleteItinerary(id string) error {
+	res, err := DB.Exec(`DELETE FROM itineraries WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// DeleteSearch permanently removes a search with its itineraries and price history, in one
+// transaction so the foreign keys never see a half-deleted search. Returns ErrNotFound if
+// the search doesn't exist.
+func DeleteSearch(id string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM itineraries WHERE search_id = $1`, id); err != nil {
+		return fmt.Errorf("delete itineraries: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM price_snapshots WHERE search_id = $1`, id); err != nil {
+		return fmt.Errorf("delete price snapshots: %w", err)
+	}
+	res, err := tx.Exec(`DELETE FROM searches WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete search: %w", err)
+	}
+	if err := requireRow(res); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListDeletedItineraries returns soft-deleted itineraries, most recently deleted first.
+// PDF bytes and cached JSON are left out to keep the listing small.
+func ListDeletedItineraries() ([]Itinerary, error) {
+	rows, err := DB.Query(`
+		SELECT id, search_id, traveler_name, created_at, deleted_at
+		FROM itineraries WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	itineraries := []Itinerary{}
+	for rows.Next() {
+		var i Itinerary
+		if err := rows.Scan(&i.ID, &i.SearchID, &i.TravelerName, &i.CreatedAt, &i.DeletedAt); err != nil {
+			return nil, err
+		}
+		itineraries = append(itineraries, i)
+	}
+	return itineraries, rows.Err()
+}
+
+// PurgeDeletedItineraries hard-deletes itineraries soft-deleted before cutoff.
+func PurgeDeletedItineraries(cutoff time.Time) (int64, error) {
+	res, err := DB.Exec(`
+		DELETE FROM itineraries WHERE deleted_at IS NOT NULL AND deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// StartPurgeJob hard-purges soft-deleted itineraries older than grace, once at start and then hourly.
+func StartPurgeJob(grace time.Duration) {
+	go func() {
+		for {
+			n, err := PurgeDeletedItineraries(time.Now().Add(-grace))
+			if err != nil {
+				log.Printf("⚠️  Purge of deleted itineraries failed: %v", err)
+			} else if n > 0 {
+				log.Printf("🧹 Purged %d itineraries deleted more than %s ago", n, grace)
+			}
+			time.Sleep(time.Hour)
+		}
+	}()
+}
+
+// PurgeOlderThan deletes itineraries created more than d ago, then searches older than d that
+// have no itineraries left (with their price history), in that order for the foreign keys.
+// A recently generated itinerary keeps its old search alive. Returns the rows deleted.
+func PurgeOlderThan(d time.Duration) (int, error) {
+	cutoff := time.Now().UTC().Add(-d)
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM itineraries WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge itineraries: %w", err)
+	}
+	itineraries, _ := res.RowsAffected()
+
+	const expired = `SELECT id FROM searches s WHERE s.created_at < $1
+		AND NOT EXISTS (SELECT 1 FROM itineraries i WHERE i.search_id = s.id)`
+	if _, err := tx.Exec(`DELETE FROM price_snapshots WHERE search_id IN (`+expired+`)`, cutoff); err != nil {
+		return 0, fmt.Errorf("purge price snapshots: %w", err)
+	}
+	res, err = tx.Exec(`DELETE FROM searches WHERE id IN (`+expired+`)`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("purge searches: %w", err)
+	}
+	searches, _ := res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(itineraries + searches), nil
+}
+
+// StartRetentionJob runs PurgeOlderThan(retention) once at start and then every interval.
+func StartRetentionJob(retention, interval time.Duration) {
+	go func() {
+		for {
+			n, err := PurgeOlderThan(retention)
+			if err != nil {
+				log.Printf("⚠️  Retention cleanup failed: %v", err)
+			} else {
+				log.Printf("🧹 Retention cleanup removed %d searches/itineraries older than %s", n, retention)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+func SavePriceSnapshot(p *PriceSnapshot) error {
+	_, err := DB.Exec(`
+		INSERT INTO price_snapshots (search_id, route_key, cheapest_flight_minor, cheapest_hotel_minor, currency)
+		VALUES ($1, $2, $3, $4, $5)`,
+		p.SearchID, p.RouteKey, p.CheapestFlight, p.CheapestHotel, p.Currency)
+	return err
+}
+
+// GetPriceSnapshots returns the price history of the trip a search priced, oldest first: its
+// own snapshots and those of every other search with the same route key. Snapshots saved
+// before route keys existed only match their own search.
+func GetPriceSnapshots(searchID string) ([]PriceSnapshot, error) {
+	var routeKey string
+	err := DB.QueryRow(`
+		SELECT route_key FROM price_snapshots WHERE search_id = $1
+		ORDER BY created_at DESC LIMIT 1`, searchID).Scan(&routeKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []PriceSnapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	const columns = `SELECT search_id, route_key, cheapest_flight_minor, cheapest_hotel_minor, currency, created_at
+		FROM price_snapshots`
+	var rows *sql.Rows
+	if routeKey == "" {
+		rows, err = DB.Query(columns+` WHERE search_id = $1 ORDER BY created_at ASC`, searchID)
+	} else {
+		rows, err = DB.Query(columns+` WHERE route_key = $1 ORDER BY created_at ASC`, routeKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := []PriceSnapshot{}
+	for rows.Next() {
+		var p PriceSnapshot
+		if err := rows.Scan(&p.SearchID, &p.RouteKey, &p.CheapestFlight, &p.CheapestHotel, &p.Currency, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, p)
+	}
+	return snapshots, rows.Err()
+}
+
 // ─── Helpers ──────────────────────────────────────────────────────────────────
 
+func requireRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// notFound translates sql.ErrNoRows into ErrNotFound and passes other errors through.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// envInt reads a positive integer setting, or def when unset or invalid.
+func envInt(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v <= 0 {
+		log.Printf("⚠️  Invalid %s %q — using %d", key, raw, def)
+		return def
+	}
+	return v
+}
+
+// envDuration reads a time.ParseDuration setting such as "10m", or def when unset or invalid.
+func envDuration(key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil || v <= 0 {
+		log.Printf("⚠️  Invalid %s %q — using %s", key, raw, def)
+		return def
+	}
+	return v
+}
+
 func getEnv(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"slices"
+	"strings"
+)
+
+// ─── Drivers ──────────────────────────────────────────────────────────────────
+
+// Supported DB_DRIVER values. Postgres is what production runs; SQLite is for trying the
+// app locally without a database server and needs a binary built with -tags sqlite.
+const (
+	driverPostgres = "postgres"
+	driverSQLite   = "sqlite"
+)
+
+// driver is the DB_DRIVER in use, set by InitDB.
+var driver = driverPostgres
+
+func isSQLite() bool {
+	return driver == driverSQLite
+}
+
+// openDB opens the database named by DB_DRIVER (default postgres).
+func openDB() (*sql.DB, error) {
+	driver = strings.ToLower(getEnv("DB_DRIVER", driverPostgres))
+	switch driver {
+	case driverPostgres:
+		return sql.Open(driverPostgres, buildDSN())
+	case driverSQLite:
+		if !slices.Contains(sql.Drivers(), driverSQLite) {
+			log.Fatalf("❌ DB_DRIVER=sqlite but this binary was built without SQLite support (build with -tags sqlite)")
+		}
+		return sql.Open(driverSQLite, sqliteDSN())
+	default:
+		log.Fatalf("❌ Unknown DB_DRIVER %q (use postgres or sqlite)", driver)
+		return nil, nil
+	}
+}
+
+// sqliteDSN points at SQLITE_PATH (default tripmind.db) with foreign keys enforced, as
+// Postgres does, and a busy timeout so the purge job and requests don't collide.
+func sqliteDSN() string {
+	path := getEnv("SQLITE_PATH", "tripmind.db")
+	return "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+// ─── Schema Dialect ───────────────────────────────────────────────────────────
+
+// sqliteSchema rewrites the Postgres column types and defaults the migrations use.
+var sqliteSchema = strings.NewReplacer(
+	"BIGSERIAL PRIMARY KEY", "INTEGER PRIMARY KEY AUTOINCREMENT",
+	"TIMESTAMPTZ", "DATETIME",
+	"BYTEA", "BLOB",
+	"NUMERIC(12,2)", "REAL",
+	"DEFAULT NOW()", "DEFAULT CURRENT_TIMESTAMP",
+	"ADD COLUMN IF NOT EXISTS", "ADD COLUMN",
+)
+
+// execMigration runs one migration written for Postgres, translated for SQLite when needed.
+func execMigration(stmt string) error {
+	if !isSQLite() {
+		_, err := DB.Exec(stmt)
+		return err
+	}
+	_, err := DB.Exec(sqliteSchema.Replace(stmt))
+	// SQLite has no ADD COLUMN IF NOT EXISTS, so a column added by an earlier start is a duplicate.
+	if err != nil && strings.Contains(err.Error(), "duplicate column name") {
+		return nil
+	}
+	return err
+}
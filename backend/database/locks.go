@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ─── Itinerary Locks ──────────────────────────────────────────────────────────
+
+// WithItineraryLock runs fn while holding the lock for one itinerary, so concurrent
+// regenerations can't interleave their PDF writes. Different itineraries never block
+// each other. ITINERARY_LOCK selects the backend:
+//   - "memory" (default): in-process, enough for a single instance
+//   - "postgres": a session advisory lock, for several instances sharing one database
+//   - "off": no serialization
+func WithItineraryLock(id string, fn func() error) error {
+	switch strings.ToLower(os.Getenv("ITINERARY_LOCK")) {
+	case "off":
+		return fn()
+	case "postgres":
+		// A SQLite database is only ever used by one process, which the memory lock covers.
+		if !isSQLite() {
+			return withAdvisoryLock(id, fn)
+		}
+	}
+	unlock := itineraryLocks.lock(id)
+	defer unlock()
+	return fn()
+}
+
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*refMutex
+}
+
+type refMutex struct {
+	sync.Mutex
+	refs int
+}
+
+var itineraryLocks = &keyedMutex{locks: map[string]*refMutex{}}
+
+// lock blocks until key is free; entries are dropped once nobody holds or waits on them.
+func (k *keyedMutex) lock(key string) (unlock func()) {
+	k.mu.Lock()
+	m, ok := k.locks[key]
+	if !ok {
+		m = &refMutex{}
+		k.locks[key] = m
+	}
+	m.refs++
+	k.mu.Unlock()
+
+	m.Lock()
+	return func() {
+		m.Unlock()
+		k.mu.Lock()
+		m.refs--
+		if m.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// withAdvisoryLock holds pg_advisory_lock on a dedicated connection, since session
+// locks belong to the connection that took them.
+func withAdvisoryLock(id string, fn func() error) error {
+	ctx := context.Background()
+	conn, err := DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("lock connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext($1))`, "itinerary:"+id); err != nil {
+		return fmt.Errorf("advisory lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, "itinerary:"+id)
+
+	return fn()
+}
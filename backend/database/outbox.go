@@ -0,0 +1,114 @@
+package database
+
+import (
+	"time"
+)
+
+// ─── Notification Outbox ──────────────────────────────────────────────────────
+
+// Notification is a pending side effect (webhook, email, …) recorded in the same place as
+// the data it describes, and delivered later by a worker with at-least-once semantics.
+type Notification struct {
+	ID             int64     `json:"id"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	Kind           string    `json:"kind"`
+	Payload        string    `json:"payload"`
+	Status         string    `json:"status"` // pending, delivered or failed
+	Attempts       int       `json:"attempts"`
+	LastError      string    `json:"last_error,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func init() {
+	extraMigrations = append(extraMigrations,
+		`CREATE TABLE IF NOT EXISTS notification_outbox (
+			id              BIGSERIAL PRIMARY KEY,
+			idempotency_key TEXT NOT NULL UNIQUE,
+			kind            TEXT NOT NULL,
+			payload         TEXT NOT NULL,
+			status          TEXT NOT NULL DEFAULT 'pending',
+			attempts        INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			last_error      TEXT NOT NULL DEFAULT '',
+			created_at      TIMESTAMPTZ DEFAULT NOW(),
+			delivered_at    TIMESTAMPTZ
+		)`,
+
+		`CREATE INDEX IF NOT EXISTS idx_notification_outbox_due
+			ON notification_outbox(next_attempt_at) WHERE status = 'pending'`,
+	)
+}
+
+// EnqueueNotification records a notification once per idempotency key; repeats (e.g. a
+// retried request) are ignored. Returns false when the key was already queued.
+func EnqueueNotification(key, kind, payload string) (bool, error) {
+	res, err := DB.Exec(`
+		INSERT INTO notification_outbox (idempotency_key, kind, payload)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (idempotency_key) DO NOTHING`,
+		key, kind, payload)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ClaimDueNotifications leases up to limit due notifications for lease, so other workers
+// (or instances) skip them while they're being delivered.
+func ClaimDueNotifications(limit int, lease time.Duration) ([]Notification, error) {
+	// SQLite serializes writers and has no row locks, so only Postgres needs SKIP LOCKED.
+	skipLocked := "FOR UPDATE SKIP LOCKED"
+	if isSQLite() {
+		skipLocked = ""
+	}
+	now := time.Now().UTC()
+	rows, err := DB.Query(`
+		UPDATE notification_outbox SET next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM notification_outbox
+			WHERE status = 'pending' AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $3
+			`+skipLocked+`
+		)
+		RETURNING id, idempotency_key, kind, payload, status, attempts, last_error, created_at`,
+		now.Add(lease), now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []Notification{}
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(&n.ID, &n.IdempotencyKey, &n.Kind, &n.Payload, &n.Status,
+			&n.Attempts, &n.LastError, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func MarkNotificationDelivered(id int64) error {
+	_, err := DB.Exec(`
+		UPDATE notification_outbox
+		SET status = 'delivered', attempts = attempts + 1, last_error = '', delivered_at = $1
+		WHERE id = $2`, time.Now().UTC(), id)
+	return err
+}
+
+// MarkNotificationFailed records a failed attempt and schedules the next one; when retry
+// is zero the notification is given up on and marked failed.
+func MarkNotificationFailed(id int64, cause error, retry time.Duration) error {
+	status := "pending"
+	if retry <= 0 {
+		status = "failed"
+	}
+	_, err := DB.Exec(`
+		UPDATE notification_outbox
+		SET status = $1, attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		WHERE id = $4`, status, cause.Error(), time.Now().UTC().Add(retry), id)
+	return err
+}
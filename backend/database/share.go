@@ -0,0 +1,66 @@
+package database
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+// ─── Share Links ──────────────────────────────────────────────────────────────
+
+// ErrShareExpired is returned for a share token that exists but is past its expiry.
+var ErrShareExpired = errors.New("share link expired")
+
+func init() {
+	extraMigrations = append(extraMigrations,
+		`ALTER TABLE itineraries ADD COLUMN IF NOT EXISTS share_token TEXT`,
+		`ALTER TABLE itineraries ADD COLUMN IF NOT EXISTS share_expires_at TIMESTAMPTZ`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_itineraries_share_token
+			ON itineraries(share_token)`,
+	)
+}
+
+// CreateShareLink gives an itinerary a new random share token valid for ttl, replacing
+// (and so revoking) any earlier one. Returns ErrNotFound if the itinerary doesn't exist or
+// is deleted.
+func CreateShareLink(itineraryID string, ttl time.Duration) (string, time.Time, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().UTC().Add(ttl)
+
+	res, err := DB.Exec(`
+		UPDATE itineraries SET share_token = $1, share_expires_at = $2
+		WHERE id = $3 AND deleted_at IS NULL`, token, expiresAt, itineraryID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := requireRow(res); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// GetSharedItinerary looks an itinerary up by share token, with the token's expiry.
+// Returns ErrNotFound for an unknown token and ErrShareExpired once it has lapsed.
+func GetSharedItinerary(token string) (*Itinerary, time.Time, error) {
+	i := &Itinerary{}
+	var expiresAt time.Time
+	err := DB.QueryRow(`
+		SELECT id, search_id, flights_json, hotels_json, ai_summary, pdf_data, traveler_name, created_at,
+			selected_flight_index, selected_hotel_index, share_expires_at
+		FROM itineraries WHERE share_token = $1 AND deleted_at IS NULL`, token).
+		Scan(&i.ID, &i.SearchID, &i.FlightsJSON, &i.HotelsJSON,
+			&i.AISummary, &i.PDFData, &i.TravelerName, &i.CreatedAt,
+			&i.SelectedFlightIndex, &i.SelectedHotelIndex, &expiresAt)
+	if err != nil {
+		return nil, time.Time{}, notFound(err)
+	}
+	if time.Now().After(expiresAt) {
+		return nil, expiresAt, ErrShareExpired
+	}
+	return i, expiresAt, nil
+}
@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package database
+
+// Registers the pure-Go "sqlite" driver for DB_DRIVER=sqlite. Only builds with -tags sqlite
+// link it in; the default binary stays Postgres-only.
+import _ "modernc.org/sqlite"
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"tripmind/database"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth gates admin routes behind ADMIN_API_KEY, sent as "Authorization: Bearer <key>"
+// or "X-Admin-Key: <key>". When ADMIN_API_KEY is unset the admin routes are disabled.
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminKey := os.Getenv("ADMIN_API_KEY")
+		if adminKey == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin endpoints are disabled"})
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Key")
+		if provided == "" {
+			provided = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(adminKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin key"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// UsageHandler reports today's (UTC) provider call and fallback counts.
+func UsageHandler(c *gin.Context) {
+	day, stats := services.UsageSnapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"date":      day,
+		"resets_at": "00:00 UTC",
+		"providers": stats,
+	})
+}
+
+// DeletedItinerariesHandler lists soft-deleted itineraries that can still be restored.
+func DeletedItinerariesHandler(c *gin.Context) {
+	itineraries, err := database.ListDeletedItineraries()
+	if err != nil {
+		logf(c, "❌ Failed to list deleted itineraries: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list deleted itineraries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"itineraries": itineraries})
+}
+
+// itineraryListItem is an itinerary in the admin listing, with its download URL when it
+// has a PDF.
+type itineraryListItem struct {
+	database.ItinerarySummary
+	PDFURL string `json:"pdf_url,omitempty"`
+}
+
+// ListItinerariesHandler pages through generated itineraries, newest first, with the total
+// for paging. PDFs aren't included, only their size and download URL.
+// GET /api/itineraries?search_id=&has_pdf=true|false&limit=20&offset=0
+func ListItinerariesHandler(c *gin.Context) {
+	filter := database.ItineraryFilter{SearchID: c.Query("search_id")}
+	if raw := c.Query("has_pdf"); raw != "" {
+		hasPDF, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "has_pdf must be true or false"})
+			return
+		}
+		filter.HasPDF = &hasPDF
+	}
+	limit, offset := pageParams(c)
+
+	summaries, err := database.ListItineraries(filter, limit, offset)
+	if err != nil {
+		logf(c, "❌ Failed to list itineraries: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load itineraries"})
+		return
+	}
+	total, err := database.CountItineraries(filter)
+	if err != nil {
+		logf(c, "❌ Failed to count itineraries: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load itineraries"})
+		return
+	}
+
+	items := make([]itineraryListItem, len(summaries))
+	for i, s := range summaries {
+		items[i] = itineraryListItem{ItinerarySummary: s}
+		if s.HasPDF {
+			items[i].PDFURL = "/api/download/" + s.ID
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"itineraries": items,
+		"total":       total,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// AdminDeleteItineraryHandler soft-deletes an itinerary like DELETE /api/itineraries/:id, or
+// with ?hard=true erases it and its PDF for good (e.g. on a traveler's erasure request).
+func AdminDeleteItineraryHandler(c *gin.Context) {
+	if c.Query("hard") == "true" {
+		EraseItineraryHandler(c)
+		return
+	}
+	DeleteItineraryHandler(c)
+}
+
+// RestoreItineraryHandler clears the soft-delete flag on an itinerary.
+func RestoreItineraryHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := database.RestoreItinerary(id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No deleted itinerary with this ID"})
+			return
+		}
+		logf(c, "❌ Failed to restore itinerary %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore itinerary"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Itinerary restored"})
+}
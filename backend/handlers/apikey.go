@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyAuth protects the endpoints that spend provider quota (search and generate) and those
+// that delete or share stored itineraries. When API_KEY is set, requests must send it as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>"; when it's unset every request passes,
+// so local development needs no key.
+func APIKeyAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := os.Getenv("API_KEY")
+		if apiKey == "" {
+			c.Next()
+			return
+		}
+
+		provided := c.GetHeader("X-API-Key")
+		if provided == "" {
+			provided = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid API key"})
+			return
+		}
+		c.Next()
+	}
+}
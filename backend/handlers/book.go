@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"tripmind/database"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BookHandler sends the traveler to book the itinerary's selected flight: its BookingLink
+// when the provider gave one, otherwise a flight search deep link for the same route and
+// dates. Itineraries without a stored selection have nothing to book and return 404.
+// GET /api/itinerary/:id/book
+func BookHandler(c *gin.Context) {
+	id := c.Param("id")
+	itinerary, err := database.GetItinerary(id)
+	if err != nil {
+		respondLookupError(c, err, "Itinerary not found")
+		return
+	}
+	if itinerary.SelectedFlightIndex == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No flight has been selected for this itinerary"})
+		return
+	}
+
+	var flights []services.Flight
+	if err := json.Unmarshal([]byte(itinerary.FlightsJSON), &flights); err != nil {
+		logf(c, "❌ Corrupt flight data for itinerary %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Corrupt flight data"})
+		return
+	}
+	idx := *itinerary.SelectedFlightIndex
+	if idx < 0 || idx >= len(flights) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Selected flight is no longer available"})
+		return
+	}
+
+	link := flights[idx].BookingLink
+	if link == "" {
+		search, err := database.GetSearch(itinerary.SearchID)
+		if err != nil {
+			respondLookupError(c, err, "Search not found")
+			return
+		}
+		link = services.FlightBookingLink(flights[idx], search.Origin, search.Destination, search.DepartureDate, search.ReturnDate)
+	}
+	if link == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No booking link is available for this itinerary"})
+		return
+	}
+
+	if err := database.RecordBookingClick(id, link, c.Request.UserAgent()); err != nil {
+		logf(c, "⚠️  Failed to record booking click for %s: %v", id, err)
+	}
+	c.Redirect(http.StatusFound, link)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CheapestDatesHandler lists the cheapest week-long round trips departing in a month, so
+// travellers with flexible dates can pick when to go.
+// GET /api/cheapest-dates?origin=TAS&destination=IST&month=YYYY-MM
+func CheapestDatesHandler(c *gin.Context) {
+	origin := strings.ToUpper(strings.TrimSpace(c.Query("origin")))
+	destination := strings.ToUpper(strings.TrimSpace(c.Query("destination")))
+	if len(origin) != 3 || len(destination) != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "origin and destination must be exactly 3 characters (e.g. TAS, IST)"})
+		return
+	}
+	if origin == destination {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Origin and destination must differ"})
+		return
+	}
+	for _, code := range []string{origin, destination} {
+		if err := services.CheckAirportCode(c.Request.Context(), code); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	month := c.Query("month")
+	from, to, err := services.MonthDates(month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month format. Use YYYY-MM"})
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if to.Before(today) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month is in the past"})
+		return
+	}
+	// Nobody can depart earlier this month than today
+	if from.Before(today) {
+		month = today.Format("2006-01-02")
+	}
+
+	var warnings []string
+	for _, code := range []*string{&origin, &destination} {
+		if airport, ok := services.CityToAirport(*code); ok {
+			warnings = append(warnings, fmt.Sprintf("City code %s was resolved to its primary airport %s", *code, airport))
+			*code = airport
+		}
+	}
+
+	var options []services.DateOption
+	live := false
+	if amadeusClient := services.GetAmadeusClient(); amadeusClient != nil {
+		options, err = amadeusClient.SearchCheapestDates(c.Request.Context(), origin, destination, month)
+		if abortIfClientGone(c) {
+			return
+		}
+		switch {
+		case err != nil:
+			logf(c, "⚠️  Amadeus cheapest dates failed for %s→%s: %v — using fallback", origin, destination, err)
+		case len(options) == 0:
+			logf(c, "⚠️  Amadeus returned no dates for %s→%s — using fallback", origin, destination)
+		default:
+			live = true
+		}
+	}
+	if !live {
+		options = services.GenerateCheapestDatesFallback(origin, destination, month)
+		services.RecordFallback(services.ProviderAmadeusFlights)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"origin":      origin,
+		"destination": destination,
+		"nights":      services.CheapestDatesNights,
+		"live":        live,
+		"dates":       options,
+		"warnings":    warnings,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DebugOnly gates debug routes. They're on by default outside release mode; DEBUG_ENDPOINTS=true
+// or false overrides that. Disabled routes answer 404 so they don't advertise themselves.
+func DebugOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		enabled := os.Getenv("GIN_MODE") != "release"
+		switch strings.ToLower(os.Getenv("DEBUG_ENDPOINTS")) {
+		case "true", "1":
+			enabled = true
+		case "false", "0":
+			enabled = false
+		}
+		if !enabled {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Not found"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// PromptPreviewRequest is a search plus optional sample offers; missing offers are filled
+// with the built-in estimated data, exactly as a search without Amadeus would.
+type PromptPreviewRequest struct {
+	SearchRequest
+	Flights   []services.Flight `json:"flights"`
+	Hotels    []services.Hotel  `json:"hotels"`
+	Estimated bool              `json:"estimated"`
+}
+
+// PromptPreviewHandler returns the rendered AI prompt without calling the model.
+func PromptPreviewHandler(c *gin.Context) {
+	var req PromptPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	req.Origin = strings.ToUpper(strings.TrimSpace(req.Origin))
+	req.Destination = strings.ToUpper(strings.TrimSpace(req.Destination))
+	req.ReturnOrigin = strings.ToUpper(strings.TrimSpace(req.ReturnOrigin))
+	if req.Passengers == 0 {
+		req.Passengers = 1
+	}
+	pax := req.passengerMix()
+	if err := pax.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Origin) != 3 || len(req.Destination) != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Airport codes must be exactly 3 characters (e.g. LHR, JFK)"})
+		return
+	}
+	currency, err := services.ParseCurrency(req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	returnOrigin := req.ReturnOrigin
+	if returnOrigin == "" {
+		returnOrigin = req.Destination
+	}
+
+	if len(req.Flights) == 0 {
+		req.Flights = services.GenerateMultiCityFallback(req.Origin, req.Destination, returnOrigin, req.Origin, req.DepartureDate, req.ReturnDate, "", false, pax)
+		req.Flights = services.PriceFlightsIn(req.Flights, currency)
+		req.Estimated = true
+	}
+	if len(req.Hotels) == 0 {
+		req.Hotels = services.PriceHotelsIn(services.GenerateHotelsFallback(req.Destination), currency)
+		req.Estimated = true
+	}
+
+	prompt := services.BuildPrompt(
+		services.NewMoney(req.Budget, currency), req.Origin, req.Destination,
+		req.DepartureDate, req.ReturnDate,
+		pax, req.Flights, req.Hotels, req.Estimated,
+		returnOrigin,
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"prompt": prompt,
+		"length": len(prompt),
+	})
+}
@@ -1,12 +1,26 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 	"tripmind/database"
+	"tripmind/services"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 )
 
+// DownloadHandler serves an itinerary's PDF, named after its traveler, route and date.
+// GET /api/download/:id (?inline=true to open it in the browser instead of saving it)
+// HEAD /api/download/:id answers with the same headers and no body, so clients can check
+// that the PDF exists and how large it is.
 func DownloadHandler(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -16,7 +30,7 @@ func DownloadHandler(c *gin.Context) {
 
 	itinerary, err := database.GetItinerary(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Itinerary not found"})
+		respondLookupError(c, err, "Itinerary not found")
 		return
 	}
 
@@ -25,24 +39,264 @@ func DownloadHandler(c *gin.Context) {
 		return
 	}
 
+	filename := defaultPDFFilename
+	if search, err := database.GetSearch(itinerary.SearchID); err == nil {
+		filename = pdfFilename(itinerary.TravelerName, search.Origin, search.Destination, search.DepartureDate)
+	}
+
 	c.Header("Content-Type", "application/pdf")
-	c.Header("Content-Disposition", "attachment; filename=tripmind-itinerary.pdf")
+	c.Header("Content-Disposition", contentDisposition(pdfDisposition(c), filename))
 	c.Header("Cache-Control", "no-store")
+	if c.Request.Method == http.MethodHead {
+		c.Header("Content-Length", strconv.Itoa(len(itinerary.PDFData)))
+		c.Status(http.StatusOK)
+		return
+	}
 	c.Data(http.StatusOK, "application/pdf", itinerary.PDFData)
 }
 
+const defaultPDFFilename = "tripmind-itinerary.pdf"
+
+// maxZipItineraries caps how many PDFs go into one download-all archive.
+const maxZipItineraries = 20
+
+// DownloadAllHandler zips every generated PDF of a search (newest first, up to maxZipItineraries).
+func DownloadAllHandler(c *gin.Context) {
+	search, err := database.GetSearch(c.Param("id"))
+	if err != nil {
+		respondLookupError(c, err, "Search session not found")
+		return
+	}
+
+	itineraries, err := database.ListItinerariesWithPDF(search.ID, maxZipItineraries)
+	if err != nil {
+		logf(c, "❌ Failed to list itineraries for %s: %v", search.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load itineraries"})
+		return
+	}
+	if len(itineraries) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No PDFs have been generated for this search"})
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	used := map[string]int{}
+	for _, itin := range itineraries {
+		name := pdfFilename(itin.TravelerName, search.Origin, search.Destination, search.DepartureDate)
+		// Same traveler + route + date would collide; number the repeats.
+		if n := used[name]; n > 0 {
+			used[name]++
+			name = fmt.Sprintf("%s_%d.pdf", strings.TrimSuffix(name, ".pdf"), n+1)
+		} else {
+			used[name] = 1
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: itin.CreatedAt})
+		if err == nil {
+			_, err = w.Write(itin.PDFData)
+		}
+		if err != nil {
+			logf(c, "❌ Failed to build ZIP for %s: %v", search.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build archive"})
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		logf(c, "❌ Failed to build ZIP for %s: %v", search.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build archive"})
+		return
+	}
+
+	zipName := strings.TrimSuffix(pdfFilename("", search.Origin, search.Destination, search.DepartureDate), ".pdf") + ".zip"
+	c.Header("Content-Disposition", contentDisposition("attachment", zipName))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// MergedPDFHandler combines the PDFs generated for a search into one comparison PDF, oldest
+// first, each behind an "Option N of M" separator (up to services.MaxMergedVariants). The
+// stored PDFs are copied as they are, comparisons and older itineraries included.
+func MergedPDFHandler(c *gin.Context) {
+	search, err := database.GetSearch(c.Param("id"))
+	if err != nil {
+		respondLookupError(c, err, "Search session not found")
+		return
+	}
+
+	itineraries, err := database.ListGeneratedItineraries(search.ID, services.MaxMergedVariants)
+	if err != nil {
+		logf(c, "❌ Failed to list itineraries for %s: %v", search.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load itineraries"})
+		return
+	}
+
+	variants := make([]services.MergedVariant, len(itineraries))
+	for i, itin := range itineraries {
+		variants[i] = services.MergedVariant{
+			ID:           itin.ID,
+			PDF:          itin.PDFData,
+			TravelerName: itin.TravelerName,
+			CreatedAt:    itin.CreatedAt,
+			Summary:      selectionSummary(search, &itin),
+		}
+	}
+	if len(variants) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No PDFs have been generated for this search"})
+		return
+	}
+
+	pdfBytes, err := services.GenerateMergedPDF(search.Destination, variants)
+	if err != nil {
+		logf(c, "❌ Merged PDF generation failed for %s: %v", search.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate merged PDF"})
+		return
+	}
+
+	name := strings.TrimSuffix(pdfFilename("", search.Origin, search.Destination, search.DepartureDate), ".pdf") + "_options.pdf"
+	c.Header("Content-Disposition", contentDisposition(pdfDisposition(c), name))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// selectionSummary is the flight and hotel an itinerary was generated for, for its separator
+// page in the merged PDF, or nil when it has no stored selection.
+func selectionSummary(search *database.Search, itin *database.Itinerary) *services.PDFData {
+	flight, hotel, ok := storedSelection(itin)
+	if !ok {
+		return nil
+	}
+	data := itineraryPDFData(search, flight, hotel, itin.TravelerName, itin.AISummary)
+	return &data
+}
+
+// pdfFilename builds e.g. "Ivan_TAS-IST_2025-06-10.pdf" so saved itineraries don't collide.
+// Falls back to the generic name when the route or date is missing.
+func pdfFilename(travelerName, origin, destination, departureDate string) string {
+	if origin == "" || destination == "" || departureDate == "" {
+		return defaultPDFFilename
+	}
+	parts := []string{}
+	if name := sanitizeFilenamePart(travelerName); name != "" {
+		parts = append(parts, name)
+	}
+	parts = append(parts,
+		sanitizeFilenamePart(origin)+"-"+sanitizeFilenamePart(destination),
+		sanitizeFilenamePart(departureDate))
+	return strings.Join(parts, "_") + ".pdf"
+}
+
+// sanitizeFilenamePart keeps letters, digits and dashes (any script) and collapses
+// everything else into single underscores.
+func sanitizeFilenamePart(s string) string {
+	var b strings.Builder
+	lastUnderscore := false
+	for _, r := range strings.TrimSpace(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+			b.WriteRune(r)
+			lastUnderscore = false
+		} else if !lastUnderscore {
+			b.WriteRune('_')
+			lastUnderscore = true
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+// pdfDisposition is "inline" for ?inline=true, so a browser tab shows the PDF in its
+// viewer, and "attachment" (a download) otherwise.
+func pdfDisposition(c *gin.Context) string {
+	if c.Query("inline") == "true" {
+		return "inline"
+	}
+	return "attachment"
+}
+
+// contentDisposition sets an ASCII filename for old clients plus an RFC 5987
+// filename* so non-ASCII traveler names survive intact.
+func contentDisposition(disposition, filename string) string {
+	ascii := make([]rune, 0, len(filename))
+	for _, r := range filename {
+		if r < 0x80 {
+			ascii = append(ascii, r)
+		} else {
+			ascii = append(ascii, '_')
+		}
+	}
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, string(ascii), rfc5987Encode(filename))
+}
+
+func rfc5987Encode(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+			strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// deepHealthTimeout bounds the provider checks of /api/health?deep=true.
+const deepHealthTimeout = 5 * time.Second
+
+// HealthHandler is the cheap probe the load balancer hits: it only pings the database.
+// ?deep=true also checks Amadeus and HuggingFace concurrently and reports each dependency's
+// status and latency; status is "degraded" when any of them errors.
 func HealthHandler(c *gin.Context) {
 	db := database.DB
 	dbStatus := "ok"
+	start := time.Now()
 	if db == nil {
 		dbStatus = "not initialized"
 	} else if err := db.Ping(); err != nil {
 		dbStatus = "error: " + err.Error()
 	}
+	dbLatency := time.Since(start)
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"status":   "ok",
 		"service":  "TripMind API",
 		"database": dbStatus,
-	})
+		"circuits": services.CircuitSnapshot(),
+	}
+	if c.Query("deep") != "true" {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), deepHealthTimeout)
+	defer cancel()
+
+	aiClient := services.GetAIClient()
+	var amadeus, ai services.DependencyHealth
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		amadeus = services.GetAmadeusClient().CheckHealth(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		ai = aiClient.CheckHealth(ctx)
+	}()
+	wg.Wait()
+
+	dbHealth := services.DependencyHealth{Status: services.HealthOK, LatencyMS: dbLatency.Milliseconds()}
+	if dbStatus != "ok" {
+		dbHealth.Status, dbHealth.Detail = services.HealthError, dbStatus
+	}
+	deps := map[string]services.DependencyHealth{
+		"database":          dbHealth,
+		"amadeus":           amadeus,
+		aiClient.Provider(): ai,
+	}
+	for _, d := range deps {
+		if d.Status == services.HealthError {
+			resp["status"] = "degraded"
+		}
+	}
+	resp["dependencies"] = deps
+	c.JSON(http.StatusOK, resp)
 }
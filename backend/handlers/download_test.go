@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"tripmind/database"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// pdfPage matches a page object, not the /Pages tree or imported templates.
+var pdfPage = regexp.MustCompile(`/Type /Page\b[^s]`)
+
+func countPDFPages(pdf []byte) int {
+	return len(pdfPage.FindAll(pdf, -1))
+}
+
+func TestMergedPDFHandlerCopiesStoredPDFs(t *testing.T) {
+	searchID, flights, hotels := seedSearch(t)
+
+	// A selection with a cover page and a day-by-day schedule, a trimmed-down selection and
+	// a comparison, all through the generate endpoint.
+	var stored [][]byte
+	for _, body := range []gin.H{
+		{"flight_id": flights[0].ID, "hotel_id": hotels[0].ID, "cover_page": true, "day_by_day": true},
+		{"flight_id": flights[1].ID, "hotel_id": hotels[1].ID, "sections": []string{"traveler", "flight"}},
+		{"compare": true},
+	} {
+		body["search_id"], body["traveler_name"] = searchID, "Ivan Petrov"
+		w := postGenerate(t, body)
+		var resp GenerateResponse
+		if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &resp) != nil {
+			t.Fatalf("generate %v: %d %s", body, w.Code, w.Body)
+		}
+		itin, err := database.GetItinerary(resp.ItineraryID)
+		if err != nil {
+			t.Fatalf("loading itinerary: %v", err)
+		}
+		stored = append(stored, itin.PDFData)
+	}
+
+	// An itinerary from before selections were stored.
+	legacy, err := services.GeneratePDFBytes(itineraryPDFData(&database.Search{Origin: "TAS", Destination: "IST",
+		DepartureDate: "2026-11-10", ReturnDate: "2026-11-17", NumNights: 7, Passengers: 1}, flights[2], hotels[2], "Old Traveler", ""))
+	if err != nil {
+		t.Fatalf("rendering legacy PDF: %v", err)
+	}
+	if err := database.SaveItinerary(&database.Itinerary{ID: uuid.New().String(), SearchID: searchID,
+		FlightsJSON: "[]", HotelsJSON: "[]", PDFData: legacy, TravelerName: "Old Traveler"}); err != nil {
+		t.Fatalf("saving legacy itinerary: %v", err)
+	}
+	stored = append(stored, legacy)
+
+	router := gin.New()
+	router.GET("/api/search/:id/merged", MergedPDFHandler)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search/"+searchID+"/merged", nil))
+	if w.Code != http.StatusOK || !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF")) {
+		t.Fatalf("got %d %.200s, want a PDF", w.Code, w.Body)
+	}
+
+	// Every stored page once, plus one separator per itinerary.
+	want := len(stored)
+	for _, pdf := range stored {
+		want += countPDFPages(pdf)
+	}
+	if got := countPDFPages(w.Body.Bytes()); got != want {
+		t.Errorf("merged PDF has %d pages, want %d (%d itineraries)", got, want, len(stored))
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"tripmind/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondLookupError answers a failed DB read: 404 with notFoundMsg for a genuine miss,
+// 500 for anything else so transient database failures aren't reported as "not found".
+func respondLookupError(c *gin.Context, err error, notFoundMsg string) {
+	if errors.Is(err, database.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFoundMsg})
+		return
+	}
+	logf(c, "❌ Database read failed: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error, please try again"})
+}
+
+// statusClientClosedRequest is nginx's non-standard 499: the client went away before the
+// response was ready. Nobody reads it, but it keeps access logs honest.
+const statusClientClosedRequest = 499
+
+// abortIfClientGone ends the request with 499 when the client disconnected (its context was
+// cancelled), so handlers stop before doing more work for nobody.
+func abortIfClientGone(c *gin.Context) bool {
+	if c.Request.Context().Err() == nil {
+		return false
+	}
+	logf(c, "ℹ️  Client disconnected — abandoning request")
+	c.AbortWithStatus(statusClientClosedRequest)
+	return true
+}
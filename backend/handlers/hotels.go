@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HotelRatesHandler returns every bookable rate (board type, refundability, price) for one hotel.
+// GET /api/hotels/:id/rates?check_in=YYYY-MM-DD&check_out=YYYY-MM-DD&adults=N&currency=EUR
+func HotelRatesHandler(c *gin.Context) {
+	hotelID := strings.ToUpper(strings.TrimSpace(c.Param("id")))
+	checkIn := c.Query("check_in")
+	checkOut := c.Query("check_out")
+
+	inDate, err := time.Parse("2006-01-02", checkIn)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid check_in date format. Use YYYY-MM-DD"})
+		return
+	}
+	outDate, err := time.Parse("2006-01-02", checkOut)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid check_out date format. Use YYYY-MM-DD"})
+		return
+	}
+	if !outDate.After(inDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "check_out must be after check_in"})
+		return
+	}
+
+	adults, err := strconv.Atoi(c.DefaultQuery("adults", "1"))
+	if err != nil || adults <= 0 {
+		adults = 1
+	}
+
+	currency, err := services.ParseCurrency(c.Query("currency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	amadeusClient := services.GetAmadeusClient()
+	if amadeusClient == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Live hotel rates are not available"})
+		return
+	}
+
+	hotel, err := amadeusClient.GetHotelRates(c.Request.Context(), hotelID, checkIn, checkOut, adults, currency)
+	if abortIfClientGone(c) {
+		return
+	}
+	if err != nil {
+		logf(c, "⚠️  Amadeus hotel rates failed for %s: %v", hotelID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Could not fetch rates for this hotel"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hotel)
+}
@@ -2,27 +2,52 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
-	"time"
+	"os"
+	"slices"
+	"strings"
 	"tripmind/database"
 	"tripmind/services"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type GenerateRequest struct {
-	SearchID            string `json:"search_id" binding:"required"`
+	SearchID string `json:"search_id" binding:"required"`
+	// FlightID and HotelID select offers by the id the search returned them with.
+	FlightID string `json:"flight_id,omitempty"`
+	HotelID  string `json:"hotel_id,omitempty"`
+	// Deprecated: select by flight_id/hotel_id. The indices are only used when the IDs are
+	// omitted, and will be removed in the next release.
 	SelectedFlightIndex int    `json:"selected_flight_index"`
 	SelectedHotelIndex  int    `json:"selected_hotel_index"`
 	TravelerName        string `json:"traveler_name"`
+	// Optional subset of PDF blocks to render; defaults to all (see services.PDFSections)
+	Sections []string `json:"sections,omitempty"`
+	// Optional title page before the itinerary details
+	CoverPage bool `json:"cover_page,omitempty"`
+	// Optional dated day-by-day schedule, with suggested activities on the full days
+	DayByDay bool `json:"day_by_day,omitempty"`
+	// Compare renders the search's top CompareTop flights and hotels side by side instead of
+	// one selection; the selected indices, sections and cover page are ignored.
+	Compare    bool `json:"compare,omitempty"`
+	CompareTop int  `json:"compare_top,omitempty"`
+	// Optional address to email the PDF to, when SMTP is configured
+	Email string `json:"email,omitempty"`
 }
 
 type GenerateResponse struct {
-	ItineraryID string `json:"itinerary_id"`
-	PDFURL      string `json:"pdf_url"`
-	Message     string `json:"message"`
+	ItineraryID string   `json:"itinerary_id"`
+	PDFURL      string   `json:"pdf_url"`
+	Message     string   `json:"message"`
+	Warnings    []string `json:"warnings,omitempty"`
+	// Cost is the selection's price for the whole party and per traveler; comparisons have none.
+	Cost *services.CostBreakdown `json:"cost,omitempty"`
 }
 
 func GenerateHandler(c *gin.Context) {
@@ -32,15 +57,29 @@ func GenerateHandler(c *gin.Context) {
 		return
 	}
 
+	for i, name := range req.Sections {
+		req.Sections[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	if err := services.ValidatePDFSections(req.Sections); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TravelerName = normalizeTravelerName(req.TravelerName)
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email != "" && !services.ValidEmail(req.Email) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+
 	search, err := database.GetSearch(req.SearchID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Search session not found"})
+		respondLookupError(c, err, "Search session not found")
 		return
 	}
 
 	itinerary, err := database.GetItineraryBySearchID(req.SearchID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Itinerary data not found"})
+		respondLookupError(c, err, "Itinerary data not found")
 		return
 	}
 
@@ -56,72 +95,404 @@ func GenerateHandler(c *gin.Context) {
 		return
 	}
 
+	if len(flights) == 0 || len(hotels) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "This search has no cached flights or hotels to choose from"})
+		return
+	}
+
+	if req.Compare {
+		generateComparison(c, req, search, itinerary, flights, hotels)
+		return
+	}
+
+	// IDs win over indices; unknown IDs and out-of-range indices (negative included) are
+	// a client bug: reject them, or with STRICT_SELECTION=false fall back to the first
+	// option and say so. An omitted index is 0, the first option, which is always in range here.
+	strict := strings.ToLower(os.Getenv("STRICT_SELECTION")) != "false"
+	var warnings []string
+	if (req.FlightID == "" && flights[0].ID != "") || (req.HotelID == "" && hotels[0].ID != "") {
+		warnings = append(warnings, "selected_flight_index and selected_hotel_index are deprecated; select by flight_id and hotel_id")
+	}
+	if req.FlightID != "" {
+		if req.SelectedFlightIndex = services.FindFlight(flights, req.FlightID); req.SelectedFlightIndex < 0 {
+			msg := fmt.Sprintf("flight_id %q is not in this search", req.FlightID)
+			if strict {
+				c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+				return
+			}
+			warnings = append(warnings, msg+"; used the first flight")
+			req.SelectedFlightIndex = 0
+		}
+	}
+	if req.HotelID != "" {
+		if req.SelectedHotelIndex = services.FindHotel(hotels, req.HotelID); req.SelectedHotelIndex < 0 {
+			msg := fmt.Sprintf("hotel_id %q is not in this search", req.HotelID)
+			if strict {
+				c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+				return
+			}
+			warnings = append(warnings, msg+"; used the first hotel")
+			req.SelectedHotelIndex = 0
+		}
+	}
 	if req.SelectedFlightIndex < 0 || req.SelectedFlightIndex >= len(flights) {
+		msg := fmt.Sprintf("selected_flight_index %d is out of range (have %d flights)", req.SelectedFlightIndex, len(flights))
+		if strict {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		warnings = append(warnings, msg+"; used 0")
 		req.SelectedFlightIndex = 0
 	}
 	if req.SelectedHotelIndex < 0 || req.SelectedHotelIndex >= len(hotels) {
+		msg := fmt.Sprintf("selected_hotel_index %d is out of range (have %d hotels)", req.SelectedHotelIndex, len(hotels))
+		if strict {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		warnings = append(warnings, msg+"; used 0")
 		req.SelectedHotelIndex = 0
 	}
 
+	// Links are stored with the offers, so /book and later downloads reuse them
+	addBookingLinks(search, &flights[req.SelectedFlightIndex], &hotels[req.SelectedHotelIndex])
+	selectedFlight := flights[req.SelectedFlightIndex]
+	selectedHotel := hotels[req.SelectedHotelIndex]
+
+	// A flight in EUR plus a hotel in USD has no meaningful total — refuse rather than mislabel it.
+	if !services.SameCurrency(selectedFlight.Currency, selectedHotel.Currency) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Selected flight (" + selectedFlight.Currency + ") and hotel (" + selectedHotel.Currency + ") are priced in different currencies",
+		})
+		return
+	}
+
+	// Identical requests (double clicks, retries) are serialized so only one of them renders;
+	// the rest find its PDF.
+	lockKey := fmt.Sprintf("generate:%s:%d:%d:%s", req.SearchID, req.SelectedFlightIndex, req.SelectedHotelIndex, req.TravelerName)
+	if err := database.WithItineraryLock(lockKey, func() error {
+		generateSelection(c, req, search, itinerary, flights, hotels, warnings)
+		return nil
+	}); err != nil {
+		logf(c, "❌ Failed to lock generation of %s: %v", lockKey, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF"})
+	}
+}
+
+// generateSelection renders the PDF of one flight+hotel selection and stores it, or
+// answers with the stored PDF when this selection, traveler and render options were
+// already generated.
+func generateSelection(c *gin.Context, req GenerateRequest, search *database.Search, itinerary *database.Itinerary, flights []services.Flight, hotels []services.Hotel, warnings []string) {
+	options := pdfOptionsKey(req)
+	existing, err := database.FindGeneratedItinerary(req.SearchID, req.SelectedFlightIndex, req.SelectedHotelIndex, req.TravelerName)
+	if err == nil && existing.PDFOptions == options && len(existing.PDFData) > 0 {
+		logf(c, "ℹ️  Reusing itinerary %s for an identical generate request", existing.ID)
+		respondGenerated(c, search, req.Email, existing, warnings, "PDF already generated")
+		return
+	}
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		logf(c, "⚠️  Failed to look up an earlier itinerary — generating anew: %v", err)
+	}
+
 	selectedFlight := flights[req.SelectedFlightIndex]
 	selectedHotel := hotels[req.SelectedHotelIndex]
+	flightsJSON, _ := json.Marshal(flights)
+	hotelsJSON, _ := json.Marshal(hotels)
 
-	depDate, _ := time.Parse("2006-01-02", search.DepartureDate)
-	retDate, _ := time.Parse("2006-01-02", search.ReturnDate)
-	numNights := int(retDate.Sub(depDate).Hours() / 24)
+	pdfData := itineraryPDFData(search, selectedFlight, selectedHotel, req.TravelerName, itinerary.AISummary)
+	pdfData.Sections = req.Sections
+	pdfData.CoverPage = req.CoverPage
+	pdfData.DayByDay = req.DayByDay
+	if req.DayByDay || len(req.Sections) == 0 || slices.Contains(req.Sections, services.SectionActivities) {
+		pdfData.Activities = nearbyActivities(c, selectedHotel)
+	}
+	if len(req.Sections) == 0 || slices.Contains(req.Sections, services.SectionOverview) {
+		pdfData.Weather = services.TripWeather(search.Destination, selectedHotel, search.DepartureDate)
+	}
 
-	passengers := search.Passengers
-	if passengers <= 0 {
-		passengers = 1
+	pdfBytes, err := services.GeneratePDFBytes(pdfData)
+	if err != nil {
+		logf(c, "❌ PDF generation failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF"})
+		return
 	}
 
-	// Total = (flight price per person × passengers) + (hotel per night × nights)
-	// Flight price from Amadeus is already the full round-trip price per person.
-	totalCost := selectedFlight.Price*float64(passengers) + selectedHotel.Price*float64(numNights)
+	saveGeneratedPDF(c, search, req.Email, &database.Itinerary{
+		ID:           uuid.New().String(),
+		SearchID:     req.SearchID,
+		FlightsJSON:  string(flightsJSON),
+		HotelsJSON:   string(hotelsJSON),
+		AISummary:    itinerary.AISummary,
+		PDFData:      pdfBytes,
+		TravelerName: req.TravelerName,
 
-	pdfData := services.PDFData{
+		SelectedFlightIndex: &req.SelectedFlightIndex,
+		SelectedHotelIndex:  &req.SelectedHotelIndex,
+		PDFOptions:          options,
+	}, warnings)
+}
+
+// generateComparison answers a compare request: one PDF of the search's top offers,
+// stored without a selection so /book and the merged PDF skip it.
+func generateComparison(c *gin.Context, req GenerateRequest, search *database.Search, itinerary *database.Itinerary, flights []services.Flight, hotels []services.Hotel) {
+	pdfBytes, err := services.GenerateComparisonPDFBytes(services.ComparisonPDFData{
 		TravelerName:  req.TravelerName,
 		Origin:        search.Origin,
 		Destination:   search.Destination,
 		DepartureDate: search.DepartureDate,
 		ReturnDate:    search.ReturnDate,
-		Flight:        selectedFlight,
-		Hotel:         selectedHotel,
-		NumNights:     numNights,
-		Passengers:    passengers,
-		TotalCost:     totalCost,
-		AISummary:     itinerary.AISummary,
-	}
-
-	pdfBytes, err := services.GeneratePDFBytes(pdfData)
+		NumNights:     search.NumNights,
+		Passengers:    searchPassengers(search),
+		Flights:       flights,
+		Hotels:        hotels,
+		Top:           req.CompareTop,
+	})
 	if err != nil {
-		log.Printf("❌ PDF generation failed: %v", err)
+		logf(c, "❌ Comparison PDF generation failed: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate PDF"})
 		return
 	}
 
-	newID := uuid.New().String()
-	newItin := &database.Itinerary{
-		ID:           newID,
+	saveGeneratedPDF(c, search, req.Email, &database.Itinerary{
+		ID:           uuid.New().String(),
 		SearchID:     req.SearchID,
 		FlightsJSON:  itinerary.FlightsJSON,
 		HotelsJSON:   itinerary.HotelsJSON,
 		AISummary:    itinerary.AISummary,
 		PDFData:      pdfBytes,
 		TravelerName: req.TravelerName,
-	}
+	}, nil)
+}
 
-	if err := database.SaveItinerary(newItin); err != nil {
-		log.Printf("❌ Failed to save itinerary with PDF: %v", err)
+// pdfOptionsKey describes the render options of a generate request, so a stored PDF is only
+// reused for the same ones.
+func pdfOptionsKey(req GenerateRequest) string {
+	sections := slices.Clone(req.Sections)
+	slices.Sort(sections)
+	return fmt.Sprintf("sections=%s;cover=%t;day_by_day=%t", strings.Join(sections, ","), req.CoverPage, req.DayByDay)
+}
+
+// saveGeneratedPDF stores a newly generated itinerary PDF, queues its notification (and
+// email, if one was given) and answers with the download URL. A selection overwrites the
+// itinerary already generated for it, keeping its ID.
+func saveGeneratedPDF(c *gin.Context, search *database.Search, email string, newItin *database.Itinerary, warnings []string) {
+	save := database.SaveItinerary
+	if newItin.SelectedFlightIndex != nil {
+		save = database.UpsertGeneratedItinerary
+	}
+	if err := save(newItin); err != nil {
+		logf(c, "❌ Failed to save itinerary with PDF: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save generated PDF"})
 		return
 	}
 
-	log.Printf("✅ PDF generated for itinerary %s (%d bytes)", newID, len(pdfBytes))
+	newID := newItin.ID
+	logf(c, "✅ PDF generated for itinerary %s (%d bytes)", newID, len(newItin.PDFData))
 
+	// Queued rather than sent inline — delivery happens in the notification worker
+	if err := services.Notify(services.KindItineraryGenerated+":"+newID, services.KindItineraryGenerated, gin.H{
+		"itinerary_id": newID,
+		"search_id":    newItin.SearchID,
+		"pdf_url":      "/api/download/" + newID,
+	}); err != nil {
+		logf(c, "⚠️  Failed to queue notification for itinerary %s: %v", newID, err)
+	}
+	respondGenerated(c, search, email, newItin, warnings, "PDF generated successfully")
+}
+
+// respondGenerated queues the email, if one was given, and answers with the download URL.
+func respondGenerated(c *gin.Context, search *database.Search, email string, itin *database.Itinerary, warnings []string, message string) {
+	if email != "" {
+		warnings = append(warnings, queueItineraryEmail(c, search, itin, email)...)
+	}
 	c.JSON(http.StatusOK, GenerateResponse{
-		ItineraryID: newID,
-		PDFURL:      "/api/download/" + newID,
-		Message:     "PDF generated successfully",
+		ItineraryID: itin.ID,
+		PDFURL:      "/api/download/" + itin.ID,
+		Message:     message,
+		Warnings:    warnings,
+		Cost:        selectionCost(search, itin),
 	})
-}
\ No newline at end of file
+}
+
+// selectionCost prices an itinerary's stored selection, or is nil when it has none.
+func selectionCost(search *database.Search, itin *database.Itinerary) *services.CostBreakdown {
+	flight, hotel, ok := storedSelection(itin)
+	if !ok {
+		return nil
+	}
+	cost := services.NewCostBreakdown(flight, hotel, search.NumNights, searchPassengers(search))
+	return &cost
+}
+
+// storedSelection is the flight and hotel an itinerary was generated for; ok is false when
+// it has no stored selection or its cached offers don't hold it.
+func storedSelection(itin *database.Itinerary) (flight services.Flight, hotel services.Hotel, ok bool) {
+	if itin.SelectedFlightIndex == nil || itin.SelectedHotelIndex == nil {
+		return flight, hotel, false
+	}
+	var flights []services.Flight
+	var hotels []services.Hotel
+	if json.Unmarshal([]byte(itin.FlightsJSON), &flights) != nil || json.Unmarshal([]byte(itin.HotelsJSON), &hotels) != nil {
+		return flight, hotel, false
+	}
+	fi, hi := *itin.SelectedFlightIndex, *itin.SelectedHotelIndex
+	if fi < 0 || fi >= len(flights) || hi < 0 || hi >= len(hotels) {
+		return flight, hotel, false
+	}
+	return flights[fi], hotels[hi], true
+}
+
+// queueItineraryEmail hands the PDF to the notification worker for emailing, returning a
+// warning when it won't be sent.
+func queueItineraryEmail(c *gin.Context, search *database.Search, itin *database.Itinerary, email string) []string {
+	if !services.EmailConfigured() {
+		return []string{"Email delivery isn't configured on this server; download the PDF instead"}
+	}
+	// Keyed by recipient too: a reused itinerary can still go to a new address
+	if err := services.Notify(services.KindItineraryEmail+":"+itin.ID+":"+email, services.KindItineraryEmail, services.ItineraryEmail{
+		ItineraryID:  itin.ID,
+		To:           email,
+		TravelerName: itin.TravelerName,
+		Trip:         fmt.Sprintf("%s → %s, %s – %s", search.Origin, search.Destination, search.DepartureDate, search.ReturnDate),
+		Filename:     pdfFilename(itin.TravelerName, search.Origin, search.Destination, search.DepartureDate),
+	}); err != nil {
+		logf(c, "⚠️  Failed to queue email for itinerary %s: %v", itin.ID, err)
+		return []string{"The PDF couldn't be queued for email; download it instead"}
+	}
+	logf(c, "📧 Itinerary %s queued for email", itin.ID)
+	return nil
+}
+
+// itineraryPDFData prices a flight+hotel selection for a search's passengers and nights.
+func itineraryPDFData(search *database.Search, flight services.Flight, hotel services.Hotel, travelerName, aiSummary string) services.PDFData {
+	passengers := searchPassengers(search)
+
+	addBookingLinks(search, &flight, &hotel)
+
+	// Total = round-trip flight price (already for every passenger) + (hotel per night × nights)
+	totalCost := services.NewCostBreakdown(flight, hotel, search.NumNights, passengers).Total
+
+	return services.PDFData{
+		TravelerName:  travelerName,
+		Origin:        search.Origin,
+		Destination:   search.Destination,
+		DepartureDate: search.DepartureDate,
+		ReturnDate:    search.ReturnDate,
+		Flight:        flight,
+		Hotel:         hotel,
+		NumNights:     search.NumNights,
+		Passengers:    passengers,
+		TotalCost:     totalCost,
+		AISummary:     aiSummary,
+		IsEstimated:   flight.Estimated || hotel.Estimated,
+	}
+}
+
+// addBookingLinks fills in deep links for a flight and hotel the providers gave none for.
+func addBookingLinks(search *database.Search, flight *services.Flight, hotel *services.Hotel) {
+	if flight.BookingLink == "" {
+		flight.BookingLink = services.FlightBookingLink(*flight, search.Origin, search.Destination, search.DepartureDate, search.ReturnDate)
+	}
+	if hotel.BookingLink == "" {
+		hotel.BookingLink = services.HotelBookingLink(*hotel, search.DepartureDate, search.ReturnDate, search.Passengers)
+	}
+}
+
+// nearbyActivities looks up activities around the chosen hotel. It returns nil when the
+// hotel has no coordinates or the lookup fails, leaving the PDF to the destination's
+// built-in highlights.
+func nearbyActivities(c *gin.Context, hotel services.Hotel) []services.Activity {
+	amadeusClient := services.GetAmadeusClient()
+	if amadeusClient == nil || !hotel.HasLocation() {
+		return nil
+	}
+	activities, err := amadeusClient.SearchActivities(c.Request.Context(), hotel.Latitude, hotel.Longitude)
+	if err != nil {
+		logf(c, "⚠️  Amadeus activities search failed: %v — using highlights", err)
+		services.RecordFallback(services.ProviderAmadeusActivities)
+		return nil
+	}
+	return activities
+}
+
+// maxTravelerNameLen caps the traveler name, in characters, so it fits the PDF header.
+const maxTravelerNameLen = 100
+
+// normalizeTravelerName makes a traveler name safe for the PDF and its filename: control
+// and invisible formatting characters (newlines, bidi overrides…) become spaces, runs of
+// whitespace collapse to one, quotes and backslashes are dropped, and the result is cut to
+// maxTravelerNameLen characters. An empty result leaves the PDF's "Guest Traveler".
+func normalizeTravelerName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r == '"' || r == '\\':
+			return -1
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || r == utf8.RuneError:
+			return ' '
+		}
+		return r
+	}, name)
+	runes := []rune(strings.Join(strings.Fields(cleaned), " "))
+	if len(runes) > maxTravelerNameLen {
+		runes = runes[:maxTravelerNameLen]
+	}
+	return strings.TrimSpace(string(runes))
+}
+
+// searchPassengers is the party a search was priced for, at least one adult.
+func searchPassengers(search *database.Search) services.PassengerMix {
+	passengers := services.PassengerMix{Adults: search.Passengers, Children: search.Children, Infants: search.Infants}
+	if passengers.Adults <= 0 {
+		passengers.Adults = 1
+	}
+	return passengers
+}
+
+// DeleteItineraryHandler soft-deletes an itinerary; it disappears from downloads but can be
+// restored by an admin until the purge job removes it.
+func DeleteItineraryHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := database.SoftDeleteItinerary(id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Itinerary not found"})
+			return
+		}
+		logf(c, "❌ Failed to delete itinerary %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete itinerary"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// EraseItineraryHandler permanently deletes an itinerary and its stored PDF (which carries the
+// traveler's name). Unlike DeleteItineraryHandler nothing is kept for an admin to restore.
+// DELETE /api/admin/itineraries/:id?hard=true
+func EraseItineraryHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := database.DeleteItinerary(id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Itinerary not found"})
+			return
+		}
+		logf(c, "❌ Failed to erase itinerary %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete itinerary"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteSearchHandler permanently deletes a search with all of its itineraries.
+// DELETE /api/search/:id
+func DeleteSearchHandler(c *gin.Context) {
+	id := c.Param("id")
+	if err := database.DeleteSearch(id); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Search session not found"})
+			return
+		}
+		logf(c, "❌ Failed to delete search %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete search"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"tripmind/database"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// TestMain runs the handler tests against a throwaway SQLite database.
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	dir, err := os.MkdirTemp("", "tripmind-handlers")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("DB_DRIVER", "sqlite")
+	os.Setenv("SQLITE_PATH", filepath.Join(dir, "test.db"))
+	database.InitDB()
+
+	code := m.Run()
+	database.DB.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// seedSearch stores a search with estimated flights and hotels to generate from.
+func seedSearch(t *testing.T) (searchID string, flights []services.Flight, hotels []services.Hotel) {
+	t.Helper()
+	flights = services.GenerateFlightsFallback("TAS", "IST", "2026-11-10", "2026-11-17", "", false, services.PassengerMix{Adults: 1})
+	hotels = services.GenerateHotelsFallback("IST")
+	services.AssignOfferIDs(flights, hotels)
+	flightsJSON, _ := json.Marshal(flights)
+	hotelsJSON, _ := json.Marshal(hotels)
+
+	searchID = uuid.New().String()
+	err := database.SaveSearchWithItinerary(&database.Search{
+		ID: searchID, Origin: "TAS", Destination: "IST", DepartureDate: "2026-11-10", ReturnDate: "2026-11-17",
+		Budget: 300000, Passengers: 1, NumNights: 7, Currency: "USD",
+	}, &database.Itinerary{
+		ID: uuid.New().String(), SearchID: searchID, FlightsJSON: string(flightsJSON), HotelsJSON: string(hotelsJSON),
+	})
+	if err != nil {
+		t.Fatalf("seeding search: %v", err)
+	}
+	return searchID, flights, hotels
+}
+
+func postGenerate(t *testing.T, body gin.H) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	router.POST("/api/generate", GenerateHandler)
+	payload, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/generate", bytes.NewReader(payload)))
+	return w
+}
+
+func TestGenerateHandlerSelection(t *testing.T) {
+	searchID, flights, hotels := seedSearch(t)
+
+	tests := []struct {
+		name string
+		body gin.H
+		// warning is what the error (strict) or warning (lenient) says; empty for a valid
+		// selection, which succeeds either way.
+		warning string
+		// selected is the flight and hotel index stored when generation succeeds.
+		selected int
+	}{
+		{"negative flight index", gin.H{"selected_flight_index": -1}, "selected_flight_index -1 is out of range", 0},
+		{"negative hotel index", gin.H{"selected_hotel_index": -2}, "selected_hotel_index -2 is out of range", 0},
+		{"flight index past the end", gin.H{"selected_flight_index": len(flights)}, "is out of range", 0},
+		{"hotel index past the end", gin.H{"selected_hotel_index": len(hotels)}, "is out of range", 0},
+		{"unknown flight_id", gin.H{"flight_id": "fl_unknown", "hotel_id": hotels[0].ID}, `flight_id "fl_unknown" is not in this search`, 0},
+		{"unknown hotel_id", gin.H{"flight_id": flights[0].ID, "hotel_id": "ht_unknown"}, `hotel_id "ht_unknown" is not in this search`, 0},
+		{"omitted index", gin.H{}, "", 0},
+		{"known ids", gin.H{"flight_id": flights[1].ID, "hotel_id": hotels[1].ID}, "", 1},
+	}
+	for _, strict := range []string{"true", "false"} {
+		for _, tt := range tests {
+			t.Run("strict="+strict+"/"+tt.name, func(t *testing.T) {
+				t.Setenv("STRICT_SELECTION", strict)
+				body := gin.H{"search_id": searchID, "traveler_name": "Ivan Petrov"}
+				for k, v := range tt.body {
+					body[k] = v
+				}
+				w := postGenerate(t, body)
+
+				if tt.warning != "" && strict == "true" {
+					var resp struct{ Error string }
+					json.Unmarshal(w.Body.Bytes(), &resp)
+					if w.Code != http.StatusBadRequest || !strings.Contains(resp.Error, tt.warning) {
+						t.Fatalf("got %d %s, want 400 with %q", w.Code, w.Body, tt.warning)
+					}
+					return
+				}
+				if w.Code != http.StatusOK {
+					t.Fatalf("got %d %s, want 200", w.Code, w.Body)
+				}
+				var resp GenerateResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("decoding response: %v", err)
+				}
+				itin, err := database.GetItinerary(resp.ItineraryID)
+				if err != nil {
+					t.Fatalf("loading itinerary %q: %v", resp.ItineraryID, err)
+				}
+				if len(itin.PDFData) == 0 || *itin.SelectedFlightIndex != tt.selected || *itin.SelectedHotelIndex != tt.selected {
+					t.Errorf("stored selection %d/%d (%d PDF bytes), want %d/%d with a PDF",
+						*itin.SelectedFlightIndex, *itin.SelectedHotelIndex, len(itin.PDFData), tt.selected, tt.selected)
+				}
+				warned := false
+				for _, warning := range resp.Warnings {
+					if tt.warning != "" && strings.Contains(warning, tt.warning) {
+						warned = true
+					}
+					if tt.warning == "" && (strings.Contains(warning, "out of range") || strings.Contains(warning, "not in this search")) {
+						t.Errorf("unexpected selection warning %q", warning)
+					}
+				}
+				if tt.warning != "" && !warned {
+					t.Errorf("warnings %q don't mention %q", resp.Warnings, tt.warning)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResolveAirportHandler maps a city or airport code to the airport flights are searched
+// from and the city hotels are searched in.
+// GET /api/locations/:code/airport
+func ResolveAirportHandler(c *gin.Context) {
+	code := strings.ToUpper(strings.TrimSpace(c.Param("code")))
+	if len(code) != 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Location codes must be exactly 3 characters (e.g. LON, LHR)"})
+		return
+	}
+
+	airport, isCity := services.CityToAirport(code)
+	if !isCity {
+		airport = code
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    code,
+		"airport": airport,
+		"city":    services.CityOf(airport),
+		"is_city": isCity,
+	})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID back to the client (and in from a proxy that
+// already assigned one), so a bug report can be matched to its log lines.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID gives each request a UUID, stored in gin's context as "request_id" and in the
+// request context for services.Logf, and logs one line per request with its status.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if _, err := uuid.Parse(id); err != nil {
+			id = uuid.NewString()
+		}
+		info := services.RequestInfo{ID: id, Method: c.Request.Method, Path: c.Request.URL.Path, Start: time.Now()}
+		c.Request = c.Request.WithContext(services.WithRequestInfo(c.Request.Context(), info))
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+
+		c.Next()
+
+		logf(c, "%d %s", c.Writer.Status(), http.StatusText(c.Writer.Status()))
+	}
+}
+
+// logf logs through services.Logf with the request's ID, method, path and latency.
+func logf(c *gin.Context, format string, args ...any) {
+	services.Logf(c.Request.Context(), format, args...)
+}
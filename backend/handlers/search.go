@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"tripmind/database"
 	"tripmind/services"
@@ -19,43 +23,265 @@ type SearchRequest struct {
 	DepartureDate string  `json:"departure_date" binding:"required"`
 	ReturnDate    string  `json:"return_date" binding:"required"`
 	Budget        float64 `json:"budget" binding:"required,gt=0"`
-	Passengers    int     `json:"passengers"`
+	Passengers    int     `json:"passengers"` // adults (defaults to 1)
 	// Optional: if set, the return flight departs from a different city (multi-city)
 	ReturnOrigin string `json:"return_origin,omitempty"`
+	// Optional multi-leg itinerary (2–6 flights, e.g. TAS→IST, IST→CDG, CDG→TAS) searched
+	// instead of the round trip; origin, destination and the dates still set the hotel stay.
+	Legs []services.TripLeg `json:"legs,omitempty"`
+	// Optional travel class: ECONOMY, PREMIUM_ECONOMY, BUSINESS or FIRST (unrestricted if empty)
+	CabinClass string `json:"cabin_class,omitempty"`
+	// Optional: compare the cheapest fare per cabin (defaults to ECONOMY and BUSINESS)
+	MultiCabin bool     `json:"multi_cabin,omitempty"`
+	Cabins     []string `json:"cabins,omitempty"`
+	// Optional HH:MM local departure windows for each leg (e.g. "06:00"–"12:00")
+	DepartTimeFrom string `json:"depart_time_from,omitempty"`
+	DepartTimeTo   string `json:"depart_time_to,omitempty"`
+	ReturnTimeFrom string `json:"return_time_from,omitempty"`
+	ReturnTimeTo   string `json:"return_time_to,omitempty"`
+	// Optional: warn (budget_warning) when the cheapest flight+hotel already exceeds the budget
+	StrictBudget bool `json:"strict_budget,omitempty"`
+	// Optional: only direct flights, live and estimated
+	NonStop bool `json:"non_stop,omitempty"`
+	// Optional: also search other airports of the origin and destination cities (e.g. LGW
+	// and STN for LHR) and merge the offers; round trips only
+	IncludeNearby bool `json:"include_nearby,omitempty"`
+	// Optional: children (2–11) and lap infants travelling with the adults in passengers
+	Children int `json:"children,omitempty"`
+	Infants  int `json:"infants,omitempty"`
+	// Optional ISO 4217 code every price (and the budget) is in; defaults to USD
+	Currency string `json:"currency,omitempty"`
+	// Optional hotel filters: minimum star rating and maximum price per night (in currency).
+	// If no hotel matches, all hotels are returned with a warning.
+	MinHotelRating float64 `json:"min_hotel_rating,omitempty"`
+	MaxHotelPrice  float64 `json:"max_hotel_price,omitempty"`
+	// Optional meal plan for hotel offers: ROOM_ONLY, BREAKFAST, HALF_BOARD, FULL_BOARD or ALL_INCLUSIVE
+	BoardType string `json:"board_type,omitempty"`
+	// Optional flight order: price, duration or stops (provider order if empty)
+	SortFlights string `json:"sort_flights,omitempty"`
+	// Optional AI tuning: summary length in tokens (50–800) and temperature (0.1–1.2);
+	// out-of-range values are clamped.
+	AIMaxTokens   int     `json:"ai_max_tokens,omitempty"`
+	AITemperature float64 `json:"ai_temperature,omitempty"`
+	// Optional result counts (1–50). max_flights is also how many offers Amadeus is asked
+	// for; max_hotels is how many listed hotels are priced per search radius.
+	MaxFlights int `json:"max_flights,omitempty"`
+	MaxHotels  int `json:"max_hotels,omitempty"`
+}
+
+// passengerMix is the request's traveler breakdown, with passengers as the adult count.
+func (r SearchRequest) passengerMix() services.PassengerMix {
+	return services.PassengerMix{Adults: r.Passengers, Children: r.Children, Infants: r.Infants}
+}
+
+// hotelFilter is the request's hotel rating/price filter.
+func (r SearchRequest) hotelFilter() services.HotelFilter {
+	return services.HotelFilter{MinRating: r.MinHotelRating, MaxPrice: r.MaxHotelPrice, BoardType: r.BoardType}
 }
 
 type SearchResponse struct {
-	SearchID     string            `json:"search_id"`
-	Flights      []services.Flight `json:"flights"`
-	Hotels       []services.Hotel  `json:"hotels"`
-	AISummary    string            `json:"ai_summary"`
-	Source       string            `json:"source"` // "live" or "estimated"
-	ReturnOrigin string            `json:"return_origin,omitempty"`
+	SearchID     string             `json:"search_id"`
+	Flights      []services.Flight  `json:"flights"`
+	Hotels       []services.Hotel   `json:"hotels"`
+	AISummary    string             `json:"ai_summary"`
+	Source       string             `json:"source"` // "live", "estimated", or "partial" (one of flights/hotels estimated)
+	ReturnOrigin string             `json:"return_origin,omitempty"`
+	Legs         []services.TripLeg `json:"legs,omitempty"`
+	Warnings     []string           `json:"warnings,omitempty"`
+	// OriginName and DestinationName are the places the codes serve ("Tashkent"), or the
+	// codes themselves when they aren't in the built-in list.
+	OriginName      string `json:"origin_name"`
+	DestinationName string `json:"destination_name"`
+	// AISections splits AISummary into flight/hotel recommendations (or just text, if the model ignored the format).
+	AISections services.AISections `json:"ai_sections"`
+	// TripSummary is computed from the offers, not the AI, so it's present even when the model fails.
+	TripSummary *services.TripSummary `json:"trip_summary,omitempty"`
+	// CabinFares is only set for multi_cabin searches.
+	CabinFares []services.CabinFare `json:"cabin_fares,omitempty"`
+	// FilteredByTime counts live flights dropped by the departure time windows.
+	FilteredByTime int `json:"filtered_by_time,omitempty"`
+	// OriginAirports and DestinationAirports are the airports an include_nearby search covered.
+	OriginAirports      []string `json:"origin_airports,omitempty"`
+	DestinationAirports []string `json:"destination_airports,omitempty"`
+	// HotelRadiusKM is the search radius the live hotels came from (widened when results are sparse).
+	HotelRadiusKM int `json:"hotel_radius_km,omitempty"`
+	// NumNights is the hotel night count stored with the search and used for the PDF total.
+	NumNights int `json:"num_nights"`
+	// BudgetWarning is set for strict_budget searches the budget can't cover.
+	BudgetWarning *services.BudgetWarning `json:"budget_warning,omitempty"`
+	// PriceRounding is the precision every price in the response is rounded to: "whole" or "cents".
+	PriceRounding string `json:"price_rounding"`
+	// FallbackReason says why an estimated search got no live data: no_results,
+	// rate_limited, auth_failed or upstream_error.
+	FallbackReason string `json:"fallback_reason,omitempty"`
 }
 
+// splitSearchResponse is returned for ?response_shape=split; its Flights field
+// shadows the flat one so each flight carries separate outbound/return legs.
+type splitSearchResponse struct {
+	SearchResponse
+	Flights []services.SplitFlight `json:"flights"`
+}
+
+// SearchHandler runs a search and returns it as one JSON response.
+// POST /api/search
 func SearchHandler(c *gin.Context) {
+	runSearch(c, false)
+}
+
+// SearchStreamHandler runs the same search but reports progress as Server-Sent Events:
+// flights_ready and hotels_ready as each finishes, ai_ready with the summary, then done
+// with the full SearchResponse. Invalid requests still get a 400 JSON error.
+// POST /api/search/stream
+func SearchStreamHandler(c *gin.Context) {
+	runSearch(c, true)
+}
+
+func runSearch(c *gin.Context, streaming bool) {
 	var req SearchRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
 		return
 	}
 
+	responseShape := c.DefaultQuery("response_shape", "flat")
+	if responseShape != "flat" && responseShape != "split" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "response_shape must be \"flat\" or \"split\""})
+		return
+	}
+
 	req.Origin = strings.ToUpper(strings.TrimSpace(req.Origin))
 	req.Destination = strings.ToUpper(strings.TrimSpace(req.Destination))
 	req.ReturnOrigin = strings.ToUpper(strings.TrimSpace(req.ReturnOrigin))
 
-	if req.Passengers <= 0 {
+	if req.Passengers == 0 {
 		req.Passengers = 1
 	}
+	pax := req.passengerMix()
+	if err := pax.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	if len(req.Origin) != 3 || len(req.Destination) != 3 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Airport codes must be exactly 3 characters (e.g. LHR, JFK)"})
 		return
 	}
+	for _, code := range []string{req.Origin, req.Destination} {
+		if err := services.CheckAirportCode(c.Request.Context(), code); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	req.CabinClass = strings.ToUpper(strings.TrimSpace(req.CabinClass))
+	if req.CabinClass != "" && !services.IsValidCabin(req.CabinClass) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cabin_class must be one of ECONOMY, PREMIUM_ECONOMY, BUSINESS, FIRST"})
+		return
+	}
+	currency, err := services.ParseCurrency(req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Currency = currency
+	if req.MinHotelRating < 0 || req.MinHotelRating > 5 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_hotel_rating must be between 0 and 5"})
+		return
+	}
+	if req.MaxHotelPrice < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_hotel_price can't be negative"})
+		return
+	}
+	req.BoardType = strings.ToUpper(strings.TrimSpace(req.BoardType))
+	if req.BoardType != "" && !services.IsValidBoardType(req.BoardType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "board_type must be one of " + strings.Join(services.BoardTypes, ", ")})
+		return
+	}
+	hotelFilter := req.hotelFilter()
+	if req.MaxFlights < 0 || req.MaxFlights > services.MaxSearchResults {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("max_flights must be between 1 and %d", services.MaxSearchResults)})
+		return
+	}
+	if req.MaxHotels < 0 || req.MaxHotels > services.MaxSearchResults {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("max_hotels must be between 1 and %d", services.MaxSearchResults)})
+		return
+	}
 	if req.ReturnOrigin != "" && len(req.ReturnOrigin) != 3 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Return origin airport code must be exactly 3 characters"})
 		return
 	}
+	if req.ReturnOrigin != "" {
+		if err := services.CheckAirportCode(c.Request.Context(), req.ReturnOrigin); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if len(req.Legs) > 0 {
+		if req.ReturnOrigin != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Use either legs or return_origin, not both"})
+			return
+		}
+		for i := range req.Legs {
+			req.Legs[i].Origin = strings.ToUpper(strings.TrimSpace(req.Legs[i].Origin))
+			req.Legs[i].Destination = strings.ToUpper(strings.TrimSpace(req.Legs[i].Destination))
+		}
+		if err := services.ValidateTripLegs(req.Legs); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for _, leg := range req.Legs {
+			for _, code := range []string{leg.Origin, leg.Destination} {
+				if err := services.CheckAirportCode(c.Request.Context(), code); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+			}
+		}
+	}
+	if req.IncludeNearby && (len(req.Legs) > 0 || (req.ReturnOrigin != "" && req.ReturnOrigin != req.Destination)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "include_nearby only applies to round trips, not return_origin or legs searches"})
+		return
+	}
+
+	req.SortFlights = strings.ToLower(strings.TrimSpace(req.SortFlights))
+	if err := services.ValidateFlightSort(req.SortFlights); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	departWindow, err := services.ParseTimeWindow(req.DepartTimeFrom, req.DepartTimeTo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Departure time window: " + err.Error()})
+		return
+	}
+	returnWindow, err := services.ParseTimeWindow(req.ReturnTimeFrom, req.ReturnTimeTo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Return time window: " + err.Error()})
+		return
+	}
+
+	var cabins []string
+	if req.MultiCabin {
+		if len(req.Cabins) == 0 {
+			req.Cabins = []string{"ECONOMY", "BUSINESS"}
+		}
+		seen := map[string]bool{}
+		for _, cabin := range req.Cabins {
+			cabin = strings.ToUpper(strings.TrimSpace(cabin))
+			if !services.IsValidCabin(cabin) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown cabin " + cabin + " (use ECONOMY, PREMIUM_ECONOMY, BUSINESS or FIRST)"})
+				return
+			}
+			if !seen[cabin] {
+				seen[cabin] = true
+				cabins = append(cabins, cabin)
+			}
+		}
+		if len(cabins) > services.MaxCabinSearches {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d cabins can be compared", services.MaxCabinSearches)})
+			return
+		}
+	}
 
 	depDate, err := time.Parse("2006-01-02", req.DepartureDate)
 	if err != nil {
@@ -73,6 +299,24 @@ func SearchHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Return date must be after departure date"})
 		return
 	}
+	numNights := database.NightsBetween(req.DepartureDate, req.ReturnDate)
+
+	// City codes (e.g. LON) work for hotels but not flight offers — search their primary airport.
+	var warnings []string
+	codes := []*string{&req.Origin, &req.Destination, &req.ReturnOrigin}
+	for i := range req.Legs {
+		codes = append(codes, &req.Legs[i].Origin, &req.Legs[i].Destination)
+	}
+	resolved := map[string]bool{}
+	for _, code := range codes {
+		if airport, ok := services.CityToAirport(*code); ok {
+			if !resolved[*code] {
+				resolved[*code] = true
+				warnings = append(warnings, fmt.Sprintf("City code %s was resolved to its primary airport %s", *code, airport))
+			}
+			*code = airport
+		}
+	}
 
 	// For multi-city, returnOrigin is the departure airport for the return leg.
 	// If not set, falls back to destination (standard round-trip).
@@ -82,135 +326,575 @@ func SearchHandler(c *gin.Context) {
 	}
 
 	// ── Try Amadeus live data ──────────────────────────────────────────────────
+	// Flights and hotels are fetched in parallel and fall back independently, so a failed
+	// hotel search doesn't turn live flights into estimates (and vice versa).
 	var flights []services.Flight
 	var hotels []services.Hotel
-	isFallback := false
-	source := "live"
+	var flightWarnings, hotelWarnings []string
+	flightsLive, hotelsLive := false, false
+	var flightFallback, hotelFallback string // services.Fallback* reasons
+	filteredByTime := 0
+	hotelRadius := 0
+	var originAirports, destinationAirports []string
 
 	amadeusClient := services.GetAmadeusClient()
+	// Cancelled when the client disconnects, which aborts the in-flight Amadeus calls.
+	ctx := c.Request.Context()
+
+	var stream *searchStream
+	if streaming {
+		stream = startSearchStream(c)
+	}
+
+	fallbackFlights := func() []services.Flight {
+		if len(req.Legs) > 0 {
+			return services.GenerateMultiLegFallback(req.Legs, req.CabinClass, req.NonStop, pax)
+		}
+		return services.GenerateMultiCityFallback(req.Origin, req.Destination, returnOrigin, req.Origin, req.DepartureDate, req.ReturnDate, req.CabinClass, req.NonStop, pax)
+	}
+
+	loadFlights := func() {
+		if amadeusClient == nil {
+			flights = fallbackFlights()
+			flightFallback = services.ClassifyFallback(services.ErrAmadeusNotConfigured)
+			services.RecordFallback(services.ProviderAmadeusFlights)
+			return
+		}
 
-	if amadeusClient != nil {
 		var liveFlights []services.Flight
 		var flightErr error
 
-		if returnOrigin != req.Destination {
-			liveFlights, flightErr = amadeusClient.SearchFlightsMultiCity(
+		if len(req.Legs) > 0 {
+			liveFlights, flightErr = amadeusClient.SearchFlightsMultiLeg(ctx,
+				req.Legs, pax, req.CabinClass, req.NonStop, req.Currency, req.MaxFlights,
+			)
+		} else if req.IncludeNearby {
+			originAirports = amadeusClient.NearbyAirports(ctx, req.Origin)
+			destinationAirports = amadeusClient.NearbyAirports(ctx, req.Destination)
+			liveFlights, flightErr = amadeusClient.SearchFlightsNearby(ctx,
+				originAirports, destinationAirports,
+				req.DepartureDate, req.ReturnDate,
+				pax, req.CabinClass, req.NonStop, req.Currency, req.MaxFlights,
+			)
+		} else if returnOrigin != req.Destination {
+			liveFlights, flightErr = amadeusClient.SearchFlightsMultiCity(ctx,
 				req.Origin, req.Destination,
 				returnOrigin, req.Origin,
 				req.DepartureDate, req.ReturnDate,
-				req.Passengers,
+				pax, req.CabinClass, req.NonStop, req.Currency, req.MaxFlights,
 			)
 		} else {
-			liveFlights, flightErr = amadeusClient.SearchFlights(
+			liveFlights, flightErr = amadeusClient.SearchFlights(ctx,
 				req.Origin, req.Destination,
 				req.DepartureDate, req.ReturnDate,
-				req.Passengers,
+				pax, req.CabinClass, req.NonStop, req.Currency, req.MaxFlights,
 			)
 		}
 
+		if ctx.Err() != nil {
+			return
+		}
+		if flightErr == nil {
+			var dropped int
+			if liveFlights, dropped = services.DropCurrencyMismatchedFlights(liveFlights); dropped > 0 {
+				flightWarnings = append(flightWarnings, fmt.Sprintf("%d flight offer(s) were priced in an unexpected currency and were left out", dropped))
+			}
+			liveFlights, filteredByTime = services.FilterFlightsByTime(liveFlights, departWindow, returnWindow)
+		}
+
 		if flightErr != nil {
-			log.Printf("⚠️  Amadeus flight search failed: %v — using fallback", flightErr)
-			flights = services.GenerateMultiCityFallback(req.Origin, req.Destination, returnOrigin, req.Origin, req.DepartureDate, req.ReturnDate)
-			isFallback = true
+			logf(c, "⚠️  Amadeus flight search failed: %v — using fallback", flightErr)
+			if errors.Is(flightErr, services.ErrRateLimited) {
+				flightWarnings = append(flightWarnings, "The flight provider is rate limiting requests right now; flights are estimated")
+			}
+			flights = fallbackFlights()
+			flightFallback = services.ClassifyFallback(flightErr)
+			services.RecordFallback(services.ProviderAmadeusFlights)
 		} else if len(liveFlights) == 0 {
-			log.Println("⚠️  Amadeus returned 0 flights — using fallback")
-			flights = services.GenerateMultiCityFallback(req.Origin, req.Destination, returnOrigin, req.Origin, req.DepartureDate, req.ReturnDate)
-			isFallback = true
+			logf(c, "⚠️  Amadeus returned 0 flights — using fallback")
+			flights = fallbackFlights()
+			flightFallback = services.FallbackNoResults
+			services.RecordFallback(services.ProviderAmadeusFlights)
 		} else {
 			flights = liveFlights
-			log.Printf("✅ Amadeus: %d live flights found", len(flights))
+			flightsLive = true
+			logf(c, "✅ Amadeus: %d live flights found", len(flights))
 		}
-	} else {
-		flights = services.GenerateMultiCityFallback(req.Origin, req.Destination, returnOrigin, req.Origin, req.DepartureDate, req.ReturnDate)
-		isFallback = true
 	}
 
-	if amadeusClient != nil && !isFallback {
-		liveHotels, err := amadeusClient.SearchHotels(
+	loadHotels := func() {
+		if amadeusClient == nil {
+			hotels = services.GenerateHotelsFallback(req.Destination)
+			hotelFallback = services.ClassifyFallback(services.ErrAmadeusNotConfigured)
+			services.RecordFallback(services.ProviderAmadeusHotels)
+			return
+		}
+
+		liveHotels, radius, err := amadeusClient.SearchHotels(ctx,
 			req.Destination,
 			req.DepartureDate,
 			req.ReturnDate,
-			req.Passengers,
+			req.Passengers, req.Currency, hotelFilter, req.MaxHotels,
 		)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			var dropped int
+			if liveHotels, dropped = services.DropCurrencyMismatchedHotels(liveHotels); dropped > 0 {
+				hotelWarnings = append(hotelWarnings, fmt.Sprintf("%d hotel offer(s) were priced in an unexpected currency and were left out", dropped))
+			}
+		}
 		if err != nil {
-			log.Printf("⚠️  Amadeus hotel search failed: %v — using fallback", err)
+			logf(c, "⚠️  Amadeus hotel search failed: %v — using fallback", err)
+			if errors.Is(err, services.ErrRateLimited) {
+				hotelWarnings = append(hotelWarnings, "The hotel provider is rate limiting requests right now; hotels are estimated")
+			}
 			hotels = services.GenerateHotelsFallback(req.Destination)
-			isFallback = true
+			hotelFallback = services.ClassifyFallback(err)
+			services.RecordFallback(services.ProviderAmadeusHotels)
 		} else if len(liveHotels) == 0 {
-			log.Println("⚠️  Amadeus returned 0 hotels — using fallback")
+			logf(c, "⚠️  Amadeus returned 0 hotels — using fallback")
 			hotels = services.GenerateHotelsFallback(req.Destination)
-			isFallback = true
+			hotelFallback = services.FallbackNoResults
+			services.RecordFallback(services.ProviderAmadeusHotels)
 		} else {
 			hotels = liveHotels
-			log.Printf("✅ Amadeus: %d live hotels found", len(hotels))
+			hotelsLive = true
+			hotelRadius = radius
+			logf(c, "✅ Amadeus: %d live hotels found within %d km", len(hotels), radius)
 		}
-	} else {
-		if hotels == nil {
-			hotels = services.GenerateHotelsFallback(req.Destination)
+	}
+
+	// Each side is finished (priced, filtered, sorted) as soon as it loads, so a stream can
+	// show flights while hotels are still being searched.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		loadFlights()
+		if ctx.Err() != nil {
+			return
+		}
+		// Estimated offers are generated in USD
+		if !flightsLive {
+			flights = services.FitFallbackToTimeWindows(flights, departWindow, returnWindow)
+			flights = services.PriceFlightsIn(flights, req.Currency)
 		}
-		isFallback = true
+		services.SortFlights(flights, req.SortFlights)
+		services.AssignOfferIDs(flights, nil)
+		if req.NonStop && len(flights) == 0 {
+			flightWarnings = append(flightWarnings, "No non-stop flights were found for this route")
+		}
+		stream.send(eventFlightsReady, gin.H{
+			"flights":          shapeFlights(capped(flights, req.MaxFlights, "MAX_FLIGHTS_RETURNED"), responseShape),
+			"live":             flightsLive,
+			"warnings":         flightWarnings,
+			"filtered_by_time": filteredByTime,
+		})
+	}()
+
+	go func() {
+		defer wg.Done()
+		loadHotels()
+		if ctx.Err() != nil {
+			return
+		}
+		if !hotelsLive {
+			hotels = services.PriceHotelsIn(hotels, req.Currency)
+		}
+		// Filtered after pricing so max_hotel_price compares in the request currency.
+		var relaxed bool
+		if hotels, relaxed = services.FilterHotels(hotels, hotelFilter); relaxed {
+			hotelWarnings = append(hotelWarnings, "No hotels matched your hotel filters; showing all hotels")
+		}
+		services.AssignOfferIDs(nil, hotels)
+		stream.send(eventHotelsReady, gin.H{
+			"hotels":          capped(hotels, req.MaxHotels, "MAX_HOTELS_RETURNED"),
+			"live":            hotelsLive,
+			"warnings":        hotelWarnings,
+			"hotel_radius_km": hotelRadius,
+		})
+	}()
+
+	wg.Wait()
+	if abortIfClientGone(c) {
+		return
 	}
+	warnings = append(warnings, flightWarnings...)
+	warnings = append(warnings, hotelWarnings...)
 
-	if isFallback {
+	// isFallback means some of the data is estimated; source says which part.
+	isFallback := !flightsLive || !hotelsLive
+	source := "live"
+	switch {
+	case !flightsLive && !hotelsLive:
 		source = "estimated"
+	case isFallback:
+		source = "partial"
+	}
+
+	// Cabin comparison reuses the round-trip search; multi-city and multi-leg routes and
+	// fallback searches get estimates based on the flights already found.
+	var cabinFares []services.CabinFare
+	if len(cabins) > 0 {
+		cabinClient := amadeusClient
+		if !flightsLive || returnOrigin != req.Destination || len(req.Legs) > 0 {
+			cabinClient = nil
+		}
+		cabinFares = services.CompareCabinFares(ctx, cabinClient, req.Origin, req.Destination,
+			req.DepartureDate, req.ReturnDate, pax, req.NonStop, req.Currency, cabins, flights)
+		if abortIfClientGone(c) {
+			return
+		}
 	}
 
+	// Cap what reaches the client (and the cached list the PDF indexes into), live or
+	// estimated, independently of how many offers were requested upstream.
+	flights = capped(flights, req.MaxFlights, "MAX_FLIGHTS_RETURNED")
+	hotels = capped(hotels, req.MaxHotels, "MAX_HOTELS_RETURNED")
+
+	budget := services.NewMoney(req.Budget, req.Currency)
+
 	// ── AI Recommendations ────────────────────────────────────────────────────
 	aiClient := services.GetAIClient()
-	aiSummary, err := aiClient.GetRecommendations(
-		req.Budget, req.Origin, req.Destination,
+	aiSummary, err := aiClient.GetRecommendations(ctx,
+		budget, req.Origin, req.Destination,
 		req.DepartureDate, req.ReturnDate,
-		req.Passengers, flights, hotels, isFallback,
+		pax, flights, hotels, isFallback,
 		returnOrigin,
+		services.GenerationOptions{MaxTokens: req.AIMaxTokens, Temperature: req.AITemperature},
 	)
 	if err != nil {
-		log.Printf("⚠️  AI recommendation failed: %v — using smart built-in summary", err)
+		logf(c, "⚠️  AI recommendation failed: %v — using smart built-in summary", err)
+		services.RecordFallback(aiClient.Provider())
 		aiSummary = services.SmartFallbackRecommendation(
-			req.Budget, req.Origin, req.Destination,
+			budget, req.Origin, req.Destination,
 			req.DepartureDate, req.ReturnDate,
-			req.Passengers, flights, hotels,
+			pax, flights, hotels,
 			returnOrigin,
 		)
 	}
+	if isFallback {
+		aiSummary = services.WithEstimateNotice(aiSummary)
+	}
+	stream.send(eventAIReady, gin.H{
+		"ai_summary":  aiSummary,
+		"ai_sections": services.ParseAISections(aiSummary),
+	})
 
 	// ── Persist to DB ─────────────────────────────────────────────────────────
 	searchID := uuid.New().String()
-	if err := database.SaveSearch(&database.Search{
+	flightsJSON, _ := json.Marshal(flights)
+	hotelsJSON, _ := json.Marshal(hotels)
+
+	itineraryID := uuid.New().String()
+	if err := database.SaveSearchWithItinerary(&database.Search{
 		ID:            searchID,
 		Origin:        req.Origin,
 		Destination:   req.Destination,
 		DepartureDate: req.DepartureDate,
 		ReturnDate:    req.ReturnDate,
-		Budget:        req.Budget,
+		Budget:        database.MinorUnits(budget.Minor),
 		Passengers:    req.Passengers,
-	}); err != nil {
-		log.Printf("❌ Failed to save search: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save search"})
-		return
-	}
-
-	flightsJSON, _ := json.Marshal(flights)
-	hotelsJSON, _ := json.Marshal(hotels)
-
-	itineraryID := uuid.New().String()
-	if err := database.SaveItinerary(&database.Itinerary{
+		NumNights:     numNights,
+		Children:      req.Children,
+		Infants:       req.Infants,
+		Currency:      req.Currency,
+	}, &database.Itinerary{
 		ID:          itineraryID,
 		SearchID:    searchID,
 		FlightsJSON: string(flightsJSON),
 		HotelsJSON:  string(hotelsJSON),
 		AISummary:   aiSummary,
 	}); err != nil {
-		log.Printf("❌ Failed to save itinerary: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save itinerary"})
+		logf(c, "❌ Failed to save search: %v", err)
+		stream.finish(c, http.StatusInternalServerError, gin.H{"error": "Failed to save search"})
+		return
+	}
+
+	recordPriceSnapshot(c, searchID, priceHistoryKey(req), req.Currency, flights, hotels)
+
+	resp := SearchResponse{
+		SearchID:            searchID,
+		Flights:             flights,
+		Hotels:              hotels,
+		AISummary:           aiSummary,
+		AISections:          services.ParseAISections(aiSummary),
+		Source:              source,
+		OriginName:          services.AirportName(req.Origin),
+		DestinationName:     services.AirportName(req.Destination),
+		ReturnOrigin:        req.ReturnOrigin,
+		Legs:                req.Legs,
+		TripSummary:         services.BuildTripSummary(budget, numNights, flights, hotels),
+		Warnings:            warnings,
+		CabinFares:          cabinFares,
+		FilteredByTime:      filteredByTime,
+		HotelRadiusKM:       hotelRadius,
+		OriginAirports:      originAirports,
+		DestinationAirports: destinationAirports,
+		PriceRounding:       services.PriceRounding,
+		NumNights:           numNights,
+	}
+	if source == "estimated" {
+		resp.FallbackReason = worseFallback(flightFallback, hotelFallback)
+	}
+	if req.StrictBudget {
+		resp.BudgetWarning = services.CheckBudget(budget, numNights, flights, hotels)
+	}
+	if responseShape == "split" {
+		stream.finish(c, http.StatusOK, splitSearchResponse{
+			SearchResponse: resp,
+			Flights:        services.SplitFlights(flights),
+		})
+		return
+	}
+	stream.finish(c, http.StatusOK, resp)
+}
+
+// worseFallback picks the reason more worth telling the user when flights and hotels fell
+// back for different reasons: a provider problem outranks an empty result.
+func worseFallback(a, b string) string {
+	rank := map[string]int{
+		services.FallbackNoResults:     1,
+		services.FallbackUpstreamError: 2,
+		services.FallbackRateLimited:   3,
+		services.FallbackAuthFailed:    4,
+	}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// responseLimit reads a positive item cap from env, falling back to def.
+func responseLimit(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+// capped trims items to requested, or when that is 0 to the response limit in env key
+// (default 10).
+func capped[T any](items []T, requested int, key string) []T {
+	limit := requested
+	if limit <= 0 {
+		limit = responseLimit(key, 10)
+	}
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+// shapeFlights returns flights as the flat list or, for response_shape=split, as legs.
+func shapeFlights(flights []services.Flight, responseShape string) any {
+	if responseShape == "split" {
+		return services.SplitFlights(flights)
+	}
+	return flights
+}
+
+// Page sizes for ListSearchesHandler and ListItinerariesHandler.
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+// ListSearchesHandler returns recent searches, newest first, with the total for paging.
+// Admin only: a search ID is enough to download its itineraries.
+// GET /api/searches?limit=20&offset=0 — limit is capped at 100.
+func ListSearchesHandler(c *gin.Context) {
+	limit, offset := pageParams(c)
+
+	searches, err := database.ListSearches(limit, offset)
+	if err != nil {
+		logf(c, "❌ Failed to list searches: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load searches"})
+		return
+	}
+	total, err := database.CountSearches()
+	if err != nil {
+		logf(c, "❌ Failed to count searches: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load searches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"searches": searches,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// pageParams reads ?limit= (default 20, capped at 100) and ?offset=, ignoring bad values.
+func pageParams(c *gin.Context) (limit, offset int) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSearchPageSize)))
+	if err != nil || limit <= 0 {
+		limit = defaultSearchPageSize
+	}
+	if limit > maxSearchPageSize {
+		limit = maxSearchPageSize
+	}
+	offset, err = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// GetSearchHandler rebuilds a past search's response from its cached itinerary, so a reloaded
+// page gets its flights and hotels back without another (billed) Amadeus search.
+// GET /api/search/:id
+func GetSearchHandler(c *gin.Context) {
+	id := c.Param("id")
+	search, err := database.GetSearch(id)
+	if err != nil {
+		respondLookupError(c, err, "Search session not found")
+		return
+	}
+
+	resp := SearchResponse{
+		SearchID:        search.ID,
+		Flights:         []services.Flight{},
+		Hotels:          []services.Hotel{},
+		Source:          "estimated",
+		OriginName:      services.AirportName(search.Origin),
+		DestinationName: services.AirportName(search.Destination),
+		NumNights:       search.NumNights,
+		PriceRounding:   services.PriceRounding,
+	}
+
+	itinerary, err := database.GetItineraryBySearchID(id)
+	if errors.Is(err, database.ErrNotFound) {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	if err != nil {
+		respondLookupError(c, err, "Itinerary data not found")
+		return
+	}
+	if err := json.Unmarshal([]byte(itinerary.FlightsJSON), &resp.Flights); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse cached flight data"})
+		return
+	}
+	if err := json.Unmarshal([]byte(itinerary.HotelsJSON), &resp.Hotels); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse cached hotel data"})
 		return
 	}
 
-	c.JSON(http.StatusOK, SearchResponse{
-		SearchID:     searchID,
-		Flights:      flights,
-		Hotels:       hotels,
-		AISummary:    aiSummary,
-		Source:       source,
-		ReturnOrigin: req.ReturnOrigin,
+	resp.AISummary = itinerary.AISummary
+	resp.AISections = services.ParseAISections(itinerary.AISummary)
+	resp.Source = storedSource(resp.Flights, resp.Hotels)
+	budget := services.Money{Minor: int64(search.Budget), Currency: search.Currency}
+	resp.TripSummary = services.BuildTripSummary(budget, search.NumNights, resp.Flights, resp.Hotels)
+	c.JSON(http.StatusOK, resp)
+}
+
+// storedSource recovers a cached search's source ("live", "estimated" or "partial") from the
+// Estimated flags on its offers.
+func storedSource(flights []services.Flight, hotels []services.Hotel) string {
+	flightsLive := len(flights) > 0 && !flights[0].Estimated
+	hotelsLive := len(hotels) > 0 && !hotels[0].Estimated
+	switch {
+	case flightsLive && hotelsLive:
+		return "live"
+	case !flightsLive && !hotelsLive:
+		return "estimated"
+	}
+	return "partial"
+}
+
+// SearchHistoryHandler returns the cheapest flight/hotel price recorded each time the trip of
+// a search (same route, dates, cabin and party) was searched.
+func SearchHistoryHandler(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := database.GetSearch(id); err != nil {
+		respondLookupError(c, err, "Search session not found")
+		return
+	}
+
+	snapshots, err := database.GetPriceSnapshots(id)
+	if err != nil {
+		logf(c, "❌ Failed to load price history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load price history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"search_id": id,
+		"snapshots": snapshots,
 	})
-}
\ No newline at end of file
+}
+
+// recordPriceSnapshot stores the cheapest options of a search run for the price-history chart,
+// in currency. Runs that found no flights or no hotels are skipped: a zero price is not a
+// data point. Failures are logged only — history is best-effort and must not fail the search.
+func recordPriceSnapshot(c *gin.Context, searchID, routeKey, currency string, flights []services.Flight, hotels []services.Hotel) {
+	if len(flights) == 0 || len(hotels) == 0 {
+		return
+	}
+	cheapestFlight, cheapestHotel := flights[0].Price, hotels[0].Price
+	for _, f := range flights[1:] {
+		if f.Price.Less(cheapestFlight) {
+			cheapestFlight = f.Price
+		}
+	}
+	for _, h := range hotels[1:] {
+		if h.Price.Less(cheapestHotel) {
+			cheapestHotel = h.Price
+		}
+	}
+	snap := &database.PriceSnapshot{
+		SearchID:       searchID,
+		RouteKey:       routeKey,
+		CheapestFlight: database.MinorUnits(cheapestFlight.Minor),
+		CheapestHotel:  database.MinorUnits(cheapestHotel.Minor),
+		Currency:       currency,
+	}
+	if err := database.SavePriceSnapshot(snap); err != nil {
+		logf(c, "⚠️  Failed to save price snapshot: %v", err)
+	}
+}
+
+// priceHistoryKey names the trip a search prices — route, dates, cabin, party, currency and
+// the filters that change the cheapest offer — so running the same search again adds to one
+// price history, e.g. "TAS-IST|2026-11-10|2026-11-17|ECONOMY|2A1C0I|USD".
+func priceHistoryKey(req SearchRequest) string {
+	route := req.Origin + "-" + req.Destination
+	if req.ReturnOrigin != "" && req.ReturnOrigin != req.Destination {
+		route += "/" + req.ReturnOrigin + "-" + req.Origin
+	}
+	dates := req.DepartureDate + "|" + req.ReturnDate
+	if len(req.Legs) > 0 {
+		legs := make([]string, len(req.Legs))
+		for i, leg := range req.Legs {
+			legs[i] = leg.Origin + "-" + leg.Destination + "@" + leg.DepartureDate
+		}
+		route = strings.Join(legs, ",")
+	}
+	cabin := req.CabinClass
+	if cabin == "" {
+		cabin = "ANY"
+	}
+	key := fmt.Sprintf("%s|%s|%s|%dA%dC%dI|%s", route, dates, cabin, req.Passengers, req.Children, req.Infants, req.Currency)
+	if req.NonStop {
+		key += "|NONSTOP"
+	}
+	if req.IncludeNearby {
+		key += "|NEARBY"
+	}
+	if req.MinHotelRating > 0 {
+		key += fmt.Sprintf("|MIN%g", req.MinHotelRating)
+	}
+	if req.MaxHotelPrice > 0 {
+		key += fmt.Sprintf("|MAX%g", req.MaxHotelPrice)
+	}
+	if req.BoardType != "" {
+		key += "|" + req.BoardType
+	}
+	return key
+}
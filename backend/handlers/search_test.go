@@ -0,0 +1,31 @@
+package handlers
+
+import "testing"
+
+func TestPriceHistoryKey(t *testing.T) {
+	base := SearchRequest{Origin: "TAS", Destination: "IST", DepartureDate: "2026-11-10", ReturnDate: "2026-11-17",
+		Passengers: 2, Children: 1, Currency: "USD"}
+	if got, want := priceHistoryKey(base), "TAS-IST|2026-11-10|2026-11-17|ANY|2A1C0I|USD"; got != want {
+		t.Errorf("priceHistoryKey() = %q, want %q", got, want)
+	}
+
+	// Each of these changes the cheapest offer, so it must start a separate history.
+	variants := map[string]func(*SearchRequest){
+		"currency":       func(r *SearchRequest) { r.Currency = "EUR" },
+		"nonstop":        func(r *SearchRequest) { r.NonStop = true },
+		"include_nearby": func(r *SearchRequest) { r.IncludeNearby = true },
+		"min rating":     func(r *SearchRequest) { r.MinHotelRating = 4 },
+		"max price":      func(r *SearchRequest) { r.MaxHotelPrice = 150 },
+		"board type":     func(r *SearchRequest) { r.BoardType = "BREAKFAST" },
+	}
+	seen := map[string]string{priceHistoryKey(base): "base"}
+	for name, change := range variants {
+		req := base
+		change(&req)
+		key := priceHistoryKey(req)
+		if other, dup := seen[key]; dup {
+			t.Errorf("%s and %s share the key %q", name, other, key)
+		}
+		seen[key] = name
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"tripmind/database"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Share links last defaultShareTTLHours unless the request asks for another lifetime, up
+// to maxShareTTLHours.
+const (
+	defaultShareTTLHours = 72
+	maxShareTTLHours     = 30 * 24
+)
+
+type ShareLinkResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	PDFURL    string    `json:"pdf_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SharedItineraryResponse is the read-only view behind a share link. It leaves out the
+// itinerary and search IDs so the link can't be walked back to the owner's routes. Flight
+// and Hotel are the selection; comparison PDFs have none and list every offer instead.
+type SharedItineraryResponse struct {
+	TravelerName  string            `json:"traveler_name,omitempty"`
+	Origin        string            `json:"origin"`
+	Destination   string            `json:"destination"`
+	DepartureDate string            `json:"departure_date"`
+	ReturnDate    string            `json:"return_date"`
+	NumNights     int               `json:"num_nights"`
+	Flight        *services.Flight  `json:"flight,omitempty"`
+	Hotel         *services.Hotel   `json:"hotel,omitempty"`
+	Flights       []services.Flight `json:"flights,omitempty"`
+	Hotels        []services.Hotel  `json:"hotels,omitempty"`
+	AISummary     string            `json:"ai_summary"`
+	PDFURL        string            `json:"pdf_url,omitempty"`
+	ExpiresAt     time.Time         `json:"expires_at"`
+}
+
+// CreateShareLinkHandler issues a read-only link to an itinerary, replacing any earlier one.
+// POST /api/itinerary/:id/share?ttl_hours=72
+func CreateShareLinkHandler(c *gin.Context) {
+	ttlHours := defaultShareTTLHours
+	if raw := c.Query("ttl_hours"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 || v > maxShareTTLHours {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ttl_hours must be between 1 and " + strconv.Itoa(maxShareTTLHours)})
+			return
+		}
+		ttlHours = v
+	}
+
+	token, expiresAt, err := database.CreateShareLink(c.Param("id"), time.Duration(ttlHours)*time.Hour)
+	if err != nil {
+		respondLookupError(c, err, "Itinerary not found")
+		return
+	}
+	logf(c, "🔗 Share link created for itinerary %s (expires %s)", c.Param("id"), expiresAt.Format(time.RFC3339))
+	c.JSON(http.StatusOK, ShareLinkResponse{
+		Token:     token,
+		URL:       "/api/shared/" + token,
+		PDFURL:    "/api/shared/" + token + "/pdf",
+		ExpiresAt: expiresAt,
+	})
+}
+
+// SharedItineraryHandler returns a shared itinerary while its link is valid.
+// GET /api/shared/:token
+func SharedItineraryHandler(c *gin.Context) {
+	token := c.Param("token")
+	itinerary, expiresAt, ok := sharedItinerary(c)
+	if !ok {
+		return
+	}
+	search, err := database.GetSearch(itinerary.SearchID)
+	if err != nil {
+		respondLookupError(c, err, "Search not found")
+		return
+	}
+
+	var flights []services.Flight
+	var hotels []services.Hotel
+	if err := json.Unmarshal([]byte(itinerary.FlightsJSON), &flights); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse cached flight data"})
+		return
+	}
+	if err := json.Unmarshal([]byte(itinerary.HotelsJSON), &hotels); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse cached hotel data"})
+		return
+	}
+
+	resp := SharedItineraryResponse{
+		TravelerName:  itinerary.TravelerName,
+		Origin:        search.Origin,
+		Destination:   search.Destination,
+		DepartureDate: search.DepartureDate,
+		ReturnDate:    search.ReturnDate,
+		NumNights:     search.NumNights,
+		AISummary:     itinerary.AISummary,
+		ExpiresAt:     expiresAt,
+	}
+	if fi, hi := itinerary.SelectedFlightIndex, itinerary.SelectedHotelIndex; fi != nil && hi != nil &&
+		*fi >= 0 && *fi < len(flights) && *hi >= 0 && *hi < len(hotels) {
+		resp.Flight, resp.Hotel = &flights[*fi], &hotels[*hi]
+	} else {
+		resp.Flights, resp.Hotels = flights, hotels
+	}
+	if len(itinerary.PDFData) > 0 {
+		resp.PDFURL = "/api/shared/" + token + "/pdf"
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// SharedPDFHandler serves a shared itinerary's PDF while its link is valid.
+// GET /api/shared/:token/pdf (?inline=true to view it in the browser)
+func SharedPDFHandler(c *gin.Context) {
+	itinerary, _, ok := sharedItinerary(c)
+	if !ok {
+		return
+	}
+	if len(itinerary.PDFData) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "PDF has not been generated for this itinerary"})
+		return
+	}
+
+	filename := defaultPDFFilename
+	if search, err := database.GetSearch(itinerary.SearchID); err == nil {
+		filename = pdfFilename(itinerary.TravelerName, search.Origin, search.Destination, search.DepartureDate)
+	}
+	c.Header("Content-Disposition", contentDisposition(pdfDisposition(c), filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", itinerary.PDFData)
+}
+
+// sharedItinerary resolves the :token param, answering 404 for an unknown link and 410
+// for an expired one.
+func sharedItinerary(c *gin.Context) (*database.Itinerary, time.Time, bool) {
+	itinerary, expiresAt, err := database.GetSharedItinerary(c.Param("token"))
+	if errors.Is(err, database.ErrShareExpired) {
+		c.JSON(http.StatusGone, gin.H{"error": "This share link has expired"})
+		return nil, time.Time{}, false
+	}
+	if err != nil {
+		respondLookupError(c, err, "Share link not found")
+		return nil, time.Time{}, false
+	}
+	return itinerary, expiresAt, true
+}
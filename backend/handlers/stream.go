@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ─── Search Progress Stream ───────────────────────────────────────────────────
+
+// Events sent by POST /api/search/stream, in the order they normally arrive (flights and
+// hotels race each other). error replaces done when the search can't be saved.
+const (
+	eventFlightsReady = "flights_ready"
+	eventHotelsReady  = "hotels_ready"
+	eventAIReady      = "ai_ready"
+	eventDone         = "done"
+	eventError        = "error"
+)
+
+// searchStream writes Server-Sent Events for one search. A nil stream is a plain JSON search:
+// send is a no-op and finish writes the usual response.
+type searchStream struct {
+	c *gin.Context
+	// mu serializes writes; the flight and hotel goroutines send concurrently.
+	mu sync.Mutex
+}
+
+// startSearchStream commits the 200 and event-stream headers. Validation errors must be
+// reported before this, while a JSON status can still be sent.
+func startSearchStream(c *gin.Context) *searchStream {
+	h := c.Writer.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	// Stop nginx-style proxies from buffering the stream until it ends.
+	h.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+	return &searchStream{c: c}
+}
+
+// send writes one event with a JSON payload and flushes it to the client.
+func (s *searchStream) send(event string, data any) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.c.Request.Context().Err() != nil {
+		return
+	}
+	s.c.SSEvent(event, data)
+	s.c.Writer.Flush()
+}
+
+// finish ends the search: the JSON response for a plain search, or a done (error for a
+// non-200 status) event for a stream.
+func (s *searchStream) finish(c *gin.Context, status int, body any) {
+	if s == nil {
+		c.JSON(status, body)
+		return
+	}
+	if status != http.StatusOK {
+		s.send(eventError, body)
+		return
+	}
+	s.send(eventDone, body)
+}
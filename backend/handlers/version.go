@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"tripmind/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Build info, set at build time:
+//
+//	go build -ldflags "-X tripmind/handlers.GitCommit=$(git rev-parse HEAD) -X tripmind/handlers.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// The Dockerfile does this from Railway's RAILWAY_GIT_COMMIT_SHA.
+var (
+	GitCommit string
+	BuildTime string
+)
+
+// VersionHandler reports which build is running and which providers are configured, to
+// confirm a deploy shipped the expected commit. It never shows credentials.
+// GET /api/version
+func VersionHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"commit":             gitCommit(),
+		"build_time":         BuildTime,
+		"go_version":         runtime.Version(),
+		"amadeus_configured": services.AmadeusConfigured(),
+		"ai_configured":      services.AIConfigured(),
+	})
+}
+
+// gitCommit is GitCommit, or the revision Go stamps into binaries built inside a git
+// checkout, or "unknown".
+func gitCommit() string {
+	if GitCommit != "" {
+		return GitCommit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				return s.Value
+			}
+		}
+	}
+	return "unknown"
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 	"tripmind/database"
@@ -23,18 +24,41 @@ func main() {
 	// Initialize database
 	database.InitDB()
 
+	// Hard-purge soft-deleted itineraries after a grace period (default 30 days)
+	purgeDays := 30
+	if v, err := strconv.Atoi(os.Getenv("ITINERARY_PURGE_DAYS")); err == nil && v > 0 {
+		purgeDays = v
+	}
+	database.StartPurgeJob(time.Duration(purgeDays) * 24 * time.Hour)
+
+	// Delete searches and itineraries (with their PDFs) past retention (default 30 days, checked every 6h)
+	retentionDays := 30
+	if v, err := strconv.Atoi(os.Getenv("RETENTION_DAYS")); err == nil && v > 0 {
+		retentionDays = v
+	}
+	retentionInterval := 6
+	if v, err := strconv.Atoi(os.Getenv("RETENTION_INTERVAL_HOURS")); err == nil && v > 0 {
+		retentionInterval = v
+	}
+	database.StartRetentionJob(time.Duration(retentionDays)*24*time.Hour, time.Duration(retentionInterval)*time.Hour)
+
 	// Initialize Amadeus service
 	services.InitAmadeus()
 
 	// Initialize AI service
 	services.InitAI()
 
+	// Deliver queued notifications (webhooks) outside the request path
+	services.StartNotificationWorker()
+
 	// Set Gin mode
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.Default()
+	// gin.Default's access log is replaced by RequestID's, which carries the request ID
+	r := gin.New()
+	r.Use(gin.Recovery(), handlers.RequestID())
 
 	// Trusted proxies (Railway sits behind a proxy)
 	r.SetTrustedProxies([]string{"0.0.0.0/0"})
@@ -53,9 +77,9 @@ func main() {
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
+		AllowMethods:     []string{"GET", "HEAD", "POST", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Admin-Key", "X-API-Key"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", handlers.RequestIDHeader},
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -64,9 +88,37 @@ func main() {
 	api := r.Group("/api")
 	{
 		api.GET("/health", handlers.HealthHandler)
-		api.POST("/search", handlers.SearchHandler)
-		api.POST("/generate", handlers.GenerateHandler)
+		api.GET("/version", handlers.VersionHandler)
+		api.POST("/search", handlers.APIKeyAuth(), handlers.SearchHandler)
+		api.POST("/search/stream", handlers.APIKeyAuth(), handlers.SearchStreamHandler)
+		api.GET("/searches", handlers.AdminAuth(), handlers.ListSearchesHandler)
+		api.GET("/search/:id", handlers.GetSearchHandler)
+		api.DELETE("/search/:id", handlers.APIKeyAuth(), handlers.DeleteSearchHandler)
+		api.GET("/search/:id/history", handlers.SearchHistoryHandler)
+		api.GET("/search/:id/download-all", handlers.DownloadAllHandler)
+		api.GET("/search/:id/merged", handlers.MergedPDFHandler)
+		api.GET("/hotels/:id/rates", handlers.HotelRatesHandler)
+		api.GET("/locations/:code/airport", handlers.ResolveAirportHandler)
+		api.GET("/cheapest-dates", handlers.APIKeyAuth(), handlers.CheapestDatesHandler)
+		api.POST("/generate", handlers.APIKeyAuth(), handlers.GenerateHandler)
 		api.GET("/download/:id", handlers.DownloadHandler)
+		api.HEAD("/download/:id", handlers.DownloadHandler)
+		api.GET("/itineraries", handlers.AdminAuth(), handlers.ListItinerariesHandler)
+		api.DELETE("/itineraries/:id", handlers.APIKeyAuth(), handlers.DeleteItineraryHandler)
+		api.GET("/itinerary/:id/book", handlers.BookHandler)
+		api.DELETE("/itinerary/:id", handlers.APIKeyAuth(), handlers.DeleteItineraryHandler) // legacy path, also a soft delete
+		api.POST("/itinerary/:id/share", handlers.APIKeyAuth(), handlers.CreateShareLinkHandler)
+		api.GET("/shared/:token", handlers.SharedItineraryHandler)
+		api.GET("/shared/:token/pdf", handlers.SharedPDFHandler)
+
+		admin := api.Group("/admin", handlers.AdminAuth())
+		admin.GET("/usage", handlers.UsageHandler)
+		admin.GET("/itineraries/deleted", handlers.DeletedItinerariesHandler)
+		admin.POST("/itineraries/:id/restore", handlers.RestoreItineraryHandler)
+		admin.DELETE("/itineraries/:id", handlers.AdminDeleteItineraryHandler)
+
+		debug := api.Group("/debug", handlers.DebugOnly())
+		debug.POST("/prompt", handlers.PromptPreviewHandler)
 	}
 
 	port := os.Getenv("PORT")
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// ─── Activities ───────────────────────────────────────────────────────────────
+
+// MaxActivities is how many activities an itinerary lists.
+const MaxActivities = 6
+
+// activityRadiusKM is how far from the hotel activities are searched.
+const activityRadiusKM = 5
+
+// Activity is a tour or attraction near the chosen hotel. Price is zero when unknown
+// (always for the built-in list).
+type Activity struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Price       Money  `json:"price"`
+	Currency    string `json:"currency,omitempty"`
+	BookingLink string `json:"booking_link,omitempty"`
+	Estimated   bool   `json:"estimated"`
+}
+
+type amadeusActivitiesResponse struct {
+	Data []struct {
+		Name             string `json:"name"`
+		ShortDescription string `json:"shortDescription"`
+		Price            struct {
+			Amount       string `json:"amount"`
+			CurrencyCode string `json:"currencyCode"`
+		} `json:"price"`
+		BookingLink string `json:"bookingLink"`
+	} `json:"data"`
+}
+
+// SearchActivities lists up to MaxActivities tours and attractions within a few km of
+// lat/lon, in the order Amadeus ranks them.
+func (c *AmadeusClient) SearchActivities(ctx context.Context, lat, lon float64) ([]Activity, error) {
+	if c.clientID == "" {
+		return nil, ErrAmadeusNotConfigured
+	}
+
+	path := fmt.Sprintf("/v1/shopping/activities?latitude=%.6f&longitude=%.6f&radius=%d", lat, lon, activityRadiusKM)
+	body, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("activities search failed: %w", err)
+	}
+
+	var resp amadeusActivitiesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse activities: %w", err)
+	}
+	activities := make([]Activity, 0, MaxActivities)
+	for _, a := range resp.Data {
+		if len(activities) == MaxActivities {
+			break
+		}
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			continue
+		}
+		act := Activity{
+			Name:        name,
+			Description: shortText(a.ShortDescription, 160),
+			BookingLink: a.BookingLink,
+		}
+		if price := parsePrice(a.Price.Amount); price > 0 {
+			act.Price = NewMoney(price, a.Price.CurrencyCode)
+			act.Currency = a.Price.CurrencyCode
+		}
+		activities = append(activities, act)
+	}
+	return activities, nil
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// shortText flattens an HTML description to plain text, cut at a word boundary after at
+// most limit characters.
+func shortText(s string, limit int) string {
+	s = strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(s, " "))), " ")
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, ",.;:") + "…"
+}
+
+// FallbackActivities turns the curated highlights for a destination (an airport or city
+// code) into activities, for when Amadeus isn't configured or finds nothing nearby.
+func FallbackActivities(destination string) []Activity {
+	highlights := DestinationHighlights(destination)
+	if highlights == "" {
+		highlights = DestinationHighlights(CityOf(destination))
+	}
+	if highlights == "" {
+		return nil
+	}
+	var activities []Activity
+	for _, name := range strings.Split(highlights, " · ") {
+		activities = append(activities, Activity{Name: name, Estimated: true})
+	}
+	return activities
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── AI Summary Cache ─────────────────────────────────────────────────────────
+
+// summaryCache is a bounded LRU of generated summaries, so repeating a search doesn't spend
+// provider quota on the same answer. A nil cache (AI_CACHE=false) never hits.
+type summaryCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // most recently used at the front
+	entries map[string]*list.Element
+}
+
+type summaryEntry struct {
+	key     string
+	text    string
+	expires time.Time
+}
+
+// newSummaryCache reads AI_CACHE (false disables), AI_CACHE_SIZE (default 500 entries) and
+// AI_CACHE_TTL_MINUTES (default 60).
+func newSummaryCache() *summaryCache {
+	if v := strings.ToLower(os.Getenv("AI_CACHE")); v == "false" || v == "0" {
+		return nil
+	}
+	return &summaryCache{
+		size:    envInt("AI_CACHE_SIZE", 500),
+		ttl:     time.Duration(envInt("AI_CACHE_TTL_MINUTES", 60)) * time.Minute,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// summaryKey hashes everything that shapes the output: the prompt carries the route, dates,
+// budget and offers, and the same prompt can still read differently per model or options.
+func summaryKey(provider, model, prompt string, opts GenerationOptions) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%g\x00%s", provider, model, opts.MaxTokens, opts.Temperature, prompt)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (sc *summaryCache) get(key string) (string, bool) {
+	if sc == nil {
+		return "", false
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	el, ok := sc.entries[key]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*summaryEntry)
+	if time.Now().After(e.expires) {
+		sc.order.Remove(el)
+		delete(sc.entries, key)
+		return "", false
+	}
+	sc.order.MoveToFront(el)
+	return e.text, true
+}
+
+// put stores text under key, evicting the least recently used entry when full.
+func (sc *summaryCache) put(key, text string) {
+	if sc == nil {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	expires := time.Now().Add(sc.ttl)
+	if el, ok := sc.entries[key]; ok {
+		e := el.Value.(*summaryEntry)
+		e.text, e.expires = text, expires
+		sc.order.MoveToFront(el)
+		return
+	}
+	sc.entries[key] = sc.order.PushFront(&summaryEntry{key: key, text: text, expires: expires})
+	for sc.order.Len() > sc.size {
+		oldest := sc.order.Back()
+		sc.order.Remove(oldest)
+		delete(sc.entries, oldest.Value.(*summaryEntry).key)
+	}
+}
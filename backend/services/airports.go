@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ─── Airport Codes ────────────────────────────────────────────────────────────
+
+//go:embed airports.txt
+var airportsFile string
+
+// airportNames maps each code in airports.txt to the place it serves.
+var airportNames = parseAirportNames(airportsFile)
+
+// parseAirportNames reads "CODE Place name" lines, skipping blanks and # comments.
+func parseAirportNames(file string) map[string]string {
+	names := map[string]string{}
+	for _, line := range strings.Split(file, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		code, name, _ := strings.Cut(line, " ")
+		names[code] = strings.TrimSpace(name)
+	}
+	return names
+}
+
+// AirportName is the place a code serves, e.g. "Tashkent" for TAS, or the code itself when
+// it isn't in airports.txt.
+func AirportName(code string) string {
+	if name, ok := airportNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// placeLabel names a code for the PDF, e.g. "Tashkent (TAS)", or just "TAS" when unknown.
+func placeLabel(code string) string {
+	if name, ok := airportNames[code]; ok {
+		return name + " (" + code + ")"
+	}
+	return code
+}
+
+// KnownAirportCode reports whether code is in airports.txt or in one of the tables the
+// estimates are built from (city groupings, fallback routes and hotels).
+func KnownAirportCode(code string) bool {
+	if _, ok := airportNames[code]; ok {
+		return true
+	}
+	if _, ok := airportCities[code]; ok {
+		return true
+	}
+	if _, ok := cityPrimaryAirports[code]; ok {
+		return true
+	}
+	if _, ok := fallbackCityHotels[code]; ok {
+		return true
+	}
+	for key := range knownRoutes {
+		if origin, destination, _ := strings.Cut(key, "-"); code == origin || code == destination {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAirportCode vets an upper-cased 3-character code before it reaches a provider.
+// Codes must be letters. Codes outside KnownAirportCode are rejected with
+// STRICT_AIRPORT_CODES=true; otherwise they're logged and allowed, since the built-in list
+// can't hold every airport.
+func CheckAirportCode(ctx context.Context, code string) error {
+	if strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("airport code %q must be letters (e.g. LHR, JFK)", code)
+	}
+	if KnownAirportCode(code) {
+		return nil
+	}
+	if strings.ToLower(os.Getenv("STRICT_AIRPORT_CODES")) == "true" {
+		return fmt.Errorf("Unknown airport code: %s", code)
+	}
+	Logf(ctx, "ℹ️  Airport code %s isn't in the built-in list — searching anyway", code)
+	return nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ─── AI Summary Sections ──────────────────────────────────────────────────────
+
+// AISections is the AI summary split into the sections BuildPrompt asks for, so the client
+// can render flight and hotel advice separately. When the model ignored the format, only
+// Text is set (with the whole summary).
+type AISections struct {
+	FlightRecommendation string `json:"flight_recommendation,omitempty"`
+	HotelRecommendation  string `json:"hotel_recommendation,omitempty"`
+	Highlights           string `json:"highlights,omitempty"`
+	Text                 string `json:"text,omitempty"`
+}
+
+// aiSectionHeader matches a section header at the start of a line, with or without the
+// emoji marker and markdown bold/heading decoration: "✈ Flight:", "**Hotel:**", "## Highlights:".
+var aiSectionHeader = regexp.MustCompile(`(?im)^[\s#>\-]*(\*\*)?\s*(?:[^\p{L}\p{N}\s:*]+\s*)?(flight|hotel|budget summary|budget|highlights)\s*(?:\*\*)?\s*:[ \t]*`)
+
+// ParseAISections splits summary on its section headers; the headers themselves are dropped.
+func ParseAISections(summary string) AISections {
+	matches := aiSectionHeader.FindAllStringSubmatchIndex(summary, -1)
+
+	var sections AISections
+	for i, m := range matches {
+		end := len(summary)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := strings.TrimSpace(summary[m[1]:end])
+		// "**Flight:** text" — the closing bold belongs to the header, not the text
+		if m[2] >= 0 {
+			body = strings.TrimSpace(strings.TrimPrefix(body, "**"))
+		}
+		switch strings.ToLower(summary[m[4]:m[5]]) {
+		case "flight":
+			sections.FlightRecommendation = body
+		case "hotel":
+			sections.HotelRecommendation = body
+		case "highlights":
+			sections.Highlights = body
+		}
+	}
+
+	if sections.FlightRecommendation == "" && sections.HotelRecommendation == "" {
+		return AISections{Text: strings.TrimSpace(summary)}
+	}
+	return sections
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,6 +12,8 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,32 +22,134 @@ import (
 // ─── Types ────────────────────────────────────────────────────────────────────
 
 type Flight struct {
-	Price               float64 `json:"price"`
-	Airline             string  `json:"airline"`
-	AirlineCode         string  `json:"airline_code,omitempty"`
-	FlightNumber        string  `json:"flight_number,omitempty"`
-	DepartureTime       string  `json:"departure_time"`
-	ArrivalTime         string  `json:"arrival_time"`
-	Duration            string  `json:"duration"`
-	Stops               int     `json:"stops"`
-	ReturnDepartureTime string  `json:"return_departure_time,omitempty"`
-	ReturnArrivalTime   string  `json:"return_arrival_time,omitempty"`
-	ReturnDuration      string  `json:"return_duration,omitempty"`
-	ReturnStops         int     `json:"return_stops,omitempty"`
-	BookingLink         string  `json:"booking_link,omitempty"`
-	Currency            string  `json:"currency,omitempty"`
+	// ID identifies the offer within its search (see AssignOfferIDs); select by it, not by index.
+	ID                  string `json:"id,omitempty"`
+	Price               Money  `json:"price"`
+	Airline             string `json:"airline"`
+	AirlineCode         string `json:"airline_code,omitempty"`
+	FlightNumber        string `json:"flight_number,omitempty"`
+	DepartureTime       string `json:"departure_time"` // as sent by the provider (airport-local for Amadeus)
+	ArrivalTime         string `json:"arrival_time"`
+	Duration            string `json:"duration"`
+	Stops               int    `json:"stops"`
+	ReturnDepartureTime string `json:"return_departure_time,omitempty"`
+	ReturnArrivalTime   string `json:"return_arrival_time,omitempty"`
+	ReturnDuration      string `json:"return_duration,omitempty"`
+	ReturnStops         int    `json:"return_stops,omitempty"`
+	BookingLink         string `json:"booking_link,omitempty"`
+	FareRulesLink       string `json:"fare_rules_link,omitempty"`
+	Currency            string `json:"currency,omitempty"`
+	Estimated           bool   `json:"estimated"` // true for generated fallback data
+	// CurrencyMismatch is set when Amadeus priced the offer in a currency we couldn't convert to the requested one.
+	CurrencyMismatch bool `json:"currency_mismatch,omitempty"`
+	// *UTC fields are RFC3339 UTC times, empty when the airport's timezone isn't known.
+	DepartureTimeUTC       string `json:"departure_time_utc,omitempty"`
+	ArrivalTimeUTC         string `json:"arrival_time_utc,omitempty"`
+	ReturnDepartureTimeUTC string `json:"return_departure_time_utc,omitempty"`
+	ReturnArrivalTimeUTC   string `json:"return_arrival_time_utc,omitempty"`
+	// Durations in whole minutes, for sorting and filtering; Duration stays the display string.
+	DurationMinutes       int `json:"duration_minutes,omitempty"`
+	ReturnDurationMinutes int `json:"return_duration_minutes,omitempty"`
+	// CabinClass is the Amadeus travel class of the fare (ECONOMY, PREMIUM_ECONOMY, BUSINESS, FIRST).
+	CabinClass string `json:"cabin_class,omitempty"`
+	// Connections on each leg, in order; only live offers have segment detail.
+	Layovers       []Layover `json:"layovers,omitempty"`
+	ReturnLayovers []Layover `json:"return_layovers,omitempty"`
+	// CO2Kg is the trip's emissions per passenger, as Amadeus reports them or estimated
+	// from flying time and stops.
+	CO2Kg float64 `json:"co2_kg,omitempty"`
+	// BaggageAllowance is the checked baggage included per passenger; nil when a live offer
+	// doesn't say.
+	BaggageAllowance *BaggageAllowance `json:"baggage_allowance,omitempty"`
+	// FareBreakdown is Price split by passenger category, summing to it; nil when a live
+	// offer doesn't price its travelers.
+	FareBreakdown []PassengerFare `json:"fare_breakdown,omitempty"`
+	// Origin and Destination are the outbound's airports (live offers, and estimates for
+	// include_nearby searches), which can differ from the ones searched.
+	Origin      string `json:"origin,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	// Legs is every flight of a multi-leg itinerary, in order. The outbound fields above
+	// repeat the first leg and the return fields the last; middle legs only appear here.
+	Legs []FlightLeg `json:"legs,omitempty"`
+}
+
+// Layover is the wait at a connecting airport between two segments of a leg.
+type Layover struct {
+	Airport         string `json:"airport"`
+	Duration        string `json:"duration"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+// FlightLeg is one direction of a Flight, used by the split response shape, or one flight
+// of a multi-leg itinerary.
+type FlightLeg struct {
+	// Origin and Destination are only set for multi-leg itineraries.
+	Origin        string `json:"origin,omitempty"`
+	Destination   string `json:"destination,omitempty"`
+	Airline       string `json:"airline"`
+	AirlineCode   string `json:"airline_code,omitempty"`
+	FlightNumber  string `json:"flight_number,omitempty"`
+	DepartureTime string `json:"departure_time"`
+	ArrivalTime   string `json:"arrival_time"`
+	Duration      string `json:"duration"`
+	Stops         int    `json:"stops"`
+	// UTC variants of the times above, empty when the airport's timezone isn't known.
+	DepartureTimeUTC string `json:"departure_time_utc,omitempty"`
+	ArrivalTimeUTC   string `json:"arrival_time_utc,omitempty"`
+	DurationMinutes  int    `json:"duration_minutes,omitempty"`
+	// Layovers are the leg's connections (live offers only).
+	Layovers []Layover `json:"layovers,omitempty"`
+}
+
+// SplitFlight carries the same offer as Flight with outbound and return as separate legs.
+type SplitFlight struct {
+	Price       Money       `json:"price"`
+	Currency    string      `json:"currency,omitempty"`
+	BookingLink string      `json:"booking_link,omitempty"`
+	CabinClass  string      `json:"cabin_class,omitempty"`
+	Outbound    FlightLeg   `json:"outbound"`
+	Return      *FlightLeg  `json:"return,omitempty"`
+	Legs        []FlightLeg `json:"legs,omitempty"`
 }
 
 type Hotel struct {
+	// ID identifies the offer within its search (see AssignOfferIDs); HotelID is Amadeus's property ID.
+	ID          string  `json:"id,omitempty"`
 	Name        string  `json:"name"`
 	HotelID     string  `json:"hotel_id,omitempty"`
-	Price       float64 `json:"price"`
+	Price       Money   `json:"price"`
 	Rating      float64 `json:"rating"`
 	Location    string  `json:"location"`
 	BookingLink string  `json:"booking_link,omitempty"`
 	Currency    string  `json:"currency,omitempty"`
+	Estimated   bool    `json:"estimated"`
+	// BoardType is the best offer's meal plan (ROOM_ONLY, BREAKFAST, HALF_BOARD, …); empty for estimates.
+	BoardType string `json:"board_type,omitempty"`
+	// Coordinates from the Amadeus hotel list, and the distance from the city center; unset for estimates.
+	Latitude   float64 `json:"latitude,omitempty"`
+	Longitude  float64 `json:"longitude,omitempty"`
+	DistanceKm float64 `json:"distance_km,omitempty"`
+	// PhotoURL is the first image Amadeus lists for the hotel; unset for estimates.
+	PhotoURL string `json:"photo_url,omitempty"`
+	// CurrencyMismatch is set when Amadeus priced the offer in a currency we couldn't convert to the requested one.
+	CurrencyMismatch bool `json:"currency_mismatch,omitempty"`
+	// Rates lists every room offer; only populated by GetHotelRates.
+	Rates []RateOption `json:"rates,omitempty"`
 }
 
+// RateOption is one bookable rate for a hotel (e.g. room-only vs breakfast included).
+type RateOption struct {
+	OfferID     string `json:"offer_id,omitempty"`
+	BoardType   string `json:"board_type"`
+	Refundable  bool   `json:"refundable"`
+	Price       Money  `json:"price"`
+	Currency    string `json:"currency,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// maxRateOptions bounds how many offers are returned per hotel in detailed mode.
+const maxRateOptions = 10
+
 // ─── Amadeus Client ───────────────────────────────────────────────────────────
 
 type AmadeusClient struct {
@@ -54,10 +160,21 @@ type AmadeusClient struct {
 	tokenExpiry  time.Time
 	mu           sync.Mutex
 	httpClient   *http.Client
+	// refreshing is the token refresh in flight, if any; guarded by mu.
+	refreshing *tokenRefresh
+}
+
+// tokenRefresh is one shared OAuth2 refresh; err is valid once done is closed.
+type tokenRefresh struct {
+	done chan struct{}
+	err  error
 }
 
 var amadeusClient *AmadeusClient
 
+// fareRulesLinksEnabled controls whether flights get a FareRulesLink (FARE_RULES_LINKS=false disables).
+var fareRulesLinksEnabled = true
+
 func InitAmadeus() {
 	env := os.Getenv("AMADEUS_ENV")
 	baseURL := "https://api.amadeus.com"
@@ -65,13 +182,21 @@ func InitAmadeus() {
 		baseURL = "https://test.api.amadeus.com"
 	}
 
+	if v := strings.ToLower(os.Getenv("FARE_RULES_LINKS")); v == "false" || v == "0" {
+		fareRulesLinksEnabled = false
+	}
+
+	loadFXRates(os.Getenv("FX_RATES"))
+	loadPriceRounding(os.Getenv("PRICE_ROUNDING"))
+	if err := loadFallbackData(os.Getenv("FALLBACK_DATA_PATH")); err != nil {
+		log.Fatalf("❌ Invalid FALLBACK_DATA_PATH: %v", err)
+	}
+
 	amadeusClient = &AmadeusClient{
 		clientID:     os.Getenv("AMADEUS_CLIENT_ID"),
 		clientSecret: os.Getenv("AMADEUS_CLIENT_SECRET"),
 		baseURL:      baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient:   newProviderHTTPClient(envDuration("AMADEUS_TIMEOUT", 30*time.Second), "AMADEUS_PROXY"),
 	}
 
 	if amadeusClient.clientID == "" || amadeusClient.clientSecret == "" {
@@ -79,7 +204,7 @@ func InitAmadeus() {
 		return
 	}
 
-	if err := amadeusClient.refreshToken(); err != nil {
+	if err := amadeusClient.refreshToken(context.Background()); err != nil {
 		log.Printf("⚠️  Amadeus token failed: %v — using rich mock data instead", err)
 	} else {
 		log.Println("✅ Amadeus API authenticated")
@@ -90,27 +215,68 @@ func GetAmadeusClient() *AmadeusClient {
 	return amadeusClient
 }
 
-func (c *AmadeusClient) refreshToken() error {
+// AmadeusConfigured reports whether Amadeus credentials are set (searches may still fall back).
+func AmadeusConfigured() bool {
+	return amadeusClient != nil && amadeusClient.clientID != "" && amadeusClient.clientSecret != ""
+}
+
+// Token refresh retries: tokenRefreshAttempts tries, waiting tokenRefreshBackoff, then
+// twice as long, between them. Only network errors, 429 and 5xx are retried.
+const (
+	tokenRefreshAttempts = 3
+	tokenRefreshBackoff  = 500 * time.Millisecond
+)
+
+// refreshToken fetches a new access token, retrying transient failures so a network blip
+// on the first search after a cold start doesn't push it onto estimated data. Bad
+// credentials (400/401) fail immediately.
+func (c *AmadeusClient) refreshToken(ctx context.Context) error {
+	if err := allowCall(ProviderAmadeusAuth); err != nil {
+		return err
+	}
+
+	backoff := tokenRefreshBackoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := c.requestToken(ctx)
+		RecordProviderCall(ProviderAmadeusAuth, err)
+		if err == nil || !retryable || attempt == tokenRefreshAttempts {
+			return err
+		}
+
+		Logf(ctx, "⚠️  Amadeus token attempt %d/%d failed: %v — retrying in %s", attempt, tokenRefreshAttempts, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// requestToken makes one token request and stores the result; retryable reports whether
+// the failure is worth another attempt.
+func (c *AmadeusClient) requestToken(ctx context.Context) (retryable bool, err error) {
 	form := url.Values{}
 	form.Set("grant_type", "client_credentials")
 	form.Set("client_id", c.clientID)
 	form.Set("client_secret", c.clientSecret)
 
-	req, err := http.NewRequest("POST", c.baseURL+"/v1/security/oauth2/token", strings.NewReader(form.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/security/oauth2/token", strings.NewReader(form.Encode()))
 	if err != nil {
-		return err
+		return false, err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return ctx.Err() == nil, err
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp.Body)
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("token request failed (%d): %s", resp.StatusCode, string(body))
+		retryable = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, &AmadeusError{Status: resp.StatusCode, Body: string(body), Token: true}
 	}
 
 	var result struct {
@@ -118,96 +284,231 @@ func (c *AmadeusClient) refreshToken() error {
 		ExpiresIn   int    `json:"expires_in"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return fmt.Errorf("failed to parse token response: %v", err)
+		return false, fmt.Errorf("failed to parse token response: %v", err)
 	}
 
 	c.mu.Lock()
 	c.accessToken = result.AccessToken
 	c.tokenExpiry = time.Now().Add(time.Duration(result.ExpiresIn-30) * time.Second)
 	c.mu.Unlock()
-	return nil
+	return false, nil
 }
 
-func (c *AmadeusClient) getToken() (string, error) {
+// getToken returns a valid access token. When it has expired, the first caller starts a
+// single refresh and every concurrent caller waits for that same result instead of
+// hitting the token endpoint itself. The lock is never held during the HTTP call.
+func (c *AmadeusClient) getToken(ctx context.Context) (string, error) {
 	c.mu.Lock()
-	expired := time.Now().After(c.tokenExpiry)
-	token := c.accessToken
+	if c.accessToken != "" && time.Now().Before(c.tokenExpiry) {
+		token := c.accessToken
+		c.mu.Unlock()
+		return token, nil
+	}
+	refresh := c.refreshing
+	if refresh == nil {
+		refresh = &tokenRefresh{done: make(chan struct{})}
+		c.refreshing = refresh
+		// Detached from ctx: one caller disconnecting mustn't fail the refresh for the rest.
+		go func() {
+			refresh.err = c.refreshToken(context.Background())
+			c.mu.Lock()
+			c.refreshing = nil
+			c.mu.Unlock()
+			close(refresh.done)
+		}()
+	}
 	c.mu.Unlock()
 
-	if expired || token == "" {
-		if err := c.refreshToken(); err != nil {
-			return "", err
-		}
-		c.mu.Lock()
-		token = c.accessToken
-		c.mu.Unlock()
+	select {
+	case <-refresh.done:
+	case <-ctx.Done():
+		return "", ctx.Err()
 	}
-	return token, nil
+	if refresh.err != nil {
+		return "", refresh.err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accessToken, nil
 }
 
-func (c *AmadeusClient) doRequest(method, path string, body []byte) ([]byte, error) {
-	token, err := c.getToken()
+// doRequest performs an authenticated Amadeus call; cancelling ctx (e.g. the client
+// disconnected) aborts it. While a token refresh is in progress it only stops waiting for
+// it: the shared refresh carries on for the other callers (see getToken).
+func (c *AmadeusClient) doRequest(ctx context.Context, method, path string, body []byte) (respBody []byte, err error) {
+	provider := amadeusProvider(path)
+	if err := allowCall(provider); err != nil {
+		return nil, err
+	}
+
+	token, err := c.getToken(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("auth failed: %w", err)
 	}
 
+	defer func() { RecordProviderCall(provider, err) }()
+
+	// A 429 is retried once after Retry-After (capped); a second one is ErrRateLimited.
+	for attempt := 1; ; attempt++ {
+		var status int
+		var retryAfter time.Duration
+		status, retryAfter, respBody, err = c.send(ctx, method, path, body, token)
+		if err != nil {
+			return nil, err
+		}
+		if status == http.StatusTooManyRequests {
+			if attempt == 1 {
+				Logf(ctx, "⚠️  Amadeus rate limited %s — retrying in %s", provider, retryAfter)
+				select {
+				case <-time.After(retryAfter):
+					continue
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+			return nil, fmt.Errorf("%w: %s", ErrRateLimited, string(respBody))
+		}
+		if status < 200 || status >= 300 {
+			return nil, &AmadeusError{Status: status, Body: string(respBody)}
+		}
+		return respBody, nil
+	}
+}
+
+// ErrRateLimited is returned (wrapped) when Amadeus still answers 429 after one retry.
+var ErrRateLimited = errors.New("amadeus rate limit exceeded")
+
+// maxRetryAfter caps how long doRequest waits on a 429's Retry-After.
+const maxRetryAfter = 5 * time.Second
+
+// send makes one HTTP attempt; retryAfter is only meaningful for a 429.
+func (c *AmadeusClient) send(ctx context.Context, method, path string, body []byte, token string) (status int, retryAfter time.Duration, respBody []byte, err error) {
 	var req *http.Request
 	if body != nil {
-		req, err = http.NewRequest(method, c.baseURL+path, bytes.NewBuffer(body))
+		req, err = http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
 	} else {
-		req, err = http.NewRequest(method, c.baseURL+path, nil)
+		req, err = http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
 	}
 	if err != nil {
-		return nil, err
+		return 0, 0, nil, err
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
+	if method == http.MethodPost {
+		// Amadeus serves its POST search variants (JSON criteria instead of a query string) as GETs.
+		req.Header.Set("X-HTTP-Method-Override", "GET")
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return 0, 0, nil, err
+	}
+	defer closeBody(resp.Body)
+
+	respBody, _ = io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
 	}
-	defer resp.Body.Close()
+	return resp.StatusCode, retryAfter, respBody, nil
+}
 
-	respBody, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("amadeus error (%d): %s", resp.StatusCode, string(respBody))
+// parseRetryAfter reads delay-seconds or an HTTP date, defaulting to one second and
+// capping at maxRetryAfter.
+func parseRetryAfter(header string) time.Duration {
+	wait := time.Second
+	if secs, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && secs >= 0 {
+		wait = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(header); err == nil {
+		wait = time.Until(at)
+	}
+	if wait < 0 {
+		wait = 0
 	}
-	return respBody, nil
+	if wait > maxRetryAfter {
+		wait = maxRetryAfter
+	}
+	return wait
 }
 
 // ─── Flight Search ────────────────────────────────────────────────────────────
 
-func (c *AmadeusClient) SearchFlights(origin, destination, departureDate, returnDate string, adults int) ([]Flight, error) {
+// SearchFlights searches round-trip offers priced in currency for the whole party in pax.
+// cabinClass restricts the travel class (e.g. "BUSINESS"); empty leaves it unrestricted.
+// nonStopOnly asks for direct flights only, and maxResults caps the offers requested
+// (DefaultFlightOffers when 0).
+func (c *AmadeusClient) SearchFlights(ctx context.Context, origin, destination, departureDate, returnDate string, pax PassengerMix, cabinClass string, nonStopOnly bool, currency string, maxResults int) ([]Flight, error) {
 	if c.clientID == "" {
-		return nil, fmt.Errorf("amadeus not configured")
+		return nil, ErrAmadeusNotConfigured
 	}
 
 	path := fmt.Sprintf(
-		"/v2/shopping/flight-offers?originLocationCode=%s&destinationLocationCode=%s&departureDate=%s&returnDate=%s&adults=%d&max=6&currencyCode=USD",
+		"/v2/shopping/flight-offers?originLocationCode=%s&destinationLocationCode=%s&departureDate=%s&returnDate=%s&max=%d&currencyCode=%s",
 		url.QueryEscape(origin), url.QueryEscape(destination),
-		url.QueryEscape(departureDate), url.QueryEscape(returnDate), adults,
+		url.QueryEscape(departureDate), url.QueryEscape(returnDate), flightOffersMax(maxResults), url.QueryEscape(currency),
 	)
+	path += passengerQuery(pax) + travelClassQuery(cabinClass) + nonStopQuery(nonStopOnly)
 
-	body, err := c.doRequest("GET", path, nil)
+	body, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("flight search failed: %w", err)
 	}
 
-	return parseFlightOffers(body)
+	flights, err := parseFlightOffers(body, cabinClass, currency)
+	if err != nil {
+		return nil, err
+	}
+	c.nameAirlines(ctx, flights)
+	return flights, nil
+}
+
+// Result counts a search may ask for: DefaultFlightOffers flight offers and
+// DefaultHotelOfferIDs hotels priced per radius unless a request sets its own, at most
+// MaxSearchResults of either.
+const (
+	DefaultFlightOffers  = 6
+	DefaultHotelOfferIDs = 20
+	MaxSearchResults     = 50
+)
+
+// flightOffersMax is the max= of a flight-offers search asking for maxResults offers.
+func flightOffersMax(maxResults int) int {
+	if maxResults <= 0 {
+		return DefaultFlightOffers
+	}
+	return min(maxResults, MaxSearchResults)
+}
+
+func travelClassQuery(cabinClass string) string {
+	if cabinClass == "" {
+		return ""
+	}
+	return "&travelClass=" + url.QueryEscape(cabinClass)
+}
+
+func nonStopQuery(nonStopOnly bool) string {
+	if !nonStopOnly {
+		return ""
+	}
+	return "&nonStop=true"
 }
 
 // SearchFlightsMultiCity fetches two one-way flights and combines them into round-trip-style Flight structs.
 // outboundOrigin→outboundDest on departureDate, returnOrigin→returnDest on returnDate.
 func (c *AmadeusClient) SearchFlightsMultiCity(
+	ctx context.Context,
 	outboundOrigin, outboundDest,
 	returnOrigin, returnDest,
 	departureDate, returnDate string,
-	adults int,
+	pax PassengerMix,
+	cabinClass string,
+	nonStopOnly bool,
+	currency string,
+	maxResults int,
 ) ([]Flight, error) {
 	if c.clientID == "" {
-		return nil, fmt.Errorf("amadeus not configured")
+		return nil, ErrAmadeusNotConfigured
 	}
 
 	// Fetch both legs in parallel
@@ -220,31 +521,31 @@ func (c *AmadeusClient) SearchFlightsMultiCity(
 
 	go func() {
 		path := fmt.Sprintf(
-			"/v2/shopping/flight-offers?originLocationCode=%s&destinationLocationCode=%s&departureDate=%s&adults=%d&max=6&currencyCode=USD&nonStop=false",
+			"/v2/shopping/flight-offers?originLocationCode=%s&destinationLocationCode=%s&departureDate=%s&max=%d&currencyCode=%s",
 			url.QueryEscape(outboundOrigin), url.QueryEscape(outboundDest),
-			url.QueryEscape(departureDate), adults,
-		)
-		body, err := c.doRequest("GET", path, nil)
+			url.QueryEscape(departureDate), flightOffersMax(maxResults), url.QueryEscape(currency),
+		) + passengerQuery(pax) + travelClassQuery(cabinClass) + nonStopQuery(nonStopOnly)
+		body, err := c.doRequest(ctx, "GET", path, nil)
 		if err != nil {
 			outCh <- legResult{nil, err}
 			return
 		}
-		flights, err := parseFlightOffers(body)
+		flights, err := parseFlightOffers(body, cabinClass, currency)
 		outCh <- legResult{flights, err}
 	}()
 
 	go func() {
 		path := fmt.Sprintf(
-			"/v2/shopping/flight-offers?originLocationCode=%s&destinationLocationCode=%s&departureDate=%s&adults=%d&max=6&currencyCode=USD&nonStop=false",
+			"/v2/shopping/flight-offers?originLocationCode=%s&destinationLocationCode=%s&departureDate=%s&max=%d&currencyCode=%s",
 			url.QueryEscape(returnOrigin), url.QueryEscape(returnDest),
-			url.QueryEscape(returnDate), adults,
-		)
-		body, err := c.doRequest("GET", path, nil)
+			url.QueryEscape(returnDate), flightOffersMax(maxResults), url.QueryEscape(currency),
+		) + passengerQuery(pax) + travelClassQuery(cabinClass) + nonStopQuery(nonStopOnly)
+		body, err := c.doRequest(ctx, "GET", path, nil)
 		if err != nil {
 			retCh <- legResult{nil, err}
 			return
 		}
-		flights, err := parseFlightOffers(body)
+		flights, err := parseFlightOffers(body, cabinClass, currency)
 		retCh <- legResult{flights, err}
 	}()
 
@@ -265,16 +566,122 @@ func (c *AmadeusClient) SearchFlightsMultiCity(
 		if i < len(retResult.flights) {
 			ret = retResult.flights[i]
 		}
-		out.Price = out.Price + ret.Price
+		// Never sum legs priced in different currencies into one fare.
+		out.CurrencyMismatch = out.CurrencyMismatch || ret.CurrencyMismatch || !SameCurrency(out.Currency, ret.Currency)
+		out.Price = out.Price.Add(ret.Price)
+		out.FareBreakdown = addFareBreakdowns(out.FareBreakdown, ret.FareBreakdown)
+		out.CO2Kg += ret.CO2Kg
+		out.BaggageAllowance = smallerBaggage(out.BaggageAllowance, ret.BaggageAllowance)
 		out.ReturnDepartureTime = ret.DepartureTime
 		out.ReturnArrivalTime = ret.ArrivalTime
+		out.ReturnDepartureTimeUTC = ret.DepartureTimeUTC
+		out.ReturnArrivalTimeUTC = ret.ArrivalTimeUTC
 		out.ReturnDuration = ret.Duration
+		out.ReturnDurationMinutes = ret.DurationMinutes
 		out.ReturnStops = ret.Stops
+		out.ReturnLayovers = ret.Layovers
 		combined = append(combined, out)
 	}
+	c.nameAirlines(ctx, combined)
 	return combined, nil
 }
 
+// ─── Cabin Comparison ─────────────────────────────────────────────────────────
+
+// CabinFare is the cheapest round-trip fare found in one cabin, for "Economy from $X /
+// Business from $Y" comparisons.
+type CabinFare struct {
+	Cabin     string `json:"cabin"`
+	FromPrice Money  `json:"from_price"`
+	Currency  string `json:"currency,omitempty"`
+	Offers    int    `json:"offers"`
+	Estimated bool   `json:"estimated"`
+}
+
+// cabinPriceFactors are the Amadeus travel classes with a rough multiplier over economy,
+// used to estimate a cabin when live data isn't available.
+var cabinPriceFactors = map[string]float64{
+	"ECONOMY":         1.0,
+	"PREMIUM_ECONOMY": 1.6,
+	"BUSINESS":        3.2,
+	"FIRST":           5.5,
+}
+
+// MaxCabinSearches bounds the extra flight searches one multi-cabin request may trigger.
+const MaxCabinSearches = 4
+
+// cabinPriceFactor is the multiplier over economy for cabin; unknown or empty counts as economy.
+func cabinPriceFactor(cabin string) float64 {
+	if factor, ok := cabinPriceFactors[cabin]; ok {
+		return factor
+	}
+	return 1.0
+}
+
+// IsValidCabin reports whether cabin is an Amadeus travel class.
+func IsValidCabin(cabin string) bool {
+	_, ok := cabinPriceFactors[cabin]
+	return ok
+}
+
+// CompareCabinFares searches each cabin concurrently and returns its cheapest fare, in the
+// order given. Cabins the live search can't price (or all of them when c is nil) are
+// estimated from the cheapest of baseline, the flights already shown to the user.
+func CompareCabinFares(ctx context.Context, c *AmadeusClient, origin, destination, departureDate, returnDate string, pax PassengerMix, nonStopOnly bool, currency string, cabins []string, baseline []Flight) []CabinFare {
+	if len(cabins) > MaxCabinSearches {
+		cabins = cabins[:MaxCabinSearches]
+	}
+
+	fares := make([]CabinFare, len(cabins))
+	var wg sync.WaitGroup
+	for i, cabin := range cabins {
+		wg.Add(1)
+		go func(i int, cabin string) {
+			defer wg.Done()
+			if c != nil {
+				flights, err := c.SearchFlights(ctx, origin, destination, departureDate, returnDate, pax, cabin, nonStopOnly, currency, 0)
+				if err == nil {
+					flights, _ = DropCurrencyMismatchedFlights(flights)
+				}
+				if err == nil && len(flights) > 0 {
+					cheapest := flights[0]
+					for _, f := range flights {
+						if f.Price.Less(cheapest.Price) {
+							cheapest = f
+						}
+					}
+					fares[i] = CabinFare{Cabin: cabin, FromPrice: cheapest.Price, Currency: cheapest.Currency, Offers: len(flights)}
+					return
+				}
+				if err != nil {
+					Logf(ctx, "⚠️  Amadeus %s cabin search failed: %v — estimating", cabin, err)
+				}
+			}
+			fares[i] = estimateCabinFare(cabin, baseline)
+		}(i, cabin)
+	}
+	wg.Wait()
+	return fares
+}
+
+func estimateCabinFare(cabin string, baseline []Flight) CabinFare {
+	fare := CabinFare{Cabin: cabin, Currency: "USD", Estimated: true}
+	if len(baseline) == 0 {
+		return fare
+	}
+	cheapest := baseline[0]
+	for _, f := range baseline {
+		if f.Price.Less(cheapest.Price) {
+			cheapest = f
+		}
+	}
+	// Scale relative to the baseline's own cabin, which is economy unless the search asked otherwise.
+	price := math.Round(cheapest.Price.Float64()*cabinPriceFactor(cabin)/cabinPriceFactor(cheapest.CabinClass)/5) * 5
+	fare.FromPrice = NewMoney(price, cheapest.Currency)
+	fare.Currency = cheapest.Currency
+	return fare
+}
+
 type amadeusFlightOffersResponse struct {
 	Data []amadeusFlightOffer `json:"data"`
 }
@@ -284,19 +691,60 @@ type amadeusFlightOffer struct {
 		GrandTotal string `json:"grandTotal"`
 		Currency   string `json:"currency"`
 	} `json:"price"`
-	Itineraries []struct {
-		Duration string `json:"duration"`
-		Segments []struct {
-			Departure   struct{ IataCode, At string } `json:"departure"`
-			Arrival     struct{ IataCode, At string } `json:"arrival"`
-			CarrierCode string                        `json:"carrierCode"`
-			Number      string                        `json:"number"`
-		} `json:"segments"`
-	} `json:"itineraries"`
-	ValidatingAirlineCodes []string `json:"validatingAirlineCodes"`
-}
-
-func parseFlightOffers(data []byte) ([]Flight, error) {
+	Itineraries            []amadeusItinerary `json:"itineraries"`
+	ValidatingAirlineCodes []string           `json:"validatingAirlineCodes"`
+	TravelerPricings       []struct {
+		TravelerType string `json:"travelerType"`
+		Price        struct {
+			Currency string `json:"currency"`
+			Total    string `json:"total"`
+		} `json:"price"`
+		FareDetailsBySegment []struct {
+			Cabin               string              `json:"cabin"`
+			IncludedCheckedBags *amadeusCheckedBags `json:"includedCheckedBags"`
+		} `json:"fareDetailsBySegment"`
+	} `json:"travelerPricings"`
+}
+
+type amadeusItinerary struct {
+	Duration string           `json:"duration"`
+	Segments []amadeusSegment `json:"segments"`
+}
+
+type amadeusSegment struct {
+	Departure    struct{ IataCode, At string } `json:"departure"`
+	Arrival      struct{ IataCode, At string } `json:"arrival"`
+	CarrierCode  string                        `json:"carrierCode"`
+	Number       string                        `json:"number"`
+	Co2Emissions []struct {
+		Weight     float64 `json:"weight"`
+		WeightUnit string  `json:"weightUnit"`
+	} `json:"co2Emissions"`
+}
+
+// segmentLayovers is the gap at each connection of a leg. Both times are local to the
+// connecting airport, so subtracting them is exact, including overnight connections.
+func segmentLayovers(segments []amadeusSegment) []Layover {
+	var layovers []Layover
+	for i := 1; i < len(segments); i++ {
+		arrived, _, ok1 := parseSegmentTime(segments[i-1].Arrival.At)
+		departs, _, ok2 := parseSegmentTime(segments[i].Departure.At)
+		if !ok1 || !ok2 || departs.Before(arrived) {
+			continue
+		}
+		minutes := int(departs.Sub(arrived).Minutes())
+		layovers = append(layovers, Layover{
+			Airport:         segments[i].Departure.IataCode,
+			Duration:        formatDurationMin(minutes),
+			DurationMinutes: minutes,
+		})
+	}
+	return layovers
+}
+
+// parseFlightOffers converts an Amadeus flight-offers body, pricing offers in currency;
+// requestedCabin labels offers whose fare details don't name a cabin.
+func parseFlightOffers(data []byte, requestedCabin, currency string) ([]Flight, error) {
 	var resp amadeusFlightOffersResponse
 	if err := json.Unmarshal(data, &resp); err != nil {
 		return nil, fmt.Errorf("failed to parse flight offers: %w", err)
@@ -304,83 +752,238 @@ func parseFlightOffers(data []byte) ([]Flight, error) {
 
 	flights := make([]Flight, 0, len(resp.Data))
 	for _, offer := range resp.Data {
-		if len(offer.Itineraries) < 1 {
-			continue
-		}
-		price := parsePrice(offer.Price.GrandTotal)
-		if price <= 0 {
-			continue
+		if f, ok := flightFromOffer(offer, requestedCabin, currency); ok {
+			flights = append(flights, f)
 		}
+	}
+	return flights, nil
+}
+
+// flightFromOffer converts one offer, reading its first itinerary as the outbound and the
+// second (if any) as the return. ok is false for offers without itineraries or a price.
+func flightFromOffer(offer amadeusFlightOffer, requestedCabin, currency string) (Flight, bool) {
+	if len(offer.Itineraries) < 1 {
+		return Flight{}, false
+	}
+	price := parsePrice(offer.Price.GrandTotal)
+	if price <= 0 {
+		return Flight{}, false
+	}
 
-		outbound := offer.Itineraries[0]
-		airlineCode := ""
-		if len(outbound.Segments) > 0 {
-			airlineCode = outbound.Segments[0].CarrierCode
-		} else if len(offer.ValidatingAirlineCodes) > 0 {
-			airlineCode = offer.ValidatingAirlineCodes[0]
+	outbound := offer.Itineraries[0]
+	airlineCode := ""
+	if len(outbound.Segments) > 0 {
+		airlineCode = outbound.Segments[0].CarrierCode
+	} else if len(offer.ValidatingAirlineCodes) > 0 {
+		airlineCode = offer.ValidatingAirlineCodes[0]
+	}
+
+	amount, converted := normalizeCurrency(NewMoney(price, offer.Price.Currency), currency, "flight offer")
+	f := Flight{
+		Price:            amount,
+		Airline:          airlineName(airlineCode),
+		AirlineCode:      airlineCode,
+		Currency:         amount.Currency,
+		Stops:            max(0, len(outbound.Segments)-1),
+		Duration:         parseDuration(outbound.Duration),
+		DurationMinutes:  parseDurationMinutes(outbound.Duration),
+		Layovers:         segmentLayovers(outbound.Segments),
+		CurrencyMismatch: !converted,
+	}
+
+	if len(outbound.Segments) > 0 {
+		first, last := outbound.Segments[0], outbound.Segments[len(outbound.Segments)-1]
+		f.DepartureTime = first.Departure.At
+		f.ArrivalTime = last.Arrival.At
+		f.DepartureTimeUTC = segmentTimeUTC(first.Departure.At, first.Departure.IataCode)
+		f.ArrivalTimeUTC = segmentTimeUTC(last.Arrival.At, last.Arrival.IataCode)
+		f.FlightNumber = airlineCode + first.Number
+		f.Origin, f.Destination = first.Departure.IataCode, last.Arrival.IataCode
+	}
+	f.FareRulesLink = fareRulesLink(airlineCode)
+	f.CabinClass = requestedCabin
+	if len(offer.TravelerPricings) > 0 && len(offer.TravelerPricings[0].FareDetailsBySegment) > 0 {
+		if cabin := offer.TravelerPricings[0].FareDetailsBySegment[0].Cabin; cabin != "" {
+			f.CabinClass = cabin
 		}
+	}
 
-		f := Flight{
-			Price:       price,
-			Airline:     airlineName(airlineCode),
-			AirlineCode: airlineCode,
-			Currency:    offer.Price.Currency,
-			Stops:       max(0, len(outbound.Segments)-1),
-			Duration:    parseDuration(outbound.Duration),
+	if len(offer.Itineraries) >= 2 {
+		ret := offer.Itineraries[1]
+		f.ReturnStops = max(0, len(ret.Segments)-1)
+		f.ReturnDuration = parseDuration(ret.Duration)
+		f.ReturnDurationMinutes = parseDurationMinutes(ret.Duration)
+		f.ReturnLayovers = segmentLayovers(ret.Segments)
+		if len(ret.Segments) > 0 {
+			first, last := ret.Segments[0], ret.Segments[len(ret.Segments)-1]
+			f.ReturnDepartureTime = first.Departure.At
+			f.ReturnArrivalTime = last.Arrival.At
+			f.ReturnDepartureTimeUTC = segmentTimeUTC(first.Departure.At, first.Departure.IataCode)
+			f.ReturnArrivalTimeUTC = segmentTimeUTC(last.Arrival.At, last.Arrival.IataCode)
 		}
+	}
 
-		if len(outbound.Segments) > 0 {
-			f.DepartureTime = outbound.Segments[0].Departure.At
-			f.ArrivalTime = outbound.Segments[len(outbound.Segments)-1].Arrival.At
-			f.FlightNumber = airlineCode + outbound.Segments[0].Number
+	f.BaggageAllowance = reportedBaggage(offer)
+	if !f.CurrencyMismatch {
+		// The traveler prices add up to the grand total, give or take rounding; the total
+		// shown is their sum so the breakdown always adds up.
+		if fares := reportedFareBreakdown(offer, currency); fares != nil {
+			f.FareBreakdown = fares
+			f.Price = fareBreakdownTotal(fares)
 		}
+	}
 
-		if len(offer.Itineraries) >= 2 {
-			ret := offer.Itineraries[1]
-			f.ReturnStops = max(0, len(ret.Segments)-1)
-			f.ReturnDuration = parseDuration(ret.Duration)
-			if len(ret.Segments) > 0 {
-				f.ReturnDepartureTime = ret.Segments[0].Departure.At
-				f.ReturnArrivalTime = ret.Segments[len(ret.Segments)-1].Arrival.At
+	if co2, ok := reportedCO2Kg(offer); ok {
+		f.CO2Kg = co2
+	} else {
+		f.CO2Kg = flightCO2Kg(f)
+	}
+	return f, true
+}
+
+// reportedCO2Kg sums the per-passenger co2Emissions Amadeus attaches to segments. ok is
+// false unless every segment has a figure, so a partly reported offer gets estimated instead.
+func reportedCO2Kg(offer amadeusFlightOffer) (float64, bool) {
+	total, segments := 0.0, 0
+	for _, it := range offer.Itineraries {
+		for _, seg := range it.Segments {
+			segments++
+			if len(seg.Co2Emissions) == 0 {
+				return 0, false
+			}
+			e := seg.Co2Emissions[0]
+			if strings.HasPrefix(strings.ToUpper(e.WeightUnit), "LB") {
+				total += e.Weight * 0.4536
+			} else {
+				total += e.Weight
 			}
 		}
-
-		flights = append(flights, f)
 	}
-	return flights, nil
+	return math.Round(total), segments > 0
 }
 
 // ─── Hotel Search ─────────────────────────────────────────────────────────────
 
-func (c *AmadeusClient) SearchHotels(cityCode, checkIn, checkOut string, adults int) ([]Hotel, error) {
+// hotelRadiiKM are the search radii tried in turn while fewer than HOTEL_MIN_RESULTS hotels
+// have bookable offers; HOTEL_MAX_RADIUS_KM caps how far the search may widen.
+var hotelRadiiKM = []int{5, 15, 30, 50}
+
+// SearchHotels returns hotels with offers for the dates, priced in currency, plus the radius (km)
+// that produced them. Many listed hotels have nothing available, so sparse results widen the radius.
+// Only hotels matching filter count towards HOTEL_MIN_RESULTS, but all of them are returned so the
+// caller can relax the filter when nothing matches. Each radius prices at most maxHotels newly
+// listed hotels (DefaultHotelOfferIDs when 0).
+func (c *AmadeusClient) SearchHotels(ctx context.Context, cityCode, checkIn, checkOut string, adults int, currency string, filter HotelFilter, maxHotels int) ([]Hotel, int, error) {
 	if c.clientID == "" {
-		return nil, fmt.Errorf("amadeus not configured")
+		return nil, 0, ErrAmadeusNotConfigured
 	}
 
-	hotelIDs, err := c.getHotelIDsByCity(cityCode)
-	if err != nil {
-		return nil, fmt.Errorf("hotel list failed: %w", err)
+	minResults := envInt("HOTEL_MIN_RESULTS", 3)
+	maxRadius := envInt("HOTEL_MAX_RADIUS_KM", 30)
+	if maxHotels <= 0 {
+		maxHotels = DefaultHotelOfferIDs
+	}
+	maxHotels = min(maxHotels, MaxSearchResults)
+
+	var hotels []Hotel
+	seen := map[string]bool{}
+	listed := map[string]hotelListing{}
+	radius := 0
+	for i, r := range hotelRadiiKM {
+		if i > 0 && r > maxRadius {
+			break
+		}
+
+		listings, err := c.getHotelIDsByCity(ctx, cityCode, r)
+		if err == nil {
+			// Only ask for offers from hotels the smaller radius didn't already cover
+			fresh := make([]string, 0, len(listings))
+			for _, l := range listings {
+				if !seen[l.ID] {
+					seen[l.ID] = true
+					listed[l.ID] = l
+					fresh = append(fresh, l.ID)
+				}
+			}
+			if len(fresh) > maxHotels {
+				fresh = fresh[:maxHotels]
+			}
+			if len(fresh) > 0 {
+				var found []Hotel
+				if found, err = c.getHotelOffersBatched(ctx, fresh, checkIn, checkOut, adults, currency, filter.BoardType); err == nil {
+					hotels = append(hotels, found...)
+				}
+			}
+		}
+		if err != nil {
+			if i == 0 {
+				return nil, 0, fmt.Errorf("hotel list failed: %w", err)
+			}
+			Logf(ctx, "⚠️  Hotel search at %d km failed: %v — keeping %d km results", r, err, radius)
+			break
+		}
+
+		radius = r
+		matching := countMatching(hotels, filter)
+		if matching >= minResults {
+			break
+		}
+		if i+1 < len(hotelRadiiKM) && hotelRadiiKM[i+1] <= maxRadius {
+			Logf(ctx, "ℹ️  Only %d matching hotels with offers within %d km of %s — widening search", matching, r, cityCode)
+		}
 	}
-	if len(hotelIDs) == 0 {
-		return nil, fmt.Errorf("no hotels found for city %s", cityCode)
+
+	if len(seen) == 0 {
+		return nil, radius, fmt.Errorf("no hotels found for city %s: %w", cityCode, ErrNoOffers)
 	}
-	if len(hotelIDs) > 20 {
-		hotelIDs = hotelIDs[:20]
+	for i := range hotels {
+		if l, ok := listed[hotels[i].HotelID]; ok {
+			hotels[i].Latitude, hotels[i].Longitude = l.Latitude, l.Longitude
+		}
 	}
-	return c.getHotelOffers(hotelIDs, checkIn, checkOut, adults)
+	setDistances(hotels, cityCode)
+	return hotels, radius, nil
+}
+
+// GetHotelRates returns a single hotel with all of its room offers (board type,
+// refundability, price) instead of only the best rate.
+func (c *AmadeusClient) GetHotelRates(ctx context.Context, hotelID, checkIn, checkOut string, adults int, currency string) (*Hotel, error) {
+	if c.clientID == "" {
+		return nil, ErrAmadeusNotConfigured
+	}
+
+	hotels, err := c.getHotelOffers(ctx, []string{hotelID}, checkIn, checkOut, adults, currency, "", false)
+	if err != nil {
+		return nil, err
+	}
+	if len(hotels) == 0 {
+		return nil, fmt.Errorf("no offers available for hotel %s", hotelID)
+	}
+	return &hotels[0], nil
 }
 
 type amadeusHotelListResponse struct {
 	Data []struct {
 		HotelID string `json:"hotelId"`
+		GeoCode struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"geoCode"`
 	} `json:"data"`
 }
 
-func (c *AmadeusClient) getHotelIDsByCity(cityCode string) ([]string, error) {
+// hotelListing is a hotel from the by-city list: its ID and where it is.
+type hotelListing struct {
+	ID        string
+	Latitude  float64
+	Longitude float64
+}
+
+func (c *AmadeusClient) getHotelIDsByCity(ctx context.Context, cityCode string, radiusKM int) ([]hotelListing, error) {
 	hotelCityCode := airportToCity(cityCode)
-	path := fmt.Sprintf("/v1/reference-data/locations/hotels/by-city?cityCode=%s&radius=5&radiusUnit=KM&hotelSource=ALL", url.QueryEscape(hotelCityCode))
+	path := fmt.Sprintf("/v1/reference-data/locations/hotels/by-city?cityCode=%s&radius=%d&radiusUnit=KM&hotelSource=ALL", url.QueryEscape(hotelCityCode), radiusKM)
 
-	body, err := c.doRequest("GET", path, nil)
+	body, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -390,11 +993,11 @@ func (c *AmadeusClient) getHotelIDsByCity(cityCode string) ([]string, error) {
 		return nil, fmt.Errorf("failed to parse hotel list: %w", err)
 	}
 
-	ids := make([]string, 0, len(resp.Data))
+	listings := make([]hotelListing, 0, len(resp.Data))
 	for _, h := range resp.Data {
-		ids = append(ids, h.HotelID)
+		listings = append(listings, hotelListing{ID: h.HotelID, Latitude: h.GeoCode.Latitude, Longitude: h.GeoCode.Longitude})
 	}
-	return ids, nil
+	return listings, nil
 }
 
 type amadeusHotelOffersResponse struct {
@@ -408,9 +1011,27 @@ type amadeusHotelOffersResponse struct {
 				CountryCode string `json:"countryCode"`
 			} `json:"address"`
 			Rating string `json:"rating"`
+			Media  []struct {
+				URI string `json:"uri"`
+			} `json:"media"`
 		} `json:"hotel"`
 		Available bool `json:"available"`
 		Offers    []struct {
+			ID        string `json:"id"`
+			BoardType string `json:"boardType"`
+			Room      struct {
+				Description struct {
+					Text string `json:"text"`
+				} `json:"description"`
+			} `json:"room"`
+			Policies struct {
+				Refundable struct {
+					CancellationRefund string `json:"cancellationRefund"`
+				} `json:"refundable"`
+				Cancellations []struct {
+					Deadline string `json:"deadline"`
+				} `json:"cancellations"`
+			} `json:"policies"`
 			Price struct {
 				Total    string `json:"total"`
 				Currency string `json:"currency"`
@@ -419,13 +1040,58 @@ type amadeusHotelOffersResponse struct {
 	} `json:"data"`
 }
 
-func (c *AmadeusClient) getHotelOffers(hotelIDs []string, checkIn, checkOut string, adults int) ([]Hotel, error) {
-	path := fmt.Sprintf("/v3/shopping/hotel-offers?hotelIds=%s&checkInDate=%s&checkOutDate=%s&adults=%d&roomQuantity=1&currency=USD&bestRateOnly=true",
+// hotelOfferBatchSize is how many hotel IDs go in one offers request. Amadeus fails a whole
+// request over a single bad ID, so small batches lose only the hotels batched with it.
+const hotelOfferBatchSize = 5
+
+// getHotelOffersBatched prices hotelIDs' best rates in concurrent batches of
+// hotelOfferBatchSize, keeping the hotels of every batch that succeeded in hotelIDs' order.
+// It only fails when every batch did.
+func (c *AmadeusClient) getHotelOffersBatched(ctx context.Context, hotelIDs []string, checkIn, checkOut string, adults int, currency, boardType string) ([]Hotel, error) {
+	var batches [][]string
+	for start := 0; start < len(hotelIDs); start += hotelOfferBatchSize {
+		batches = append(batches, hotelIDs[start:min(start+hotelOfferBatchSize, len(hotelIDs))])
+	}
+
+	found := make([][]Hotel, len(batches))
+	errs := make([]error, len(batches))
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			found[i], errs[i] = c.getHotelOffers(ctx, batch, checkIn, checkOut, adults, currency, boardType, true)
+		}(i, batch)
+	}
+	wg.Wait()
+
+	var hotels []Hotel
+	failed := 0
+	for i := range batches {
+		if errs[i] != nil {
+			failed++
+			Logf(ctx, "⚠️  Hotel offers for %s failed: %v — skipping those hotels", strings.Join(batches[i], ","), errs[i])
+			continue
+		}
+		hotels = append(hotels, found[i]...)
+	}
+	if failed == len(batches) && failed > 0 {
+		return nil, errs[0]
+	}
+	return hotels, nil
+}
+
+// getHotelOffers prices hotelIDs for the stay; a non-empty boardType only returns offers with that meal plan.
+func (c *AmadeusClient) getHotelOffers(ctx context.Context, hotelIDs []string, checkIn, checkOut string, adults int, currency, boardType string, bestRateOnly bool) ([]Hotel, error) {
+	path := fmt.Sprintf("/v3/shopping/hotel-offers?hotelIds=%s&checkInDate=%s&checkOutDate=%s&adults=%d&roomQuantity=1&currency=%s&bestRateOnly=%t",
 		url.QueryEscape(strings.Join(hotelIDs, ",")),
-		url.QueryEscape(checkIn), url.QueryEscape(checkOut), adults,
+		url.QueryEscape(checkIn), url.QueryEscape(checkOut), adults, url.QueryEscape(currency), bestRateOnly,
 	)
+	if boardType != "" {
+		path += "&boardType=" + url.QueryEscape(boardType)
+	}
 
-	body, err := c.doRequest("GET", path, nil)
+	body, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("hotel offers failed: %w", err)
 	}
@@ -448,18 +1114,59 @@ func (c *AmadeusClient) getHotelOffers(hotelIDs []string, checkIn, checkOut stri
 		if location == "" {
 			location = item.Hotel.CityCode
 		}
-		hotels = append(hotels, Hotel{
-			Name:     item.Hotel.Name,
-			HotelID:  item.Hotel.HotelID,
-			Price:    price,
-			Rating:   parseRating(item.Hotel.Rating),
-			Location: location,
-			Currency: item.Offers[0].Price.Currency,
-		})
+		amount, converted := normalizeCurrency(NewMoney(price, item.Offers[0].Price.Currency), currency, "hotel offer")
+		hotel := Hotel{
+			Name:             item.Hotel.Name,
+			HotelID:          item.Hotel.HotelID,
+			Price:            amount,
+			Rating:           parseRating(item.Hotel.Rating),
+			Location:         location,
+			Currency:         amount.Currency,
+			BoardType:        offerBoardType(item.Offers[0].BoardType),
+			CurrencyMismatch: !converted,
+		}
+		for _, m := range item.Hotel.Media {
+			if strings.HasPrefix(m.URI, "https://") || strings.HasPrefix(m.URI, "http://") {
+				hotel.PhotoURL = m.URI
+				break
+			}
+		}
+
+		if !bestRateOnly {
+			for _, offer := range item.Offers {
+				if len(hotel.Rates) >= maxRateOptions {
+					break
+				}
+				ratePrice := parsePrice(offer.Price.Total)
+				if ratePrice <= 0 {
+					continue
+				}
+				refund := offer.Policies.Refundable.CancellationRefund
+				rateAmount, _ := normalizeCurrency(NewMoney(ratePrice, offer.Price.Currency), currency, "hotel rate")
+				hotel.Rates = append(hotel.Rates, RateOption{
+					OfferID:     offer.ID,
+					BoardType:   offerBoardType(offer.BoardType),
+					Refundable:  (refund != "" && refund != "NON_REFUNDABLE") || (refund == "" && len(offer.Policies.Cancellations) > 0),
+					Price:       rateAmount,
+					Currency:    rateAmount.Currency,
+					Description: offer.Room.Description.Text,
+				})
+			}
+		}
+
+		hotels = append(hotels, hotel)
 	}
 	return hotels, nil
 }
 
+// offerBoardType defaults an offer without a boardType to room-only, which is what Amadeus omits it for.
+func offerBoardType(boardType string) string {
+	if boardType == "" {
+		return "ROOM_ONLY"
+	}
+	return boardType
+}
+
 // ─── Rich Fallback Data ───────────────────────────────────────────────────────
 
 type routeData struct {
@@ -478,6 +1185,8 @@ type fallbackAirline struct {
 	retHour     int
 }
 
+// knownRoutes are the estimated fares of the routes we know, by "ORIGIN-DESTINATION".
+// FALLBACK_DATA_PATH can add routes or replace these.
 var knownRoutes = map[string]routeData{
 	"TAS-IST": {310, 295, []fallbackAirline{
 		{"Turkish Airlines", "TK", "TK315", 1.00, 0, 6, 14},
@@ -663,24 +1372,48 @@ var knownRoutes = map[string]routeData{
 	}},
 }
 
+// fallbackStayNights is the stay assumed when an estimate has no usable return date.
+const fallbackStayNights = 7
+
 // GenerateFlightsFallback produces highly realistic flight data without an API key.
-func GenerateFlightsFallback(origin, destination, departureDate, returnDate string) []Flight {
-	key := origin + "-" + destination
-	route, ok := knownRoutes[key]
+// Prices are for the whole party in pax, like live offers, and scale with cabinClass (empty
+// means economy); nonStopOnly drops connecting options, which can leave none on long routes.
+// An unparsable departure date gives no flights; a missing, unparsable or earlier return
+// date is taken as fallbackStayNights after departure.
+func GenerateFlightsFallback(origin, destination, departureDate, returnDate, cabinClass string, nonStopOnly bool, pax PassengerMix) []Flight {
+	depDate, err := time.Parse("2006-01-02", departureDate)
+	if err != nil {
+		log.Printf("⚠️  No estimated flights %s-%s: invalid departure date %q", origin, destination, departureDate)
+		return nil
+	}
+	retDate, err := time.Parse("2006-01-02", returnDate)
+	if err != nil || retDate.Before(depDate) {
+		retDate = depDate.AddDate(0, 0, fallbackStayNights)
+	}
+
+	route, ok := knownRoutes[origin+"-"+destination]
 	if !ok {
 		route = estimateRoute(origin, destination)
 	}
+	return routeFallbackFlights(route, depDate, retDate, cabinClass, nonStopOnly, pax)
+}
 
-	depDate, _ := time.Parse("2006-01-02", departureDate)
-	retDate, _ := time.Parse("2006-01-02", returnDate)
-
+// routeFallbackFlights prices and times one route's airlines for the given dates. There is
+// no clock or randomness involved, so the same route and dates always give the same flights.
+func routeFallbackFlights(route routeData, depDate, retDate time.Time, cabinClass string, nonStopOnly bool, pax PassengerMix) []Flight {
+	if cabinClass == "" {
+		cabinClass = "ECONOMY"
+	}
 	flights := make([]Flight, 0, len(route.airlines))
 	for _, opt := range route.airlines {
+		if nonStopOnly && opt.stops > 0 {
+			continue
+		}
 		dur := route.durationM
 		if opt.stops > 0 {
 			dur += 85
 		}
-		price := math.Round(float64(route.basePrice)*opt.priceFactor/5) * 5
+		fares := estimatedFareBreakdown(float64(route.basePrice)*opt.priceFactor*cabinPriceFactor(cabinClass), pax)
 
 		depTime := time.Date(depDate.Year(), depDate.Month(), depDate.Day(), opt.depHour, 25, 0, 0, time.UTC)
 		arrTime := depTime.Add(time.Duration(dur) * time.Minute)
@@ -688,19 +1421,27 @@ func GenerateFlightsFallback(origin, destination, departureDate, returnDate stri
 		retArrTime := retDepTime.Add(time.Duration(dur) * time.Minute)
 
 		flights = append(flights, Flight{
-			Price:               price,
-			Airline:             opt.name,
-			AirlineCode:         opt.code,
-			FlightNumber:        opt.flightNum,
-			DepartureTime:       depTime.Format(time.RFC3339),
-			ArrivalTime:         arrTime.Format(time.RFC3339),
-			Duration:            formatDurationMin(dur),
-			Stops:               opt.stops,
-			ReturnDepartureTime: retDepTime.Format(time.RFC3339),
-			ReturnArrivalTime:   retArrTime.Format(time.RFC3339),
-			ReturnDuration:      formatDurationMin(dur),
-			ReturnStops:         opt.stops,
-			Currency:            "USD",
+			Price:                 fareBreakdownTotal(fares),
+			FareBreakdown:         fares,
+			Airline:               opt.name,
+			AirlineCode:           opt.code,
+			FlightNumber:          opt.flightNum,
+			DepartureTime:         depTime.Format(time.RFC3339),
+			ArrivalTime:           arrTime.Format(time.RFC3339),
+			Duration:              formatDurationMin(dur),
+			Stops:                 opt.stops,
+			ReturnDepartureTime:   retDepTime.Format(time.RFC3339),
+			ReturnArrivalTime:     retArrTime.Format(time.RFC3339),
+			ReturnDuration:        formatDurationMin(dur),
+			DurationMinutes:       dur,
+			ReturnDurationMinutes: dur,
+			ReturnStops:           opt.stops,
+			FareRulesLink:         fareRulesLink(opt.code),
+			Currency:              "USD",
+			Estimated:             true,
+			CabinClass:            cabinClass,
+			CO2Kg:                 2 * estimateCO2Kg(dur, opt.stops),
+			BaggageAllowance:      estimateBaggage(opt.code, cabinClass),
 		})
 	}
 	return flights
@@ -708,13 +1449,16 @@ func GenerateFlightsFallback(origin, destination, departureDate, returnDate stri
 
 // GenerateMultiCityFallback generates flights where the return leg departs from a different city.
 // For standard round-trips (returnOrigin == destination), it delegates to GenerateFlightsFallback.
-func GenerateMultiCityFallback(outOrigin, outDest, retOrigin, retDest, departureDate, returnDate string) []Flight {
+func GenerateMultiCityFallback(outOrigin, outDest, retOrigin, retDest, departureDate, returnDate, cabinClass string, nonStopOnly bool, pax PassengerMix) []Flight {
 	if retOrigin == outDest {
-		return GenerateFlightsFallback(outOrigin, outDest, departureDate, returnDate)
+		return GenerateFlightsFallback(outOrigin, outDest, departureDate, returnDate, cabinClass, nonStopOnly, pax)
 	}
 	// Build outbound and return independently, then combine
-	outFlights := GenerateFlightsFallback(outOrigin, outDest, departureDate, returnDate)
-	retFlights := GenerateFlightsFallback(retOrigin, retDest, returnDate, returnDate)
+	outFlights := GenerateFlightsFallback(outOrigin, outDest, departureDate, returnDate, cabinClass, nonStopOnly, pax)
+	retFlights := GenerateFlightsFallback(retOrigin, retDest, returnDate, returnDate, cabinClass, nonStopOnly, pax)
+	if len(retFlights) == 0 {
+		return nil
+	}
 
 	combined := make([]Flight, 0, len(outFlights))
 	for i, out := range outFlights {
@@ -722,11 +1466,18 @@ func GenerateMultiCityFallback(outOrigin, outDest, retOrigin, retDest, departure
 		if i < len(retFlights) {
 			ret = retFlights[i]
 		}
-		out.Price = out.Price + ret.Price
+		out.Price = out.Price.Add(ret.Price)
+		out.FareBreakdown = addFareBreakdowns(out.FareBreakdown, ret.FareBreakdown)
 		out.ReturnDepartureTime = ret.DepartureTime
 		out.ReturnArrivalTime = ret.ArrivalTime
+		out.ReturnDepartureTimeUTC = ret.DepartureTimeUTC
+		out.ReturnArrivalTimeUTC = ret.ArrivalTimeUTC
 		out.ReturnDuration = ret.Duration
+		out.ReturnDurationMinutes = ret.DurationMinutes
 		out.ReturnStops = ret.Stops
+		out.ReturnLayovers = ret.Layovers
+		out.CO2Kg = flightCO2Kg(out)
+		out.BaggageAllowance = smallerBaggage(out.BaggageAllowance, ret.BaggageAllowance)
 		combined = append(combined, out)
 	}
 	return combined
@@ -771,153 +1522,160 @@ func estimateRoute(origin, destination string) routeData {
 	}
 }
 
+func fallbackHotel(name string, price, rating float64, location string) Hotel {
+	return Hotel{Name: name, Price: usd(price), Rating: rating, Location: location, Currency: "USD", Estimated: true}
+}
+
+// fallbackCityHotels are the estimated hotels of the cities we know, by IATA code.
+// FALLBACK_DATA_PATH can add cities or replace these.
+var fallbackCityHotels = map[string][]Hotel{
+	"IST": {
+		fallbackHotel("Grand Hyatt Istanbul", 189, 4.7, "Taksim, Istanbul"),
+		fallbackHotel("Hilton Istanbul Bosphorus", 172, 4.5, "Beşiktaş, Istanbul"),
+		fallbackHotel("The Marmara Taksim", 145, 4.4, "Taksim Square, Istanbul"),
+		fallbackHotel("Sultan Ahmet Palace Hotel", 99, 4.3, "Sultanahmet, Istanbul"),
+		fallbackHotel("ibis Istanbul Taksim", 72, 4.0, "Taksim, Istanbul"),
+	},
+	"CDG": {
+		fallbackHotel("Hôtel Le Marais Bastille", 225, 4.6, "Le Marais, Paris"),
+		fallbackHotel("Pullman Paris Tour Eiffel", 285, 4.5, "7th Arr., Paris"),
+		fallbackHotel("Hôtel des Arts Montmartre", 135, 4.3, "Montmartre, Paris"),
+		fallbackHotel("ibis Paris Opéra", 98, 4.0, "9th Arr., Paris"),
+		fallbackHotel("Generator Paris", 58, 3.8, "10th Arr., Paris"),
+	},
+	"PAR": {
+		fallbackHotel("Hôtel Le Marais Bastille", 225, 4.6, "Le Marais, Paris"),
+		fallbackHotel("Pullman Paris Tour Eiffel", 285, 4.5, "7th Arr., Paris"),
+		fallbackHotel("Hôtel des Arts Montmartre", 135, 4.3, "Montmartre, Paris"),
+		fallbackHotel("ibis Paris Opéra", 98, 4.0, "9th Arr., Paris"),
+		fallbackHotel("Generator Paris", 58, 3.8, "10th Arr., Paris"),
+	},
+	"LHR": {
+		fallbackHotel("Hilton London Tower Bridge", 185, 4.4, "Tower Bridge, London"),
+		fallbackHotel("The Hoxton Shoreditch", 168, 4.5, "Shoreditch, London"),
+		fallbackHotel("citizenM London Bankside", 148, 4.4, "Bankside, London"),
+		fallbackHotel("Premier Inn London City", 97, 4.1, "City of London"),
+		fallbackHotel("Generator London", 52, 3.8, "Russell Square, London"),
+	},
+	"LON": {
+		fallbackHotel("Hilton London Tower Bridge", 185, 4.4, "Tower Bridge, London"),
+		fallbackHotel("The Hoxton Shoreditch", 168, 4.5, "Shoreditch, London"),
+		fallbackHotel("citizenM London Bankside", 148, 4.4, "Bankside, London"),
+		fallbackHotel("Premier Inn London City", 97, 4.1, "City of London"),
+		fallbackHotel("Generator London", 52, 3.8, "Russell Square, London"),
+	},
+	"DXB": {
+		fallbackHotel("JW Marriott Marquis Dubai", 228, 4.6, "Business Bay, Dubai"),
+		fallbackHotel("Hilton Dubai Al Habtoor City", 165, 4.4, "Dubai Marina"),
+		fallbackHotel("Atlantis The Palm", 390, 4.7, "Palm Jumeirah, Dubai"),
+		fallbackHotel("Rove Downtown Dubai", 98, 4.3, "Downtown Dubai"),
+		fallbackHotel("Premier Inn Dubai Ibn Battuta", 68, 4.0, "Jebel Ali, Dubai"),
+	},
+	"FRA": {
+		fallbackHotel("Steigenberger Frankfurter Hof", 285, 4.6, "Kaiserplatz, Frankfurt"),
+		fallbackHotel("Hilton Frankfurt City Centre", 178, 4.5, "City Centre, Frankfurt"),
+		fallbackHotel("Marriott Frankfurt City Center", 158, 4.4, "Sachsenhausen, Frankfurt"),
+		fallbackHotel("Motel One Frankfurt-Römer", 91, 4.3, "Römer, Frankfurt"),
+		fallbackHotel("Generator Frankfurt", 48, 3.9, "Sachsenhausen, Frankfurt"),
+	},
+	"BER": {
+		fallbackHotel("Hotel Adlon Kempinski", 325, 4.8, "Unter den Linden, Berlin"),
+		fallbackHotel("Radisson Blu Berlin", 152, 4.4, "Alexanderplatz, Berlin"),
+		fallbackHotel("Michelberger Hotel", 132, 4.5, "Friedrichshain, Berlin"),
+		fallbackHotel("Motel One Berlin Hackescher Markt", 87, 4.2, "Mitte, Berlin"),
+		fallbackHotel("Generator Berlin Mitte", 46, 3.9, "Mitte, Berlin"),
+	},
+	"JFK": {
+		fallbackHotel("The Plaza Hotel", 590, 4.7, "Midtown, New York"),
+		fallbackHotel("Marriott Marquis Times Square", 315, 4.5, "Times Square, New York"),
+		fallbackHotel("citizenM New York Bowery", 189, 4.4, "Lower East Side, New York"),
+		fallbackHotel("ibis New York Midtown", 148, 4.1, "Midtown, New York"),
+		fallbackHotel("HI NYC Hostel", 65, 3.8, "Upper West Side, New York"),
+	},
+	"NYC": {
+		fallbackHotel("The Plaza Hotel", 590, 4.7, "Midtown, New York"),
+		fallbackHotel("Marriott Marquis Times Square", 315, 4.5, "Times Square, New York"),
+		fallbackHotel("citizenM New York Bowery", 189, 4.4, "Lower East Side, New York"),
+		fallbackHotel("ibis New York Midtown", 148, 4.1, "Midtown, New York"),
+		fallbackHotel("HI NYC Hostel", 65, 3.8, "Upper West Side, New York"),
+	},
+	"BKK": {
+		fallbackHotel("Mandarin Oriental Bangkok", 285, 4.8, "Charoennakorn, Bangkok"),
+		fallbackHotel("Chatrium Hotel Riverside", 148, 4.5, "Riverside, Bangkok"),
+		fallbackHotel("Novotel Bangkok Ploenchit", 118, 4.3, "Ploenchit, Bangkok"),
+		fallbackHotel("ibis Bangkok Sukhumvit", 72, 4.2, "Sukhumvit, Bangkok"),
+		fallbackHotel("Lub d Silom", 38, 4.0, "Silom, Bangkok"),
+	},
+	"SIN": {
+		fallbackHotel("Marina Bay Sands", 485, 4.7, "Marina Bay, Singapore"),
+		fallbackHotel("Fullerton Hotel Singapore", 368, 4.8, "Fullerton Square, Singapore"),
+		fallbackHotel("ibis Singapore on Bencoolen", 112, 4.1, "Bencoolen, Singapore"),
+		fallbackHotel("V Hotel Lavender", 88, 4.0, "Lavender, Singapore"),
+		fallbackHotel("Wink Hostel", 42, 4.2, "Chinatown, Singapore"),
+	},
+	"NRT": {
+		fallbackHotel("Park Hyatt Tokyo", 520, 4.8, "Shinjuku, Tokyo"),
+		fallbackHotel("Shinjuku Granbell Hotel", 148, 4.4, "Shinjuku, Tokyo"),
+		fallbackHotel("ibis Tokyo Shinjuku", 95, 4.1, "Shinjuku, Tokyo"),
+		fallbackHotel("UNPLAN Shinjuku", 58, 4.3, "Shinjuku, Tokyo"),
+		fallbackHotel("APA Hotel Shinjuku Kabukicho", 78, 4.0, "Kabukicho, Tokyo"),
+	},
+	"TYO": {
+		fallbackHotel("Park Hyatt Tokyo", 520, 4.8, "Shinjuku, Tokyo"),
+		fallbackHotel("Shinjuku Granbell Hotel", 148, 4.4, "Shinjuku, Tokyo"),
+		fallbackHotel("ibis Tokyo Shinjuku", 95, 4.1, "Shinjuku, Tokyo"),
+		fallbackHotel("UNPLAN Shinjuku", 58, 4.3, "Shinjuku, Tokyo"),
+		fallbackHotel("APA Hotel Shinjuku Kabukicho", 78, 4.0, "Kabukicho, Tokyo"),
+	},
+	"MAD": {
+		fallbackHotel("Hotel Ritz Madrid", 348, 4.8, "Paseo del Prado, Madrid"),
+		fallbackHotel("NH Collection Madrid Gran Vía", 165, 4.5, "Gran Vía, Madrid"),
+		fallbackHotel("Only YOU Hotel Atocha", 195, 4.6, "Atocha, Madrid"),
+		fallbackHotel("ibis Madrid Centro", 82, 4.0, "Lavapiés, Madrid"),
+		fallbackHotel("Generator Madrid", 48, 3.9, "Chueca, Madrid"),
+	},
+	"BCN": {
+		fallbackHotel("Hotel Arts Barcelona", 385, 4.7, "Barceloneta, Barcelona"),
+		fallbackHotel("Novotel Barcelona City", 158, 4.4, "Eixample, Barcelona"),
+		fallbackHotel("Yurbban Passage Hotel", 135, 4.5, "El Born, Barcelona"),
+		fallbackHotel("ibis Barcelona Centro", 85, 4.0, "Gothic Quarter, Barcelona"),
+		fallbackHotel("Generator Barcelona", 46, 3.8, "Gràcia, Barcelona"),
+	},
+	"AMS": {
+		fallbackHotel("Sofitel Legend The Grand Amsterdam", 398, 4.8, "Old Centre, Amsterdam"),
+		fallbackHotel("Mövenpick Hotel Amsterdam City Centre", 168, 4.4, "Eastern Docklands, Amsterdam"),
+		fallbackHotel("The Student Hotel Amsterdam City", 135, 4.3, "Amsterdam West"),
+		fallbackHotel("ibis Amsterdam Centre", 105, 4.1, "De Wallen, Amsterdam"),
+		fallbackHotel("Generator Amsterdam", 52, 3.9, "Oost, Amsterdam"),
+	},
+	"FCO": {
+		fallbackHotel("Hotel de Russie", 425, 4.8, "Piazza del Popolo, Rome"),
+		fallbackHotel("Colosseum Hotel", 128, 4.3, "Colosseo, Rome"),
+		fallbackHotel("Bettoja Hotel Massimo D'Azeglio", 165, 4.4, "Termini, Rome"),
+		fallbackHotel("ibis Roma Tiburtina", 78, 4.0, "Tiburtina, Rome"),
+		fallbackHotel("Generator Rome", 44, 3.8, "Termini, Rome"),
+	},
+}
+
 // GenerateHotelsFallback produces realistic hotel data for major cities.
 func GenerateHotelsFallback(destination string) []Hotel {
-	cityHotels := map[string][]Hotel{
-		"IST": {
-			{"Grand Hyatt Istanbul", "", 189, 4.7, "Taksim, Istanbul", "", "USD"},
-			{"Hilton Istanbul Bosphorus", "", 172, 4.5, "Beşiktaş, Istanbul", "", "USD"},
-			{"The Marmara Taksim", "", 145, 4.4, "Taksim Square, Istanbul", "", "USD"},
-			{"Sultan Ahmet Palace Hotel", "", 99, 4.3, "Sultanahmet, Istanbul", "", "USD"},
-			{"ibis Istanbul Taksim", "", 72, 4.0, "Taksim, Istanbul", "", "USD"},
-		},
-		"CDG": {
-			{"Hôtel Le Marais Bastille", "", 225, 4.6, "Le Marais, Paris", "", "USD"},
-			{"Pullman Paris Tour Eiffel", "", 285, 4.5, "7th Arr., Paris", "", "USD"},
-			{"Hôtel des Arts Montmartre", "", 135, 4.3, "Montmartre, Paris", "", "USD"},
-			{"ibis Paris Opéra", "", 98, 4.0, "9th Arr., Paris", "", "USD"},
-			{"Generator Paris", "", 58, 3.8, "10th Arr., Paris", "", "USD"},
-		},
-		"PAR": {
-			{"Hôtel Le Marais Bastille", "", 225, 4.6, "Le Marais, Paris", "", "USD"},
-			{"Pullman Paris Tour Eiffel", "", 285, 4.5, "7th Arr., Paris", "", "USD"},
-			{"Hôtel des Arts Montmartre", "", 135, 4.3, "Montmartre, Paris", "", "USD"},
-			{"ibis Paris Opéra", "", 98, 4.0, "9th Arr., Paris", "", "USD"},
-			{"Generator Paris", "", 58, 3.8, "10th Arr., Paris", "", "USD"},
-		},
-		"LHR": {
-			{"Hilton London Tower Bridge", "", 185, 4.4, "Tower Bridge, London", "", "USD"},
-			{"The Hoxton Shoreditch", "", 168, 4.5, "Shoreditch, London", "", "USD"},
-			{"citizenM London Bankside", "", 148, 4.4, "Bankside, London", "", "USD"},
-			{"Premier Inn London City", "", 97, 4.1, "City of London", "", "USD"},
-			{"Generator London", "", 52, 3.8, "Russell Square, London", "", "USD"},
-		},
-		"LON": {
-			{"Hilton London Tower Bridge", "", 185, 4.4, "Tower Bridge, London", "", "USD"},
-			{"The Hoxton Shoreditch", "", 168, 4.5, "Shoreditch, London", "", "USD"},
-			{"citizenM London Bankside", "", 148, 4.4, "Bankside, London", "", "USD"},
-			{"Premier Inn London City", "", 97, 4.1, "City of London", "", "USD"},
-			{"Generator London", "", 52, 3.8, "Russell Square, London", "", "USD"},
-		},
-		"DXB": {
-			{"JW Marriott Marquis Dubai", "", 228, 4.6, "Business Bay, Dubai", "", "USD"},
-			{"Hilton Dubai Al Habtoor City", "", 165, 4.4, "Dubai Marina", "", "USD"},
-			{"Atlantis The Palm", "", 390, 4.7, "Palm Jumeirah, Dubai", "", "USD"},
-			{"Rove Downtown Dubai", "", 98, 4.3, "Downtown Dubai", "", "USD"},
-			{"Premier Inn Dubai Ibn Battuta", "", 68, 4.0, "Jebel Ali, Dubai", "", "USD"},
-		},
-		"FRA": {
-			{"Steigenberger Frankfurter Hof", "", 285, 4.6, "Kaiserplatz, Frankfurt", "", "USD"},
-			{"Hilton Frankfurt City Centre", "", 178, 4.5, "City Centre, Frankfurt", "", "USD"},
-			{"Marriott Frankfurt City Center", "", 158, 4.4, "Sachsenhausen, Frankfurt", "", "USD"},
-			{"Motel One Frankfurt-Römer", "", 91, 4.3, "Römer, Frankfurt", "", "USD"},
-			{"Generator Frankfurt", "", 48, 3.9, "Sachsenhausen, Frankfurt", "", "USD"},
-		},
-		"BER": {
-			{"Hotel Adlon Kempinski", "", 325, 4.8, "Unter den Linden, Berlin", "", "USD"},
-			{"Radisson Blu Berlin", "", 152, 4.4, "Alexanderplatz, Berlin", "", "USD"},
-			{"Michelberger Hotel", "", 132, 4.5, "Friedrichshain, Berlin", "", "USD"},
-			{"Motel One Berlin Hackescher Markt", "", 87, 4.2, "Mitte, Berlin", "", "USD"},
-			{"Generator Berlin Mitte", "", 46, 3.9, "Mitte, Berlin", "", "USD"},
-		},
-		"JFK": {
-			{"The Plaza Hotel", "", 590, 4.7, "Midtown, New York", "", "USD"},
-			{"Marriott Marquis Times Square", "", 315, 4.5, "Times Square, New York", "", "USD"},
-			{"citizenM New York Bowery", "", 189, 4.4, "Lower East Side, New York", "", "USD"},
-			{"ibis New York Midtown", "", 148, 4.1, "Midtown, New York", "", "USD"},
-			{"HI NYC Hostel", "", 65, 3.8, "Upper West Side, New York", "", "USD"},
-		},
-		"NYC": {
-			{"The Plaza Hotel", "", 590, 4.7, "Midtown, New York", "", "USD"},
-			{"Marriott Marquis Times Square", "", 315, 4.5, "Times Square, New York", "", "USD"},
-			{"citizenM New York Bowery", "", 189, 4.4, "Lower East Side, New York", "", "USD"},
-			{"ibis New York Midtown", "", 148, 4.1, "Midtown, New York", "", "USD"},
-			{"HI NYC Hostel", "", 65, 3.8, "Upper West Side, New York", "", "USD"},
-		},
-		"BKK": {
-			{"Mandarin Oriental Bangkok", "", 285, 4.8, "Charoennakorn, Bangkok", "", "USD"},
-			{"Chatrium Hotel Riverside", "", 148, 4.5, "Riverside, Bangkok", "", "USD"},
-			{"Novotel Bangkok Ploenchit", "", 118, 4.3, "Ploenchit, Bangkok", "", "USD"},
-			{"ibis Bangkok Sukhumvit", "", 72, 4.2, "Sukhumvit, Bangkok", "", "USD"},
-			{"Lub d Silom", "", 38, 4.0, "Silom, Bangkok", "", "USD"},
-		},
-		"SIN": {
-			{"Marina Bay Sands", "", 485, 4.7, "Marina Bay, Singapore", "", "USD"},
-			{"Fullerton Hotel Singapore", "", 368, 4.8, "Fullerton Square, Singapore", "", "USD"},
-			{"ibis Singapore on Bencoolen", "", 112, 4.1, "Bencoolen, Singapore", "", "USD"},
-			{"V Hotel Lavender", "", 88, 4.0, "Lavender, Singapore", "", "USD"},
-			{"Wink Hostel", "", 42, 4.2, "Chinatown, Singapore", "", "USD"},
-		},
-		"NRT": {
-			{"Park Hyatt Tokyo", "", 520, 4.8, "Shinjuku, Tokyo", "", "USD"},
-			{"Shinjuku Granbell Hotel", "", 148, 4.4, "Shinjuku, Tokyo", "", "USD"},
-			{"ibis Tokyo Shinjuku", "", 95, 4.1, "Shinjuku, Tokyo", "", "USD"},
-			{"UNPLAN Shinjuku", "", 58, 4.3, "Shinjuku, Tokyo", "", "USD"},
-			{"APA Hotel Shinjuku Kabukicho", "", 78, 4.0, "Kabukicho, Tokyo", "", "USD"},
-		},
-		"TYO": {
-			{"Park Hyatt Tokyo", "", 520, 4.8, "Shinjuku, Tokyo", "", "USD"},
-			{"Shinjuku Granbell Hotel", "", 148, 4.4, "Shinjuku, Tokyo", "", "USD"},
-			{"ibis Tokyo Shinjuku", "", 95, 4.1, "Shinjuku, Tokyo", "", "USD"},
-			{"UNPLAN Shinjuku", "", 58, 4.3, "Shinjuku, Tokyo", "", "USD"},
-			{"APA Hotel Shinjuku Kabukicho", "", 78, 4.0, "Kabukicho, Tokyo", "", "USD"},
-		},
-		"MAD": {
-			{"Hotel Ritz Madrid", "", 348, 4.8, "Paseo del Prado, Madrid", "", "USD"},
-			{"NH Collection Madrid Gran Vía", "", 165, 4.5, "Gran Vía, Madrid", "", "USD"},
-			{"Only YOU Hotel Atocha", "", 195, 4.6, "Atocha, Madrid", "", "USD"},
-			{"ibis Madrid Centro", "", 82, 4.0, "Lavapiés, Madrid", "", "USD"},
-			{"Generator Madrid", "", 48, 3.9, "Chueca, Madrid", "", "USD"},
-		},
-		"BCN": {
-			{"Hotel Arts Barcelona", "", 385, 4.7, "Barceloneta, Barcelona", "", "USD"},
-			{"Novotel Barcelona City", "", 158, 4.4, "Eixample, Barcelona", "", "USD"},
-			{"Yurbban Passage Hotel", "", 135, 4.5, "El Born, Barcelona", "", "USD"},
-			{"ibis Barcelona Centro", "", 85, 4.0, "Gothic Quarter, Barcelona", "", "USD"},
-			{"Generator Barcelona", "", 46, 3.8, "Gràcia, Barcelona", "", "USD"},
-		},
-		"AMS": {
-			{"Sofitel Legend The Grand Amsterdam", "", 398, 4.8, "Old Centre, Amsterdam", "", "USD"},
-			{"Mövenpick Hotel Amsterdam City Centre", "", 168, 4.4, "Eastern Docklands, Amsterdam", "", "USD"},
-			{"The Student Hotel Amsterdam City", "", 135, 4.3, "Amsterdam West", "", "USD"},
-			{"ibis Amsterdam Centre", "", 105, 4.1, "De Wallen, Amsterdam", "", "USD"},
-			{"Generator Amsterdam", "", 52, 3.9, "Oost, Amsterdam", "", "USD"},
-		},
-		"FCO": {
-			{"Hotel de Russie", "", 425, 4.8, "Piazza del Popolo, Rome", "", "USD"},
-			{"Colosseum Hotel", "", 128, 4.3, "Colosseo, Rome", "", "USD"},
-			{"Bettoja Hotel Massimo D'Azeglio", "", 165, 4.4, "Termini, Rome", "", "USD"},
-			{"ibis Roma Tiburtina", "", 78, 4.0, "Tiburtina, Rome", "", "USD"},
-			{"Generator Rome", "", 44, 3.8, "Termini, Rome", "", "USD"},
-		},
-	}
-
-	if hotels, ok := cityHotels[destination]; ok {
-		return hotels
+	if hotels, ok := fallbackCityHotels[destination]; ok {
+		// A copy, since searches price and number their hotels in place.
+		return slices.Clone(hotels)
 	}
 
 	return []Hotel{
-		{"Grand Hotel " + destination, "", 178, 4.5, "City Center, " + destination, "", "USD"},
-		{"Marriott " + destination, "", 148, 4.4, "Business District, " + destination, "", "USD"},
-		{"ibis " + destination + " Centre", "", 88, 4.1, "Central " + destination, "", "USD"},
-		{"Boutique Residence " + destination, "", 122, 4.3, "Arts Quarter, " + destination, "", "USD"},
-		{"Generator " + destination, "", 48, 3.8, "Student Quarter, " + destination, "", "USD"},
+		fallbackHotel("Grand Hotel "+destination, 178, 4.5, "City Center, "+destination),
+		fallbackHotel("Marriott "+destination, 148, 4.4, "Business District, "+destination),
+		fallbackHotel("ibis "+destination+" Centre", 88, 4.1, "Central "+destination),
+		fallbackHotel("Boutique Residence "+destination, 122, 4.3, "Arts Quarter, "+destination),
+		fallbackHotel("Generator "+destination, 48, 3.8, "Student Quarter, "+destination),
 	}
 }
 
 // ─── Smart Built-in AI Summary ────────────────────────────────────────────────
 
-func SmartFallbackRecommendation(budget float64, origin, destination, departureDate, returnDate string, passengers int, flights []Flight, hotels []Hotel, returnOrigin string) string {
+func SmartFallbackRecommendation(budget Money, origin, destination, departureDate, returnDate string, pax PassengerMix, flights []Flight, hotels []Hotel, returnOrigin string) string {
 	if len(flights) == 0 || len(hotels) == 0 {
 		return "Unable to provide recommendations — no flight or hotel data available."
 	}
@@ -929,31 +1687,29 @@ func SmartFallbackRecommendation(budget float64, origin, destination, departureD
 		}
 	}
 
-	bestFlight := flights[0]
+	bestFlight := flights[bestValueFlight(flights)]
 	cheapest := flights[0]
 	premium := flights[0]
 	for _, f := range flights {
-		if f.Price < cheapest.Price { cheapest = f }
-		if f.Price > premium.Price { premium = f }
-		if f.Stops == 0 && f.Price < bestFlight.Price { bestFlight = f }
+		if f.Price.Less(cheapest.Price) { cheapest = f }
+		if premium.Price.Less(f.Price) { premium = f }
 	}
 
-	bestHotel := hotels[0]
+	bestHotel := hotels[bestValueHotel(hotels)]
 	luxuryHotel := hotels[0]
 	budgetHotel := hotels[0]
 	for _, h := range hotels {
-		if h.Price > luxuryHotel.Price { luxuryHotel = h }
-		if h.Price < budgetHotel.Price { budgetHotel = h }
-		if h.Rating/h.Price > bestHotel.Rating/bestHotel.Price { bestHotel = h }
+		if luxuryHotel.Price.Less(h.Price) { luxuryHotel = h }
+		if h.Price.Less(budgetHotel.Price) { budgetHotel = h }
 	}
 
-	// Flight price is per-person round-trip; multiply by passengers for total flight cost
-	totalBestValue := bestFlight.Price*float64(passengers) + bestHotel.Price*float64(numNights)
-	totalBudget := cheapest.Price*float64(passengers) + budgetHotel.Price*float64(numNights)
-	totalLuxury := premium.Price*float64(passengers) + luxuryHotel.Price*float64(numNights)
+	// Flight price is the round-trip total for the whole party, as Amadeus prices it
+	totalBestValue := bestFlight.Price.Add(bestHotel.Price.Mul(numNights))
+	totalBudget := cheapest.Price.Add(budgetHotel.Price.Mul(numNights))
+	totalLuxury := premium.Price.Add(luxuryHotel.Price.Mul(numNights))
 
 	budgetStatus := "within"
-	if totalBestValue > budget { budgetStatus = "slightly over" }
+	if budget.Less(totalBestValue) { budgetStatus = "slightly over" }
 
 	depFormatted := departureDate
 	if t, err := time.Parse("2006-01-02", departureDate); err == nil {
@@ -964,11 +1720,19 @@ func SmartFallbackRecommendation(budget float64, origin, destination, departureD
 		retFormatted = t.Format("Jan 2")
 	}
 
+	flightPrice := fmt.Sprintf("%s for %s", bestFlight.Price, pax)
+	if len(bestFlight.FareBreakdown) > 0 && pax.Total() > 1 {
+		flightPrice = flightPriceLabel(bestFlight)
+	}
+
+	stops, duration := quotedStopsAndDuration(bestFlight)
 	directLabel := "non-stop"
-	if bestFlight.Stops > 0 { directLabel = fmt.Sprintf("%d-stop", bestFlight.Stops) }
+	if stops > 0 { directLabel = fmt.Sprintf("%d-stop", stops) }
 
 	routeDesc := fmt.Sprintf("%s→%s", origin, destination)
-	if returnOrigin != "" && returnOrigin != destination {
+	if len(bestFlight.Legs) > 0 {
+		routeDesc = LegsRoute(bestFlight.Legs) + " (multi-city)"
+	} else if returnOrigin != "" && returnOrigin != destination {
 		routeDesc = fmt.Sprintf("%s→%s, returning %s→%s (multi-city)", origin, destination, returnOrigin, origin)
 	}
 
@@ -979,16 +1743,16 @@ func SmartFallbackRecommendation(budget float64, origin, destination, departureD
 	}
 
 	return fmt.Sprintf(
-		"✈ Flight: **%s** at $%.0f/person — a %s flight (%s) offering the best balance of price and convenience for your %s trip departing %s, returning %s.\n\n"+
-			"🏨 Hotel: **%s** at $%.0f/night in %s (★%.1f) is your best value stay. With %d night(s) this adds $%.0f to your total.\n\n"+
-			"💰 Budget Summary: Best-value combo comes to approximately **$%.0f** for %d passenger(s) — %s your $%.0f budget. "+
-			"Budget option: %s + %s ≈ $%.0f. Premium option: %s + %s ≈ $%.0f.%s",
-		bestFlight.Airline, bestFlight.Price,
-		directLabel, bestFlight.Duration,
+		"✈ Flight: **%s** at %s — a %s flight (%s) offering the best balance of price and convenience for your %s trip departing %s, returning %s.\n\n"+
+			"🏨 Hotel: **%s** at %s/night in %s (★%.1f) is your best value stay. With %d night(s) this adds %s to your total.\n\n"+
+			"💰 Budget Summary: Best-value combo comes to approximately **%s** for %s — %s your %s budget. "+
+			"Budget option: %s + %s ≈ %s. Premium option: %s + %s ≈ %s.%s",
+		bestFlight.Airline, flightPrice,
+		directLabel, duration,
 		routeDesc, depFormatted, retFormatted,
 		bestHotel.Name, bestHotel.Price, bestHotel.Location, bestHotel.Rating,
-		numNights, bestHotel.Price*float64(numNights),
-		totalBestValue, passengers, budgetStatus, budget,
+		numNights, bestHotel.Price.Mul(numNights),
+		totalBestValue, pax, budgetStatus, budget,
 		cheapest.Airline, budgetHotel.Name, totalBudget,
 		premium.Airline, luxuryHotel.Name, totalLuxury,
 		highlightNote,
@@ -996,25 +1760,25 @@ func SmartFallbackRecommendation(budget float64, origin, destination, departureD
 }
 
 // FallbackRecommendation kept for compatibility
-func FallbackRecommendation(budget float64, flights []Flight, hotels []Hotel, numNights int) string {
+func FallbackRecommendation(budget Money, flights []Flight, hotels []Hotel, numNights int) string {
 	if len(flights) == 0 || len(hotels) == 0 {
 		return "Unable to provide recommendations at this time."
 	}
 	cheapestFlight := flights[0]
 	for _, f := range flights {
-		if f.Price < cheapestFlight.Price { cheapestFlight = f }
+		if f.Price.Less(cheapestFlight.Price) { cheapestFlight = f }
 	}
 	bestValueHotel := hotels[0]
 	for _, h := range hotels {
-		if h.Price < bestValueHotel.Price { bestValueHotel = h }
+		if h.Price.Less(bestValueHotel.Price) { bestValueHotel = h }
 	}
-	total := cheapestFlight.Price + bestValueHotel.Price*float64(numNights)
-	withinBudget := fmt.Sprintf(" Estimated total: $%.0f fits your $%.0f budget.", total, budget)
-	if total > budget {
-		withinBudget = fmt.Sprintf(" Note: $%.0f total exceeds your $%.0f budget by $%.0f.", total, budget, total-budget)
+	total := cheapestFlight.Price.Add(bestValueHotel.Price.Mul(numNights))
+	withinBudget := fmt.Sprintf(" Estimated total: %s fits your %s budget.", total, budget)
+	if budget.Less(total) {
+		withinBudget = fmt.Sprintf(" Note: %s total exceeds your %s budget by %s.", total, budget, total.Sub(budget))
 	}
-	return fmt.Sprintf("Best picks: %s at $%.0f and %s at $%.0f/night (★%.1f).%s",
-		cheapestFlight.Airline, cheapestFlight.Price,
+	return fmt.Sprintf("Best picks: %s at %s and %s at %s/night (★%.1f).%s",
+		cheapestFlight.Airline, flightPriceLabel(cheapestFlight),
 		bestValueHotel.Name, bestValueHotel.Price, bestValueHotel.Rating,
 		withinBudget)
 }
@@ -1058,6 +1822,118 @@ func DestinationHighlights(destination string) string {
 
 // ─── Helpers ──────────────────────────────────────────────────────────────────
 
+// SplitFlights converts flat flights into outbound/return legs. Return is nil for one-way data.
+func SplitFlights(flights []Flight) []SplitFlight {
+	out := make([]SplitFlight, 0, len(flights))
+	for _, f := range flights {
+		sf := SplitFlight{
+			Price:       f.Price,
+			Currency:    f.Currency,
+			BookingLink: f.BookingLink,
+			CabinClass:  f.CabinClass,
+			Outbound:    outboundLeg(f),
+			Legs:        f.Legs,
+		}
+		if f.ReturnDepartureTime != "" {
+			sf.Return = &FlightLeg{
+				Airline:          f.Airline,
+				AirlineCode:      f.AirlineCode,
+				DepartureTime:    f.ReturnDepartureTime,
+				ArrivalTime:      f.ReturnArrivalTime,
+				Duration:         f.ReturnDuration,
+				Stops:            f.ReturnStops,
+				DepartureTimeUTC: f.ReturnDepartureTimeUTC,
+				ArrivalTimeUTC:   f.ReturnArrivalTimeUTC,
+				DurationMinutes:  f.ReturnDurationMinutes,
+				Layovers:         f.ReturnLayovers,
+			}
+		}
+		out = append(out, sf)
+	}
+	return out
+}
+
+// outboundLeg is f's outbound direction as a FlightLeg.
+func outboundLeg(f Flight) FlightLeg {
+	return FlightLeg{
+		Airline:          f.Airline,
+		AirlineCode:      f.AirlineCode,
+		FlightNumber:     f.FlightNumber,
+		DepartureTime:    f.DepartureTime,
+		ArrivalTime:      f.ArrivalTime,
+		Duration:         f.Duration,
+		Stops:            f.Stops,
+		DepartureTimeUTC: f.DepartureTimeUTC,
+		ArrivalTimeUTC:   f.ArrivalTimeUTC,
+		DurationMinutes:  f.DurationMinutes,
+		Layovers:         f.Layovers,
+	}
+}
+
+// normalizeCurrency converts a provider price to the requested currency when Amadeus ignored
+// it, and rounds it per PriceRounding. converted is false when no FX rate is configured for it.
+func normalizeCurrency(m Money, currency, what string) (Money, bool) {
+	if SameCurrency(m.Currency, currency) {
+		return m.Rounded(), true
+	}
+	converted, ok := convertCurrency(m, currency)
+	if !ok {
+		log.Printf("⚠️  Amadeus %s priced in %s despite currencyCode=%s and no FX rate is configured", what, m.Currency, currency)
+		return m.Rounded(), false
+	}
+	return converted.Rounded(), true
+}
+
+// PriceFlightsIn converts estimated flights (generated in USD) into currency.
+func PriceFlightsIn(flights []Flight, currency string) []Flight {
+	for i := range flights {
+		if price, ok := convertCurrency(flights[i].Price, currency); ok {
+			flights[i].Price = price.Rounded()
+			flights[i].Currency = flights[i].Price.Currency
+			// Convert each line and re-total, so the breakdown still adds up after rounding.
+			if fares, ok := convertFareBreakdown(flights[i].FareBreakdown, currency); ok && len(fares) > 0 {
+				flights[i].FareBreakdown = fares
+				flights[i].Price = fareBreakdownTotal(fares)
+			}
+		}
+	}
+	return flights
+}
+
+// PriceHotelsIn is the hotel counterpart of PriceFlightsIn.
+func PriceHotelsIn(hotels []Hotel, currency string) []Hotel {
+	for i := range hotels {
+		if price, ok := convertCurrency(hotels[i].Price, currency); ok {
+			hotels[i].Price = price.Rounded()
+			hotels[i].Currency = hotels[i].Price.Currency
+		}
+	}
+	return hotels
+}
+
+// DropCurrencyMismatchedFlights removes flights still priced in another currency than the
+// one requested so they are never summed with it. It returns the kept flights and how many were dropped.
+func DropCurrencyMismatchedFlights(flights []Flight) ([]Flight, int) {
+	kept := make([]Flight, 0, len(flights))
+	for _, f := range flights {
+		if !f.CurrencyMismatch {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(flights) - len(kept)
+}
+
+// DropCurrencyMismatchedHotels is the hotel counterpart of DropCurrencyMismatchedFlights.
+func DropCurrencyMismatchedHotels(hotels []Hotel) ([]Hotel, int) {
+	kept := make([]Hotel, 0, len(hotels))
+	for _, h := range hotels {
+		if !h.CurrencyMismatch {
+			kept = append(kept, h)
+		}
+	}
+	return kept, len(hotels) - len(kept)
+}
+
 func parseDuration(iso string) string {
 	if iso == "" { return "" }
 	iso = strings.TrimPrefix(iso, "PT")
@@ -1076,13 +1952,52 @@ func parseDuration(iso string) string {
 	return result
 }
 
+// parseDurationMinutes converts an ISO 8601 duration such as "PT5H30M" or "P1DT2H" to minutes.
+func parseDurationMinutes(iso string) int {
+	total, n := 0, 0
+	for _, r := range iso {
+		switch {
+		case r >= '0' && r <= '9':
+			n = n*10 + int(r-'0')
+		case r == 'D':
+			total += n * 24 * 60
+			n = 0
+		case r == 'H':
+			total += n * 60
+			n = 0
+		case r == 'M':
+			total += n
+			n = 0
+		default:
+			n = 0
+		}
+	}
+	return total
+}
+
 func formatDurationMin(minutes int) string {
 	h := minutes / 60
 	m := minutes % 60
+	if h == 0 { return fmt.Sprintf("%dm", m) }
 	if m > 0 { return fmt.Sprintf("%dh %dm", h, m) }
 	return fmt.Sprintf("%dh", h)
 }
 
+// displayDurationMinutes parses a display duration back to minutes, inverting parseDuration and
+// formatDurationMin ("5h 30m", "5h", "45m"). Unparseable parts are ignored.
+func displayDurationMinutes(d string) int {
+	total := 0
+	for _, part := range strings.Fields(d) {
+		var n int
+		if _, err := fmt.Sscanf(part, "%dh", &n); err == nil && strings.HasSuffix(part, "h") {
+			total += n * 60
+		} else if _, err := fmt.Sscanf(part, "%dm", &n); err == nil && strings.HasSuffix(part, "m") {
+			total += n
+		}
+	}
+	return total
+}
+
 func parsePrice(s string) float64 {
 	var price float64
 	fmt.Sscanf(s, "%f", &price)
@@ -1103,39 +2018,272 @@ func max(a, b int) int {
 	return b
 }
 
+// cityPrimaryAirports maps metropolitan city codes (which flight search rejects) to the
+// airport searched on their behalf.
+var cityPrimaryAirports = map[string]string{
+	"LON": "LHR", "PAR": "CDG", "NYC": "JFK", "ROM": "FCO", "TYO": "HND",
+}
+
+// CityToAirport resolves a city code to its primary airport; ok is false for codes that
+// aren't a known city-only code (airport codes, or cities whose code is also the airport).
+func CityToAirport(code string) (airport string, ok bool) {
+	airport, ok = cityPrimaryAirports[strings.ToUpper(code)]
+	return airport, ok
+}
+
+// CityOf returns the city code hotels are searched under for an airport or city code.
+func CityOf(code string) string {
+	return airportToCity(strings.ToUpper(code))
+}
+
+// airportCities maps airports to the city code hotels are searched under.
+var airportCities = map[string]string{
+	"LHR": "LON", "LGW": "LON", "STN": "LON", "LTN": "LON",
+	"CDG": "PAR", "ORY": "PAR",
+	"JFK": "NYC", "LGA": "NYC", "EWR": "NYC",
+	"LAX": "LAX", "DXB": "DXB", "IST": "IST", "FRA": "FRA",
+	"AMS": "AMS", "BER": "BER", "SXF": "BER",
+	"MAD": "MAD", "BCN": "BCN",
+	"FCO": "ROM", "CIA": "ROM",
+	"TAS": "TAS", "NRT": "TYO", "HND": "TYO",
+	"SIN": "SIN", "BKK": "BKK",
+}
+
 func airportToCity(airport string) string {
-	mapping := map[string]string{
-		"LHR": "LON", "LGW": "LON", "STN": "LON", "LTN": "LON",
-		"CDG": "PAR", "ORY": "PAR",
-		"JFK": "NYC", "LGA": "NYC", "EWR": "NYC",
-		"LAX": "LAX", "DXB": "DXB", "IST": "IST", "FRA": "FRA",
-		"AMS": "AMS", "BER": "BER", "SXF": "BER",
-		"MAD": "MAD", "BCN": "BCN",
-		"FCO": "ROM", "CIA": "ROM",
-		"TAS": "TAS", "NRT": "TYO", "HND": "TYO",
-		"SIN": "SIN", "BKK": "BKK",
-	}
-	if city, ok := mapping[airport]; ok { return city }
+	if city, ok := airportCities[airport]; ok { return city }
 	return airport
 }
 
+// segmentTimeLayouts are the shapes seen in segment times: Amadeus sends airport-local
+// time without an offset, fallback data uses full RFC3339.
+var segmentTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04"}
+
+// parseSegmentTime parses a segment time; hasOffset reports whether it carried its own zone.
+func parseSegmentTime(raw string) (t time.Time, hasOffset bool, ok bool) {
+	for i, layout := range segmentTimeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, i == 0, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+// segmentTimeUTC normalizes a segment time to RFC3339 UTC. Times without an offset are
+// interpreted in the airport's timezone; returns "" if that timezone isn't known.
+func segmentTimeUTC(raw, airport string) string {
+	t, hasOffset, ok := parseSegmentTime(raw)
+	if !ok {
+		return ""
+	}
+	if !hasOffset {
+		loc := airportLocation(airport)
+		if loc == nil {
+			return ""
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, loc)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func airportLocation(airport string) *time.Location {
+	zones := map[string]string{
+		"TAS": "Asia/Tashkent", "SKD": "Asia/Samarkand",
+		"IST": "Europe/Istanbul", "SAW": "Europe/Istanbul",
+		"DXB": "Asia/Dubai", "AUH": "Asia/Dubai", "DOH": "Asia/Qatar",
+		"LHR": "Europe/London", "LGW": "Europe/London", "STN": "Europe/London", "LTN": "Europe/London",
+		"CDG": "Europe/Paris", "ORY": "Europe/Paris",
+		"FRA": "Europe/Berlin", "MUC": "Europe/Berlin", "BER": "Europe/Berlin",
+		"AMS": "Europe/Amsterdam", "MAD": "Europe/Madrid", "BCN": "Europe/Madrid",
+		"FCO": "Europe/Rome", "CIA": "Europe/Rome", "VIE": "Europe/Vienna",
+		"PRG": "Europe/Prague", "WAW": "Europe/Warsaw", "BUD": "Europe/Budapest",
+		"ATH": "Europe/Athens", "LIS": "Europe/Lisbon", "CPH": "Europe/Copenhagen",
+		"ZRH": "Europe/Zurich",
+		"JFK": "America/New_York", "LGA": "America/New_York", "EWR": "America/New_York",
+		"LAX": "America/Los_Angeles", "SFO": "America/Los_Angeles", "ORD": "America/Chicago",
+		"NRT": "Asia/Tokyo", "HND": "Asia/Tokyo", "SIN": "Asia/Singapore", "BKK": "Asia/Bangkok",
+	}
+	name, ok := zones[airport]
+	if !ok { return nil }
+	loc, err := time.LoadLocation(name)
+	if err != nil { return nil }
+	return loc
+}
+
+// fareRulesLink returns the carrier's fare-rules / manage-booking page, where seat maps
+// and fare conditions can be checked. Empty when disabled or the carrier is unknown.
+func fareRulesLink(code string) string {
+	if !fareRulesLinksEnabled {
+		return ""
+	}
+	links := map[string]string{
+		"TK": "https://www.turkishairlines.com/en-int/any-questions/fare-rules/",
+		"LH": "https://www.lufthansa.com/xx/en/fare-conditions",
+		"AF": "https://wwws.airfrance.us/information/prepare/conditions-tarifaires",
+		"BA": "https://www.britishairways.com/en-gb/information/legal/british-airways/fare-rules",
+		"EK": "https://www.emirates.com/english/before-you-fly/travel/fare-rules/",
+		"QR": "https://www.qatarairways.com/en/fare-rules.html",
+		"PC": "https://www.flypgs.com/en/fare-rules",
+		"FR": "https://www.ryanair.com/gb/en/useful-info/help-centre/terms-and-conditions",
+		"U2": "https://www.easyjet.com/en/terms-and-conditions/fees",
+		"W6": "https://wizzair.com/en-gb/information-and-services/prices-discounts/fare-types",
+		"FZ": "https://www.flydubai.com/en/plan/fare-types/",
+		"HY": "https://www.uzairways.com/en/fare-rules",
+		"UA": "https://www.united.com/en/us/fly/travel/airport/seat-maps.html",
+		"AA": "https://www.aa.com/i18n/travel-info/seat-maps.jsp",
+		"DL": "https://www.delta.com/us/en/check-in-security/seat-maps",
+		"KL": "https://www.klm.com/information/legal/fare-conditions",
+		"SQ": "https://www.singaporeair.com/en_UK/us/travel-info/fare-rules/",
+		"VS": "https://www.virginatlantic.com/us/en/fare-rules.html",
+		"G9": "https://www.airarabia.com/en/fare-rules",
+		"TG": "https://www.thaiairways.com/en/booking/fare_rules.page",
+	}
+	return links[code]
+}
+
+// ─── Airline Names ────────────────────────────────────────────────────────────
+
+// airlineCache holds names fetched from the Amadeus airline reference for the process
+// lifetime. An empty name records a code Amadeus didn't know, so it isn't asked again.
+var airlineCache = struct {
+	sync.RWMutex
+	names map[string]string
+}{names: map[string]string{}}
+
+type amadeusAirlinesResponse struct {
+	Data []struct {
+		IataCode     string `json:"iataCode"`
+		BusinessName string `json:"businessName"`
+		CommonName   string `json:"commonName"`
+	} `json:"data"`
+}
+
+// LookupAirlines returns business names for carrier codes, querying Amadeus in one batch
+// for codes not looked up before. Codes Amadeus doesn't know are absent from the result.
+func (c *AmadeusClient) LookupAirlines(ctx context.Context, codes []string) (map[string]string, error) {
+	result := make(map[string]string, len(codes))
+	var missing []string
+	airlineCache.RLock()
+	for _, code := range codes {
+		if name, ok := airlineCache.names[code]; !ok {
+			missing = append(missing, code)
+		} else if name != "" {
+			result[code] = name
+		}
+	}
+	airlineCache.RUnlock()
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	escaped := make([]string, len(missing))
+	for i, code := range missing {
+		escaped[i] = url.QueryEscape(code)
+	}
+	path := "/v1/reference-data/airlines?airlineCodes=" + strings.Join(escaped, ",")
+	body, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return result, fmt.Errorf("airline lookup failed: %w", err)
+	}
+	var resp amadeusAirlinesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return result, fmt.Errorf("failed to parse airline lookup: %w", err)
+	}
+
+	airlineCache.Lock()
+	defer airlineCache.Unlock()
+	for _, code := range missing {
+		airlineCache.names[code] = ""
+	}
+	for _, a := range resp.Data {
+		name := a.BusinessName
+		if name == "" {
+			name = a.CommonName
+		}
+		if a.IataCode == "" || name == "" {
+			continue
+		}
+		name = titleCase(name)
+		airlineCache.names[a.IataCode] = name
+		result[a.IataCode] = name
+	}
+	return result, nil
+}
+
+// nameAirlines replaces "XX Airlines" placeholders with names from the Amadeus reference.
+// A failed lookup is logged and leaves the placeholders.
+func (c *AmadeusClient) nameAirlines(ctx context.Context, flights []Flight) {
+	var unknown []string
+	seen := map[string]bool{}
+	for _, f := range flights {
+		codes := []string{f.AirlineCode}
+		for _, leg := range f.Legs {
+			codes = append(codes, leg.AirlineCode)
+		}
+		for _, code := range codes {
+			if code != "" && !seen[code] && !knownAirline(code) {
+				seen[code] = true
+				unknown = append(unknown, code)
+			}
+		}
+	}
+	if len(unknown) == 0 {
+		return
+	}
+	if _, err := c.LookupAirlines(ctx, unknown); err != nil {
+		Logf(ctx, "⚠️  %v — keeping carrier codes", err)
+	}
+	for i := range flights {
+		flights[i].Airline = airlineName(flights[i].AirlineCode)
+		for j := range flights[i].Legs {
+			flights[i].Legs[j].Airline = airlineName(flights[i].Legs[j].AirlineCode)
+		}
+	}
+}
+
+// knownAirline reports whether airlineName already has a real name (or a settled miss) for code.
+func knownAirline(code string) bool {
+	if _, ok := staticAirlineNames[code]; ok {
+		return true
+	}
+	airlineCache.RLock()
+	defer airlineCache.RUnlock()
+	_, ok := airlineCache.names[code]
+	return ok
+}
+
+// titleCase turns the reference data's "BRITISH AIRWAYS" into "British Airways".
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// airlineName prefers the Amadeus reference, then the built-in names, then "XX Airlines".
 func airlineName(code string) string {
-	names := map[string]string{
-		"TK": "Turkish Airlines", "LH": "Lufthansa", "AF": "Air France",
-		"BA": "British Airways", "EK": "Emirates", "QR": "Qatar Airways",
-		"PC": "Pegasus Airlines", "FR": "Ryanair", "U2": "EasyJet",
-		"W6": "Wizz Air", "FZ": "FlyDubai", "HY": "Uzbekistan Airways",
-		"UA": "United Airlines", "AA": "American Airlines", "DL": "Delta Air Lines",
-		"KL": "KLM", "IB": "Iberia", "AZ": "ITA Airways",
-		"OS": "Austrian Airlines", "LX": "Swiss International Air Lines",
-		"SQ": "Singapore Airlines", "CX": "Cathay Pacific",
-		"NH": "ANA", "JL": "Japan Airlines", "EY": "Etihad Airways",
-		"SV": "Saudi Arabian Airlines", "MS": "EgyptAir", "RJ": "Royal Jordanian",
-		"ET": "Ethiopian Airlines", "G9": "Air Arabia", "XQ": "SunExpress",
-		"HV": "Transavia", "VY": "Vueling", "VS": "Virgin Atlantic",
-		"TG": "Thai Airways", "N0": "Norse Atlantic", "TR": "Scoot",
-	}
-	if name, ok := names[code]; ok { return name }
+	airlineCache.RLock()
+	cached := airlineCache.names[code]
+	airlineCache.RUnlock()
+	if cached != "" { return cached }
+
+	if name, ok := staticAirlineNames[code]; ok { return name }
 	if code != "" { return code + " Airlines" }
 	return "Unknown Airline"
+}
+
+var staticAirlineNames = map[string]string{
+	"TK": "Turkish Airlines", "LH": "Lufthansa", "AF": "Air France",
+	"BA": "British Airways", "EK": "Emirates", "QR": "Qatar Airways",
+	"PC": "Pegasus Airlines", "FR": "Ryanair", "U2": "EasyJet",
+	"W6": "Wizz Air", "FZ": "FlyDubai", "HY": "Uzbekistan Airways",
+	"UA": "United Airlines", "AA": "American Airlines", "DL": "Delta Air Lines",
+	"KL": "KLM", "IB": "Iberia", "AZ": "ITA Airways",
+	"OS": "Austrian Airlines", "LX": "Swiss International Air Lines",
+	"SQ": "Singapore Airlines", "CX": "Cathay Pacific",
+	"NH": "ANA", "JL": "Japan Airlines", "EY": "Etihad Airways",
+	"SV": "Saudi Arabian Airlines", "MS": "EgyptAir", "RJ": "Royal Jordanian",
+	"ET": "Ethiopian Airlines", "G9": "Air Arabia", "XQ": "SunExpress",
+	"HV": "Transavia", "VY": "Vueling", "VS": "Virgin Atlantic",
+	"TG": "Thai Airways", "N0": "Norse Atlantic", "TR": "Scoot",
 }
\ No newline at end of file
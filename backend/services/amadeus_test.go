@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestAmadeusClient points a client with dummy credentials at a stub server.
+func newTestAmadeusClient(t *testing.T, handler http.Handler) *AmadeusClient {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &AmadeusClient{
+		clientID:     "test-id",
+		clientSecret: "test-secret",
+		baseURL:      srv.URL,
+		httpClient:   srv.Client(),
+	}
+}
+
+// tokenServer answers token requests with "token-1", "token-2"…, counting them in hits.
+// delay holds each answer so concurrent callers pile up behind the refresh in flight.
+func tokenServer(hits *atomic.Int32, delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/security/oauth2/token" {
+			http.NotFound(w, r)
+			return
+		}
+		n := hits.Add(1)
+		time.Sleep(delay)
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":1799}`, n)
+	}
+}
+
+func TestGetTokenConcurrentCallersShareOneRefresh(t *testing.T) {
+	var hits atomic.Int32
+	c := newTestAmadeusClient(t, tokenServer(&hits, 50*time.Millisecond))
+
+	const callers = 50
+	tokens := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = c.getToken(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("token endpoint hit %d times, want 1", got)
+	}
+	for i := range tokens {
+		if errs[i] != nil || tokens[i] != "token-1" {
+			t.Fatalf("caller %d got (%q, %v), want (token-1, nil)", i, tokens[i], errs[i])
+		}
+	}
+}
+
+func TestGetTokenRefreshesExpiredToken(t *testing.T) {
+	var hits atomic.Int32
+	c := newTestAmadeusClient(t, tokenServer(&hits, 0))
+	c.accessToken = "stale"
+	c.tokenExpiry = time.Now().Add(-time.Minute)
+
+	token, err := c.getToken(context.Background())
+	if err != nil || token != "token-1" {
+		t.Fatalf("getToken() = (%q, %v), want (token-1, nil)", token, err)
+	}
+	if !c.tokenExpiry.After(time.Now()) {
+		t.Errorf("tokenExpiry %v is not in the future after a refresh", c.tokenExpiry)
+	}
+
+	// The fresh token is reused until it expires.
+	if token, _ := c.getToken(context.Background()); token != "token-1" {
+		t.Errorf("second getToken() = %q, want the cached token-1", token)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("token endpoint hit %d times, want 1", got)
+	}
+}
+
+// testRoute is a fixed two-airline route so the expected fares don't follow knownRoutes edits.
+var testRoute = routeData{basePrice: 400, durationM: 300, airlines: []fallbackAirline{
+	{"Test Air", "TA", "TA100", 1.00, 0, 8, 17},
+	{"Budget Air", "BU", "BU200", 0.80, 1, 22, 6},
+}}
+
+func TestRouteFallbackFlightsPricesAndTimes(t *testing.T) {
+	dep := time.Date(2026, time.November, 10, 0, 0, 0, 0, time.UTC)
+	ret := time.Date(2026, time.November, 17, 0, 0, 0, 0, time.UTC)
+	pax := PassengerMix{Adults: 2, Children: 1}
+
+	tests := []struct {
+		cabin              string
+		price              []Money
+		departure, arrival []string
+		returnDeparture    []string
+	}{
+		{
+			cabin:           "",
+			price:           []Money{usd(1100), usd(880)},
+			departure:       []string{"2026-11-10T08:25:00Z", "2026-11-10T22:25:00Z"},
+			arrival:         []string{"2026-11-10T13:25:00Z", "2026-11-11T04:50:00Z"},
+			returnDeparture: []string{"2026-11-17T17:40:00Z", "2026-11-17T06:40:00Z"},
+		},
+		{
+			cabin:           "BUSINESS",
+			price:           []Money{usd(3520), usd(2820)},
+			departure:       []string{"2026-11-10T08:25:00Z", "2026-11-10T22:25:00Z"},
+			arrival:         []string{"2026-11-10T13:25:00Z", "2026-11-11T04:50:00Z"},
+			returnDeparture: []string{"2026-11-17T17:40:00Z", "2026-11-17T06:40:00Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("cabin="+tt.cabin, func(t *testing.T) {
+			flights := routeFallbackFlights(testRoute, dep, ret, tt.cabin, false, pax)
+			if len(flights) != len(tt.price) {
+				t.Fatalf("got %d flights, want %d", len(flights), len(tt.price))
+			}
+			for i, f := range flights {
+				if f.Price != tt.price[i] {
+					t.Errorf("flight %d price = %v, want %v", i, f.Price, tt.price[i])
+				}
+				if f.Price != fareBreakdownTotal(f.FareBreakdown) {
+					t.Errorf("flight %d price %v doesn't match its breakdown %v", i, f.Price, f.FareBreakdown)
+				}
+				if f.DepartureTime != tt.departure[i] || f.ArrivalTime != tt.arrival[i] || f.ReturnDepartureTime != tt.returnDeparture[i] {
+					t.Errorf("flight %d times = %s → %s, back %s; want %s → %s, back %s", i,
+						f.DepartureTime, f.ArrivalTime, f.ReturnDepartureTime, tt.departure[i], tt.arrival[i], tt.returnDeparture[i])
+				}
+				if !f.Estimated || f.Currency != "USD" {
+					t.Errorf("flight %d estimated=%v currency=%q, want an estimated USD fare", i, f.Estimated, f.Currency)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateFlightsFallbackIsDeterministic(t *testing.T) {
+	pax := PassengerMix{Adults: 1, Children: 1, Infants: 1}
+	for _, route := range [][2]string{{"TAS", "IST"}, {"JFK", "LHR"}, {"TAS", "LIS"}} {
+		first := GenerateFlightsFallback(route[0], route[1], "2026-11-10", "2026-11-17", "ECONOMY", false, pax)
+		second := GenerateFlightsFallback(route[0], route[1], "2026-11-10", "2026-11-17", "ECONOMY", false, pax)
+		if len(first) == 0 {
+			t.Errorf("%s-%s: no estimated flights", route[0], route[1])
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s-%s: two calls gave different flights:\n%+v\n%+v", route[0], route[1], first, second)
+		}
+	}
+}
+
+func TestSearchFlightsNonStopQuery(t *testing.T) {
+	for _, nonStopOnly := range []bool{true, false} {
+		t.Run(fmt.Sprintf("nonStopOnly=%v", nonStopOnly), func(t *testing.T) {
+			var query url.Values
+			c := newTestAmadeusClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v1/security/oauth2/token":
+					fmt.Fprint(w, `{"access_token":"token","expires_in":1799}`)
+				case "/v2/shopping/flight-offers":
+					query = r.URL.Query()
+					fmt.Fprint(w, `{"data":[]}`)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+
+			if _, err := c.SearchFlights(context.Background(), "TAS", "IST", "2026-11-10", "2026-11-17", PassengerMix{Adults: 1}, "ECONOMY", nonStopOnly, "USD", 0); err != nil {
+				t.Fatalf("SearchFlights: %v", err)
+			}
+			if query == nil {
+				t.Fatal("flight-offers endpoint was not called")
+			}
+			got, sent := query["nonStop"]
+			switch {
+			case nonStopOnly && (!sent || len(got) != 1 || got[0] != "true"):
+				t.Errorf("query %q: want nonStop=true", query.Encode())
+			case !nonStopOnly && sent:
+				t.Errorf("query %q: want no nonStop parameter", query.Encode())
+			}
+		})
+	}
+}
+
+func TestFallbackFlightsNonStopOnly(t *testing.T) {
+	pax := PassengerMix{Adults: 1}
+	all := GenerateFlightsFallback("TAS", "IST", "2026-11-10", "2026-11-17", "ECONOMY", false, pax)
+	direct := GenerateFlightsFallback("TAS", "IST", "2026-11-10", "2026-11-17", "ECONOMY", true, pax)
+	if len(direct) == 0 || len(direct) >= len(all) {
+		t.Fatalf("got %d nonstop of %d flights, want some but not all", len(direct), len(all))
+	}
+	multiCity := GenerateMultiCityFallback("TAS", "IST", "DXB", "TAS", "2026-11-10", "2026-11-17", "ECONOMY", true, pax)
+	for _, f := range append(direct, multiCity...) {
+		if f.Stops != 0 || f.ReturnStops != 0 {
+			t.Errorf("%s %s has %d/%d stops, want nonstop both ways", f.Airline, f.FlightNumber, f.Stops, f.ReturnStops)
+		}
+	}
+}
+
+func TestGenerateHotelsFallbackReturnsACopy(t *testing.T) {
+	first := GenerateHotelsFallback("IST")
+	want := slices.Clone(first)
+	if len(first) == 0 {
+		t.Fatal("no estimated hotels for IST")
+	}
+	AssignOfferIDs(nil, first)
+	for i := range first {
+		first[i].Price = NewMoney(1, "EUR")
+		first[i].Currency = "EUR"
+	}
+
+	if second := GenerateHotelsFallback("IST"); !reflect.DeepEqual(second, want) {
+		t.Errorf("changing one result changed the next:\n%+v\nwant\n%+v", second, want)
+	}
+}
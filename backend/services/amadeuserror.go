@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ─── Amadeus Errors ───────────────────────────────────────────────────────────
+
+var (
+	// ErrAmadeusNotConfigured is returned by every Amadeus call when there are no credentials.
+	ErrAmadeusNotConfigured = errors.New("amadeus not configured")
+	// ErrNoOffers is returned (wrapped) when a search succeeded but found nothing to price.
+	ErrNoOffers = errors.New("no offers found")
+)
+
+// AmadeusError is an Amadeus response with an unexpected status. Body is kept for the logs
+// only: it can echo request details, so it never goes to clients.
+type AmadeusError struct {
+	Status int
+	Body   string
+	// Token is set for failures of the OAuth2 token request itself.
+	Token bool
+}
+
+func (e *AmadeusError) Error() string {
+	if e.Token {
+		return fmt.Sprintf("token request failed (%d): %s", e.Status, e.Body)
+	}
+	return fmt.Sprintf("amadeus error (%d): %s", e.Status, e.Body)
+}
+
+// FallbackReason values say why a search used estimated data instead of live offers.
+const (
+	FallbackNoResults     = "no_results"     // Amadeus answered but had nothing (left after filtering)
+	FallbackRateLimited   = "rate_limited"   // still 429 after the retry
+	FallbackAuthFailed    = "auth_failed"    // credentials missing, rejected or expired
+	FallbackUpstreamError = "upstream_error" // anything else: 5xx, timeouts, an open circuit
+)
+
+// ClassifyFallback maps the error a live search failed with to a FallbackReason; nil means
+// the search succeeded with no offers.
+func ClassifyFallback(err error) string {
+	var apiErr *AmadeusError
+	switch {
+	case err == nil, errors.Is(err, ErrNoOffers):
+		return FallbackNoResults
+	case errors.Is(err, ErrRateLimited):
+		return FallbackRateLimited
+	case errors.Is(err, ErrAmadeusNotConfigured):
+		return FallbackAuthFailed
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.Status == http.StatusUnauthorized || apiErr.Status == http.StatusForbidden,
+			apiErr.Token && apiErr.Status == http.StatusBadRequest:
+			return FallbackAuthFailed
+		case apiErr.Status == http.StatusTooManyRequests:
+			return FallbackRateLimited
+		}
+	}
+	return FallbackUpstreamError
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ─── Baggage Allowance ────────────────────────────────────────────────────────
+
+// BaggageAllowance is the checked baggage a fare includes per passenger: a number of bags,
+// or a total weight for airlines that count kilograms instead of pieces. Zero means a
+// cabin bag only.
+type BaggageAllowance struct {
+	CheckedBags int     `json:"checked_bags"`
+	WeightKg    float64 `json:"weight_kg,omitempty"`
+}
+
+func (b BaggageAllowance) String() string {
+	switch {
+	case b.CheckedBags == 0 && b.WeightKg > 0:
+		return fmt.Sprintf("%.0f kg checked per passenger", b.WeightKg)
+	case b.CheckedBags == 0:
+		return "Cabin bag only (no checked bag)"
+	}
+	if b.CheckedBags == 1 && b.WeightKg > 0 {
+		return fmt.Sprintf("1 checked bag per passenger (up to %.0f kg)", b.WeightKg)
+	}
+	if b.CheckedBags == 1 {
+		return "1 checked bag per passenger"
+	}
+	s := fmt.Sprintf("%d checked bags per passenger", b.CheckedBags)
+	if b.WeightKg > 0 {
+		s += fmt.Sprintf(" (up to %.0f kg each)", b.WeightKg)
+	}
+	return s
+}
+
+// amadeusCheckedBags is a segment's includedCheckedBags: a quantity, or a weight.
+type amadeusCheckedBags struct {
+	Quantity   int     `json:"quantity"`
+	Weight     float64 `json:"weight"`
+	WeightUnit string  `json:"weightUnit"`
+}
+
+// reportedBaggage is the allowance of the offer's first traveler across every segment: the
+// smallest one, since that is all that's checked through. It is nil unless every segment
+// reports one.
+func reportedBaggage(offer amadeusFlightOffer) *BaggageAllowance {
+	if len(offer.TravelerPricings) == 0 || len(offer.TravelerPricings[0].FareDetailsBySegment) == 0 {
+		return nil
+	}
+	var allowance *BaggageAllowance
+	for i, seg := range offer.TravelerPricings[0].FareDetailsBySegment {
+		bags := seg.IncludedCheckedBags
+		if bags == nil {
+			return nil
+		}
+		b := &BaggageAllowance{CheckedBags: bags.Quantity, WeightKg: bags.Weight}
+		if strings.HasPrefix(strings.ToUpper(bags.WeightUnit), "LB") {
+			b.WeightKg = math.Round(bags.Weight * 0.4536)
+		}
+		if i == 0 {
+			allowance = b
+		} else {
+			allowance = smallerBaggage(allowance, b)
+		}
+	}
+	return allowance
+}
+
+// smallerBaggage is the lesser of two allowances, for a trip combined from separate
+// fares; it is nil when either is unknown.
+func smallerBaggage(a, b *BaggageAllowance) *BaggageAllowance {
+	if a == nil || b == nil {
+		return nil
+	}
+	if b.CheckedBags < a.CheckedBags || (b.CheckedBags == a.CheckedBags && b.WeightKg < a.WeightKg) {
+		return b
+	}
+	return a
+}
+
+// lowCostCarriers sell their cheapest fares without a checked bag.
+var lowCostCarriers = map[string]bool{
+	"W6": true, "FZ": true, "PC": true, "U2": true, "G9": true, "FR": true,
+	"N0": true, "VY": true, "HV": true, "XQ": true, "TR": true,
+}
+
+// estimateBaggage is a typical allowance for an estimated fare: none on a low-cost
+// carrier's economy fare, one 23 kg bag in full-service economy, more in premium cabins.
+func estimateBaggage(airlineCode, cabinClass string) *BaggageAllowance {
+	switch {
+	case cabinClass == "FIRST":
+		return &BaggageAllowance{CheckedBags: 3, WeightKg: 32}
+	case cabinClass == "BUSINESS":
+		return &BaggageAllowance{CheckedBags: 2, WeightKg: 32}
+	case lowCostCarriers[airlineCode] && cabinClass == "PREMIUM_ECONOMY":
+		return &BaggageAllowance{CheckedBags: 1, WeightKg: 20}
+	case lowCostCarriers[airlineCode]:
+		return &BaggageAllowance{}
+	case cabinClass == "PREMIUM_ECONOMY":
+		return &BaggageAllowance{CheckedBags: 2, WeightKg: 23}
+	}
+	return &BaggageAllowance{CheckedBags: 1, WeightKg: 23}
+}
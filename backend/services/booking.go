@@ -0,0 +1,64 @@
+package services
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ─── Booking Links ────────────────────────────────────────────────────────────
+
+// FlightBookingLink builds a Google Flights query for f: its own airports when it has them
+// (nearby-airport searches), otherwise origin and destination, on the trip's dates.
+// Multi-leg itineraries list every leg.
+func FlightBookingLink(f Flight, origin, destination, departureDate, returnDate string) string {
+	var q string
+	switch {
+	case len(f.Legs) > 0:
+		legs := make([]string, len(f.Legs))
+		for i, leg := range f.Legs {
+			legs[i] = leg.Origin + " to " + leg.Destination + " on " + dateOf(leg.DepartureTime)
+		}
+		q = "Flights " + strings.Join(legs, ", ")
+	case origin == "" || destination == "" || departureDate == "":
+		return ""
+	default:
+		if f.Origin != "" && f.Destination != "" {
+			origin, destination = f.Origin, f.Destination
+		}
+		q = "Flights from " + origin + " to " + destination + " on " + departureDate
+		if returnDate != "" {
+			q += " through " + returnDate
+		}
+	}
+	return "https://www.google.com/travel/flights?q=" + url.QueryEscape(q)
+}
+
+// HotelBookingLink builds a Booking.com search for the hotel by name and location, with
+// the stay's dates and party size filled in.
+func HotelBookingLink(h Hotel, checkIn, checkOut string, adults int) string {
+	if h.Name == "" {
+		return ""
+	}
+	query := h.Name
+	if h.Location != "" {
+		query += ", " + h.Location
+	}
+	params := url.Values{}
+	params.Set("ss", query)
+	if checkIn != "" && checkOut != "" {
+		params.Set("checkin", checkIn)
+		params.Set("checkout", checkOut)
+	}
+	params.Set("group_adults", strconv.Itoa(max(1, adults)))
+	params.Set("no_rooms", "1")
+	return "https://www.booking.com/searchresults.html?" + params.Encode()
+}
+
+// dateOf is the YYYY-MM-DD part of a segment time.
+func dateOf(t string) string {
+	if len(t) >= 10 {
+		return t[:10]
+	}
+	return t
+}
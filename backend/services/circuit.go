@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ─── Circuit Breaker ──────────────────────────────────────────────────────────
+
+// Circuit states as reported by CircuitSnapshot.
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// CircuitStatus is the externally visible state of one provider's breaker.
+type CircuitStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+}
+
+type circuitBreakers struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	circuits  map[string]*circuit
+}
+
+// breakers trips a provider after CIRCUIT_FAILURE_THRESHOLD consecutive failures (default 5)
+// and keeps it open for CIRCUIT_COOLDOWN_SECONDS (default 60) before letting one probe through.
+// The settings are read on first use, after main has loaded .env.
+var breakers = sync.OnceValue(func() *circuitBreakers {
+	return &circuitBreakers{
+		threshold: envInt("CIRCUIT_FAILURE_THRESHOLD", 5),
+		cooldown:  time.Duration(envInt("CIRCUIT_COOLDOWN_SECONDS", 60)) * time.Second,
+		circuits:  map[string]*circuit{},
+	}
+})
+
+// allowCall returns an error without touching the network while provider's circuit is open.
+// After the cooldown exactly one caller is let through as a half-open probe.
+func allowCall(provider string) error {
+	b := breakers()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.get(provider)
+	if c.failures < b.threshold {
+		return nil
+	}
+	if time.Now().Before(c.openUntil) {
+		return fmt.Errorf("%s circuit open after %d consecutive failures", provider, c.failures)
+	}
+	// Hold everyone else back for another cooldown while the probe runs; its outcome
+	// closes the circuit or re-opens it, and a probe that never reports just expires.
+	c.openUntil = time.Now().Add(b.cooldown)
+	return nil
+}
+
+// recordOutcome closes provider's circuit on success and (re)opens it once failures hit the threshold.
+// A rejection of our own request (see isRequestError) shows the provider is up, so it counts
+// as a success.
+func recordOutcome(provider string, err error) {
+	b := breakers()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.get(provider)
+	if err == nil || isRequestError(err) {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.failures >= b.threshold {
+		c.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// isRequestError reports whether err is a provider's 4xx answer to bad input of ours, such as
+// an unknown hotel ID in a batch. Auth failures, timeouts and rate limits still count against
+// the provider.
+func isRequestError(err error) bool {
+	var apiErr *AmadeusError
+	if !errors.As(err, &apiErr) || apiErr.Token {
+		return false
+	}
+	switch apiErr.Status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return apiErr.Status >= 400 && apiErr.Status < 500
+}
+
+// CircuitSnapshot reports the breaker state of every provider called so far.
+func CircuitSnapshot() map[string]CircuitStatus {
+	b := breakers()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make(map[string]CircuitStatus, len(b.circuits))
+	now := time.Now()
+	for provider, c := range b.circuits {
+		status := CircuitStatus{State: CircuitClosed, ConsecutiveFailures: c.failures}
+		if c.failures >= b.threshold {
+			status.State = CircuitHalfOpen
+			if now.Before(c.openUntil) {
+				status.State = CircuitOpen
+				until := c.openUntil
+				status.OpenUntil = &until
+			}
+		}
+		out[provider] = status
+	}
+	return out
+}
+
+// get returns provider's circuit, creating it closed. Caller must hold b.mu.
+func (b *circuitBreakers) get(provider string) *circuit {
+	c, ok := b.circuits[provider]
+	if !ok {
+		c = &circuit{}
+		b.circuits[provider] = c
+	}
+	return c
+}
+
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+// envDuration reads a time.ParseDuration value such as "45s"; unset, malformed or
+// non-positive values give def.
+func envDuration(key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil || v <= 0 {
+		log.Printf("⚠️  Invalid %s %q — using %s", key, raw, def)
+		return def
+	}
+	return v
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCircuitIgnoresRequestErrors(t *testing.T) {
+	threshold := breakers().threshold
+	tests := []struct {
+		err      error
+		wantOpen bool
+	}{
+		{&AmadeusError{Status: http.StatusBadRequest}, false},
+		{fmt.Errorf("hotel offers failed: %w", &AmadeusError{Status: http.StatusNotFound}), false},
+		{&AmadeusError{Status: http.StatusInternalServerError}, true},
+		{&AmadeusError{Status: http.StatusUnauthorized}, true},
+		{&AmadeusError{Status: http.StatusBadRequest, Token: true}, true},
+		{fmt.Errorf("connection refused"), true},
+	}
+	for i, tt := range tests {
+		provider := fmt.Sprintf("test-circuit-%d", i)
+		for n := 0; n < threshold; n++ {
+			recordOutcome(provider, tt.err)
+		}
+		if open := allowCall(provider) != nil; open != tt.wantOpen {
+			t.Errorf("after %d × %v: circuit open = %v, want %v", threshold, tt.err, open, tt.wantOpen)
+		}
+	}
+}
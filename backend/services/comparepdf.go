@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ─── Comparison PDF ───────────────────────────────────────────────────────────
+
+// DefaultCompareTop and MaxCompareTop bound how many flights and hotels a comparison lists.
+const (
+	DefaultCompareTop = 5
+	MaxCompareTop     = 10
+)
+
+// ComparisonPDFData is a search's offers for the comparison PDF, in the order the search
+// ranked them. Top limits each table (DefaultCompareTop when 0, clamped to MaxCompareTop).
+type ComparisonPDFData struct {
+	TravelerName  string
+	Origin        string
+	Destination   string
+	DepartureDate string
+	ReturnDate    string
+	NumNights     int
+	Passengers    PassengerMix
+	Flights       []Flight
+	Hotels        []Hotel
+	Top           int
+}
+
+type compareColumn struct {
+	title string
+	width float64
+	align string
+}
+
+// GenerateComparisonPDFBytes lists the top flights and hotels of a search in two tables,
+// with price, duration, stops and rating, for choosing before generating an itinerary.
+func GenerateComparisonPDFBytes(data ComparisonPDFData) ([]byte, error) {
+	if len(data.Flights) == 0 && len(data.Hotels) == 0 {
+		return nil, fmt.Errorf("no offers to compare")
+	}
+	top := data.Top
+	if top <= 0 {
+		top = DefaultCompareTop
+	}
+	top = min(top, MaxCompareTop)
+	flights := data.Flights[:min(top, len(data.Flights))]
+	hotels := data.Hotels[:min(top, len(data.Hotels))]
+
+	estimated := false
+	for _, f := range flights {
+		estimated = estimated || f.Estimated
+	}
+	for _, h := range hotels {
+		estimated = estimated || h.Estimated
+	}
+
+	pdf := newItineraryPDF(func(int) bool { return false })
+	pdf.AddPage()
+	drawPageHeader(pdf, "Trip Options Compared", estimated)
+
+	passengers := data.Passengers
+	if passengers.Adults <= 0 {
+		passengers.Adults = 1
+	}
+	pdf.SetFont(pdfFont, "B", 13)
+	pdf.SetTextColor(13, 24, 37)
+	pdf.CellFormat(170, 7, fmt.Sprintf("%s → %s", data.Origin, data.Destination), "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFont, "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(170, 6, fmt.Sprintf("%s – %s · %d nights · %s",
+		fmtDateReadable(data.DepartureDate), fmtDateReadable(data.ReturnDate), data.NumNights, passengers), "", 1, "L", false, 0, "")
+	if data.TravelerName != "" {
+		pdf.CellFormat(170, 6, "Prepared for "+data.TravelerName, "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	if len(flights) > 0 {
+		compareTitle(pdf, fmt.Sprintf("Flights (top %d)", len(flights)))
+		rows := make([][]string, len(flights))
+		for i, f := range flights {
+			stops, duration := quotedStopsAndDuration(f)
+			stopsLabel := "Direct"
+			if stops > 0 {
+				stopsLabel = fmt.Sprintf("%d", stops)
+			}
+			co2 := "–"
+			if f.CO2Kg > 0 {
+				co2 = fmt.Sprintf("%.0f kg", f.CO2Kg)
+			}
+			rows[i] = []string{fmt.Sprintf("%d", i+1), f.Airline, formatLegTime(f.DepartureTime, ""), duration, stopsLabel, co2, f.Price.String()}
+		}
+		drawCompareTable(pdf, []compareColumn{
+			{"#", 8, "C"}, {"Airline", 44, "L"}, {"Departs", 28, "L"}, {"Duration", 22, "L"},
+			{"Stops", 16, "C"}, {"CO2 / pax", 20, "R"}, {"Price", 32, "R"},
+		}, rows)
+		pdf.SetFont(pdfFont, "I", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.MultiCell(170, 4, "Prices are for the whole party. Duration and stops are the outbound's (every flight's, for multi-city trips).", "", "L", false)
+		pdf.Ln(4)
+	}
+
+	if len(hotels) > 0 {
+		compareTitle(pdf, fmt.Sprintf("Hotels (top %d)", len(hotels)))
+		rows := make([][]string, len(hotels))
+		for i, h := range hotels {
+			rating := "–"
+			if h.Rating > 0 {
+				rating = fmt.Sprintf("%.1f / 5", h.Rating)
+			}
+			rows[i] = []string{fmt.Sprintf("%d", i+1), h.Name, h.Location, rating,
+				h.Price.String(), h.Price.Mul(data.NumNights).String()}
+		}
+		drawCompareTable(pdf, []compareColumn{
+			{"#", 8, "C"}, {"Hotel", 58, "L"}, {"Location", 38, "L"}, {"Rating", 18, "C"},
+			{"Per night", 24, "R"}, {fmt.Sprintf("%d nights", data.NumNights), 24, "R"},
+		}, rows)
+		pdf.Ln(4)
+	}
+
+	if len(flights) > 0 && len(hotels) > 0 {
+		drawCheapestCombo(pdf, flights, hotels, data.NumNights)
+	}
+	return outputPDF(pdf)
+}
+
+// drawCheapestCombo notes the lowest flight + hotel total among the listed offers, when
+// both are priced in the same currency.
+func drawCheapestCombo(pdf *gofpdf.Fpdf, flights []Flight, hotels []Hotel, nights int) {
+	cheapestFlight, cheapestHotel := flights[0], hotels[0]
+	for _, f := range flights {
+		if f.Price.Less(cheapestFlight.Price) {
+			cheapestFlight = f
+		}
+	}
+	for _, h := range hotels {
+		if h.Price.Less(cheapestHotel.Price) {
+			cheapestHotel = h
+		}
+	}
+	if !SameCurrency(cheapestFlight.Currency, cheapestHotel.Currency) {
+		return
+	}
+	total := cheapestFlight.Price.Add(cheapestHotel.Price.Mul(nights))
+	pdf.SetFillColor(212, 168, 67)
+	pdf.SetTextColor(13, 24, 37)
+	pdf.SetFont(pdfFont, "B", 10)
+	pdf.CellFormat(170, 8, fitText(pdf, fmt.Sprintf("  Cheapest combination: %s + %s = %s",
+		cheapestFlight.Airline, cheapestHotel.Name, total), 170), "", 1, "L", true, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+func compareTitle(pdf *gofpdf.Fpdf, title string) {
+	pdf.SetFillColor(13, 24, 37)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.CellFormat(170, 8, "  "+title, "", 1, "L", true, 0, "")
+	pdf.Ln(1)
+}
+
+// drawCompareTable draws a header row and striped rows; text too wide for its column is
+// shortened with an ellipsis.
+func drawCompareTable(pdf *gofpdf.Fpdf, cols []compareColumn, rows [][]string) {
+	pdf.SetFont(pdfFont, "B", 9)
+	pdf.SetFillColor(232, 236, 242)
+	pdf.SetTextColor(60, 60, 60)
+	for _, col := range cols {
+		pdf.CellFormat(col.width, 7, col.title, "B", 0, col.align, true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont(pdfFont, "", 9)
+	pdf.SetTextColor(20, 20, 20)
+	pdf.SetFillColor(246, 248, 251)
+	for i, row := range rows {
+		for j, col := range cols {
+			pdf.CellFormat(col.width, 7, fitText(pdf, row[j], col.width-2), "", 0, col.align, i%2 == 1, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+// fitText shortens s with an ellipsis until it fits width in the current font.
+func fitText(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
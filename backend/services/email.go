@@ -0,0 +1,202 @@
+package services
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"tripmind/database"
+)
+
+// ─── Itinerary Email ──────────────────────────────────────────────────────────
+// Emails go through the notification outbox like webhooks: the request only queues the
+// itinerary ID, and the worker loads the PDF and sends it, retrying if SMTP is down.
+
+const KindItineraryEmail = "itinerary.email"
+
+// ItineraryEmail is the queued payload for sending a generated PDF to a traveler.
+type ItineraryEmail struct {
+	ItineraryID  string `json:"itinerary_id"`
+	To           string `json:"to"`
+	TravelerName string `json:"traveler_name,omitempty"`
+	Trip         string `json:"trip"`     // e.g. "TAS → IST, 2025-06-01 – 2025-06-08"
+	Filename     string `json:"filename"` // attachment name
+}
+
+type smtpConfig struct {
+	host, port, user, password, from string
+}
+
+// smtpConfigFromEnv reads SMTP_HOST, SMTP_PORT (default 587), SMTP_USER, SMTP_PASSWORD and
+// SMTP_FROM (default SMTP_USER). Email is off without a host and a sender.
+func smtpConfigFromEnv() (smtpConfig, bool) {
+	cfg := smtpConfig{
+		host:     os.Getenv("SMTP_HOST"),
+		port:     strconv.Itoa(envInt("SMTP_PORT", 587)),
+		user:     os.Getenv("SMTP_USER"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM"),
+	}
+	if cfg.from == "" {
+		cfg.from = cfg.user
+	}
+	return cfg, cfg.host != "" && cfg.from != ""
+}
+
+// EmailConfigured reports whether itinerary emails will be delivered.
+func EmailConfigured() bool {
+	return notifierFor(KindItineraryEmail) != nil
+}
+
+// ValidEmail accepts a bare address (no display name) with a dotted domain.
+func ValidEmail(s string) bool {
+	if len(s) > 254 {
+		return false
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return false
+	}
+	domain := s[strings.LastIndex(s, "@")+1:]
+	return strings.Contains(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+const emailDisclaimer = "This is not a booking confirmation: nothing has been reserved or paid for. " +
+	"Prices and availability were checked when you searched and may have changed since. " +
+	"Book the flight and hotel with the providers linked in the PDF."
+
+// emailNotifier sends the itinerary's PDF as an attachment. An itinerary deleted before
+// delivery is dropped rather than retried.
+func emailNotifier(cfg smtpConfig) Notifier {
+	return func(key string, payload []byte) error {
+		var msg ItineraryEmail
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return fmt.Errorf("bad email payload: %w", err)
+		}
+		itinerary, err := database.GetItinerary(msg.ItineraryID)
+		if errors.Is(err, database.ErrNotFound) {
+			log.Printf("ℹ️  Itinerary %s was deleted before it could be emailed — skipping", msg.ItineraryID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		body, err := buildItineraryEmail(cfg.from, msg, itinerary.PDFData)
+		if err != nil {
+			return err
+		}
+		return sendMail(cfg, msg.To, body)
+	}
+}
+
+// buildItineraryEmail assembles a multipart/mixed message: a plain-text note with the
+// disclaimer, then the PDF.
+func buildItineraryEmail(from string, msg ItineraryEmail, pdf []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	greeting := "Hi,"
+	if msg.TravelerName != "" {
+		greeting = "Hi " + msg.TravelerName + ","
+	}
+	headers := []string{
+		"From: " + from,
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", "Your TripMind itinerary: "+msg.Trip),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + w.Boundary(),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	text, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(text, "%s\r\n\r\nYour TripMind itinerary for %s is attached.\r\n\r\n%s\r\n\r\n— TripMind\r\n",
+		greeting, msg.Trip, emailDisclaimer)
+
+	attachment, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType("application/pdf", map[string]string{"name": msg.Filename})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": msg.Filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(pdf)
+	for len(encoded) > 76 {
+		attachment.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	attachment.Write([]byte(encoded + "\r\n"))
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sendMail delivers one message, upgrading to TLS when the server offers STARTTLS. Unlike
+// smtp.SendMail it gives up after a minute, so a stalled server can't hold the worker.
+func sendMail(cfg smtpConfig, to string, body []byte) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(cfg.host, cfg.port), 10*time.Second)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(time.Minute))
+	client, err := smtp.NewClient(conn, cfg.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.host}); err != nil {
+			return err
+		}
+	}
+	if cfg.user != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.user, cfg.password, cfg.host)); err != nil {
+			return err
+		}
+	}
+	sender := cfg.from // SMTP_FROM may carry a display name; the envelope takes the bare address
+	if addr, err := mail.ParseAddress(cfg.from); err == nil {
+		sender = addr.Address
+	}
+	if err := client.Mail(sender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := data.Write(body); err != nil {
+		return err
+	}
+	if err := data.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,34 @@
+package services
+
+import "math"
+
+// ─── CO2 Estimates ────────────────────────────────────────────────────────────
+
+// Rough economy-seat figures used when Amadeus doesn't report emissions: cruise burn per
+// hour of flying, plus an extra take-off and landing cycle for each stop.
+const (
+	co2KgPerHour = 90.0
+	co2KgPerStop = 50.0
+)
+
+// estimateCO2Kg is one leg's emissions per passenger, to the nearest kg.
+func estimateCO2Kg(durationMinutes, stops int) float64 {
+	return math.Round(co2KgPerHour*float64(durationMinutes)/60 + co2KgPerStop*float64(stops))
+}
+
+// flightCO2Kg estimates both legs of a flight, or every leg of a multi-leg itinerary;
+// one-way flights only count the outbound.
+func flightCO2Kg(f Flight) float64 {
+	if len(f.Legs) > 0 {
+		co2 := 0.0
+		for _, leg := range f.Legs {
+			co2 += estimateCO2Kg(leg.DurationMinutes, leg.Stops)
+		}
+		return co2
+	}
+	co2 := estimateCO2Kg(f.DurationMinutes, f.Stops)
+	if f.ReturnDepartureTime != "" {
+		co2 += estimateCO2Kg(f.ReturnDurationMinutes, f.ReturnStops)
+	}
+	return co2
+}
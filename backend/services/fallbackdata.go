@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"regexp"
+)
+
+// ─── Fallback Data File ───────────────────────────────────────────────────────
+// FALLBACK_DATA_PATH points at a JSON file of extra estimated routes and hotels, so new
+// markets can be tuned without a rebuild:
+//
+//	{
+//	  "routes": {
+//	    "TAS-IST": {"base_price": 310, "duration_minutes": 295, "airlines": [
+//	      {"name": "Turkish Airlines", "code": "TK", "flight_number": "TK315",
+//	       "price_factor": 1.0, "stops": 0, "departure_hour": 6, "return_hour": 14}
+//	    ]}
+//	  },
+//	  "hotels": {
+//	    "IST": [{"name": "Grand Hyatt Istanbul", "price_per_night": 189, "rating": 4.7, "location": "Taksim, Istanbul"}]
+//	  }
+//	}
+//
+// Entries replace the compiled-in route or city with the same key; the rest are kept.
+
+type fallbackDataFile struct {
+	Routes map[string]fallbackRouteJSON   `json:"routes"`
+	Hotels map[string][]fallbackHotelJSON `json:"hotels"`
+}
+
+type fallbackRouteJSON struct {
+	BasePrice       int                   `json:"base_price"`
+	DurationMinutes int                   `json:"duration_minutes"`
+	Airlines        []fallbackAirlineJSON `json:"airlines"`
+}
+
+type fallbackAirlineJSON struct {
+	Name          string  `json:"name"`
+	Code          string  `json:"code"`
+	FlightNumber  string  `json:"flight_number"`
+	PriceFactor   float64 `json:"price_factor"`
+	Stops         int     `json:"stops"`
+	DepartureHour int     `json:"departure_hour"`
+	ReturnHour    int     `json:"return_hour"`
+}
+
+type fallbackHotelJSON struct {
+	Name          string  `json:"name"`
+	PricePerNight float64 `json:"price_per_night"`
+	Rating        float64 `json:"rating"`
+	Location      string  `json:"location"`
+}
+
+var (
+	fallbackRouteKey = regexp.MustCompile(`^[A-Z]{3}-[A-Z]{3}$`)
+	fallbackCityKey  = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// loadFallbackData merges the file at path into knownRoutes and fallbackCityHotels. An
+// empty path or a missing file keeps the compiled-in data; a malformed file is an error,
+// and nothing from it is applied.
+func loadFallbackData(path string) error {
+	if path == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Printf("⚠️  FALLBACK_DATA_PATH %s not found — using built-in estimates", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var file fallbackDataFile
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	routes := make(map[string]routeData, len(file.Routes))
+	for key, r := range file.Routes {
+		route, err := r.routeData(key)
+		if err != nil {
+			return fmt.Errorf("%s: route %s: %w", path, key, err)
+		}
+		routes[key] = route
+	}
+	cities := make(map[string][]Hotel, len(file.Hotels))
+	for city, list := range file.Hotels {
+		hotels, err := fallbackHotelsFromJSON(city, list)
+		if err != nil {
+			return fmt.Errorf("%s: hotels for %s: %w", path, city, err)
+		}
+		cities[city] = hotels
+	}
+
+	for key, route := range routes {
+		knownRoutes[key] = route
+	}
+	for city, hotels := range cities {
+		fallbackCityHotels[city] = hotels
+	}
+	log.Printf("✅ Loaded fallback data from %s: %d routes, %d cities of hotels (%d routes, %d cities in total)",
+		path, len(routes), len(cities), len(knownRoutes), len(fallbackCityHotels))
+	return nil
+}
+
+func (r fallbackRouteJSON) routeData(key string) (routeData, error) {
+	switch {
+	case !fallbackRouteKey.MatchString(key):
+		return routeData{}, fmt.Errorf(`key must look like "TAS-IST"`)
+	case r.BasePrice <= 0:
+		return routeData{}, fmt.Errorf("base_price must be positive")
+	case r.DurationMinutes <= 0:
+		return routeData{}, fmt.Errorf("duration_minutes must be positive")
+	case len(r.Airlines) == 0:
+		return routeData{}, fmt.Errorf("at least one airline is required")
+	}
+	route := routeData{basePrice: r.BasePrice, durationM: r.DurationMinutes}
+	for i, a := range r.Airlines {
+		switch {
+		case a.Name == "" || a.Code == "" || a.FlightNumber == "":
+			return routeData{}, fmt.Errorf("airline %d: name, code and flight_number are required", i+1)
+		case a.PriceFactor <= 0:
+			return routeData{}, fmt.Errorf("airline %d: price_factor must be positive", i+1)
+		case a.Stops < 0 || a.Stops > 3:
+			return routeData{}, fmt.Errorf("airline %d: stops must be 0-3", i+1)
+		case a.DepartureHour < 0 || a.DepartureHour > 23 || a.ReturnHour < 0 || a.ReturnHour > 23:
+			return routeData{}, fmt.Errorf("airline %d: departure_hour and return_hour must be 0-23", i+1)
+		}
+		route.airlines = append(route.airlines, fallbackAirline{
+			a.Name, a.Code, a.FlightNumber, a.PriceFactor, a.Stops, a.DepartureHour, a.ReturnHour,
+		})
+	}
+	return route, nil
+}
+
+func fallbackHotelsFromJSON(city string, list []fallbackHotelJSON) ([]Hotel, error) {
+	switch {
+	case !fallbackCityKey.MatchString(city):
+		return nil, fmt.Errorf(`key must be an IATA code like "IST"`)
+	case len(list) == 0:
+		return nil, fmt.Errorf("at least one hotel is required")
+	}
+	hotels := make([]Hotel, len(list))
+	for i, h := range list {
+		switch {
+		case h.Name == "":
+			return nil, fmt.Errorf("hotel %d: name is required", i+1)
+		case h.PricePerNight <= 0:
+			return nil, fmt.Errorf("hotel %d: price_per_night must be positive", i+1)
+		case h.Rating < 0 || h.Rating > 5:
+			return nil, fmt.Errorf("hotel %d: rating must be 0-5", i+1)
+		}
+		location := h.Location
+		if location == "" {
+			location = "City Center, " + city
+		}
+		hotels[i] = fallbackHotel(h.Name, h.PricePerNight, h.Rating, location)
+	}
+	return hotels, nil
+}
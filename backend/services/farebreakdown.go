@@ -0,0 +1,169 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ─── Fare Breakdown ───────────────────────────────────────────────────────────
+// A family's flight price is shown per passenger category ("2 adults × $450, 1 child ×
+// $340") instead of an average per head. Live offers carry Amadeus' own per-traveler
+// prices; estimated fares apply childFareShare and infantFareShare to the adult fare.
+
+// Passenger categories of a fare breakdown, in the order they are listed.
+const (
+	PassengerAdult  = "adult"
+	PassengerChild  = "child"
+	PassengerInfant = "infant"
+)
+
+var passengerCategories = []string{PassengerAdult, PassengerChild, PassengerInfant}
+
+// PassengerFare is the flight price for one passenger category: Count travelers at
+// PerTraveler each (an average if Amadeus priced them differently), Total in all.
+type PassengerFare struct {
+	Type        string `json:"type"`
+	Count       int    `json:"count"`
+	PerTraveler Money  `json:"per_traveler"`
+	Total       Money  `json:"total"`
+}
+
+// String describes the line, e.g. "2 adults × $450".
+func (pf PassengerFare) String() string {
+	many := map[string]string{PassengerAdult: "adults", PassengerChild: "children", PassengerInfant: "infants"}[pf.Type]
+	return fmt.Sprintf("%s × %s", plural(pf.Count, pf.Type, many), pf.PerTraveler)
+}
+
+// FareBreakdownString joins the lines, e.g. "2 adults × $450, 1 child × $340".
+func FareBreakdownString(fares []PassengerFare) string {
+	parts := make([]string, len(fares))
+	for i, pf := range fares {
+		parts[i] = pf.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// flightPriceLabel is the flight's price with its split by category when more than one
+// traveler flies, e.g. "$1,240 (2 adults × $450, 1 child × $340)".
+func flightPriceLabel(f Flight) string {
+	heads := 0
+	for _, pf := range f.FareBreakdown {
+		heads += pf.Count
+	}
+	if heads <= 1 {
+		return f.Price.String()
+	}
+	return fmt.Sprintf("%s (%s)", f.Price, FareBreakdownString(f.FareBreakdown))
+}
+
+// fareBreakdownTotal is what the whole party pays, the flight's price.
+func fareBreakdownTotal(fares []PassengerFare) Money {
+	var total Money
+	for _, pf := range fares {
+		total = total.Add(pf.Total)
+	}
+	return total
+}
+
+// amadeusPassengerCategory maps a travelerType to a category. Seniors, young travelers and
+// students pay adult-type fares; seated and lap infants are both infants.
+func amadeusPassengerCategory(travelerType string) string {
+	switch strings.ToUpper(travelerType) {
+	case "CHILD":
+		return PassengerChild
+	case "HELD_INFANT", "SEATED_INFANT":
+		return PassengerInfant
+	}
+	return PassengerAdult
+}
+
+// reportedFareBreakdown groups the offer's travelerPricings by category, each price converted
+// and rounded like the grand total. It is nil unless every traveler has a price.
+func reportedFareBreakdown(offer amadeusFlightOffer, currency string) []PassengerFare {
+	if len(offer.TravelerPricings) == 0 {
+		return nil
+	}
+	byType := map[string]*PassengerFare{}
+	for _, tp := range offer.TravelerPricings {
+		amount := parsePrice(tp.Price.Total)
+		if amount <= 0 {
+			return nil
+		}
+		source := tp.Price.Currency
+		if source == "" {
+			source = offer.Price.Currency
+		}
+		price, ok := normalizeCurrency(NewMoney(amount, source), currency, "traveler price")
+		if !ok {
+			return nil
+		}
+		category := amadeusPassengerCategory(tp.TravelerType)
+		pf, seen := byType[category]
+		if !seen {
+			pf = &PassengerFare{Type: category}
+			byType[category] = pf
+		}
+		pf.Count++
+		pf.Total = pf.Total.Add(price)
+	}
+	fares := make([]PassengerFare, 0, len(byType))
+	for _, category := range passengerCategories {
+		if pf, ok := byType[category]; ok {
+			pf.PerTraveler = pf.Total.Div(pf.Count)
+			fares = append(fares, *pf)
+		}
+	}
+	return fares
+}
+
+// estimatedFareBreakdown prices an estimated fare per category from the adult fare (USD),
+// each rounded to $5 like the estimates themselves.
+func estimatedFareBreakdown(adultFare float64, pax PassengerMix) []PassengerFare {
+	if pax.Adults <= 0 {
+		pax.Adults = 1
+	}
+	counts := map[string]int{PassengerAdult: pax.Adults, PassengerChild: pax.Children, PassengerInfant: pax.Infants}
+	shares := map[string]float64{PassengerAdult: 1, PassengerChild: childFareShare, PassengerInfant: infantFareShare}
+	var fares []PassengerFare
+	for _, category := range passengerCategories {
+		if counts[category] == 0 {
+			continue
+		}
+		per := usd(math.Round(adultFare*shares[category]/5) * 5)
+		fares = append(fares, PassengerFare{Type: category, Count: counts[category], PerTraveler: per, Total: per.Mul(counts[category])})
+	}
+	return fares
+}
+
+// addFareBreakdowns combines the breakdowns of separately priced flights flown by the same
+// party (multi-city legs). It is nil when either is unknown.
+func addFareBreakdowns(a, b []PassengerFare) []PassengerFare {
+	if len(a) == 0 || len(b) != len(a) {
+		return nil
+	}
+	sum := make([]PassengerFare, len(a))
+	for i := range a {
+		if a[i].Type != b[i].Type || a[i].Count != b[i].Count {
+			return nil
+		}
+		total := a[i].Total.Add(b[i].Total)
+		sum[i] = PassengerFare{Type: a[i].Type, Count: a[i].Count, PerTraveler: a[i].PerTraveler.Add(b[i].PerTraveler), Total: total}
+	}
+	return sum
+}
+
+// convertFareBreakdown re-prices an estimated breakdown in currency; ok is false when there
+// is no FX rate for it.
+func convertFareBreakdown(fares []PassengerFare, currency string) ([]PassengerFare, bool) {
+	converted := make([]PassengerFare, len(fares))
+	for i, pf := range fares {
+		per, ok := convertCurrency(pf.PerTraveler, currency)
+		if !ok {
+			return nil, false
+		}
+		per = per.Rounded()
+		converted[i] = PassengerFare{Type: pf.Type, Count: pf.Count, PerTraveler: per, Total: per.Mul(pf.Count)}
+	}
+	return converted, true
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// ─── Cheapest Dates ───────────────────────────────────────────────────────────
+
+// CheapestDatesNights is the stay length a flexible-dates search prices: a week away,
+// whichever day you leave.
+const CheapestDatesNights = 7
+
+// DateOption is one departure/return pair of a flexible-dates search, with the cheapest
+// round-trip fare found for it (one adult, economy).
+type DateOption struct {
+	DepartureDate string `json:"departure_date"`
+	ReturnDate    string `json:"return_date"`
+	Price         Money  `json:"price"`
+	Currency      string `json:"currency"`
+	Estimated     bool   `json:"estimated"`
+}
+
+type amadeusFlightDatesResponse struct {
+	Data []struct {
+		DepartureDate string `json:"departureDate"`
+		ReturnDate    string `json:"returnDate"`
+		Price         struct {
+			Total string `json:"total"`
+		} `json:"price"`
+	} `json:"data"`
+	Meta struct {
+		Currency string `json:"currency"`
+	} `json:"meta"`
+}
+
+// MonthDates returns the departure range a flexible-dates search covers: monthStart
+// (YYYY-MM for a whole month, or YYYY-MM-DD to start mid-month) up to the month's last day.
+func MonthDates(monthStart string) (from, to time.Time, err error) {
+	from, err = time.Parse("2006-01-02", monthStart)
+	if err != nil {
+		if from, err = time.Parse("2006-01", monthStart); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid month, use YYYY-MM or YYYY-MM-DD")
+		}
+	}
+	to = time.Date(from.Year(), from.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	return from, to, nil
+}
+
+// SearchCheapestDates asks Amadeus' cheapest-date search for the cheapest week-long round
+// trips departing in monthStart's month, cheapest first. Amadeus answers from its cache of
+// recent searches, so popular routes come back and others often fail.
+func (c *AmadeusClient) SearchCheapestDates(ctx context.Context, origin, destination, monthStart string) ([]DateOption, error) {
+	if c.clientID == "" {
+		return nil, ErrAmadeusNotConfigured
+	}
+	from, to, err := MonthDates(monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(
+		"/v1/shopping/flight-dates?origin=%s&destination=%s&departureDate=%s&oneWay=false&duration=%d&viewBy=DATE",
+		url.QueryEscape(origin), url.QueryEscape(destination),
+		url.QueryEscape(from.Format("2006-01-02")+","+to.Format("2006-01-02")), CheapestDatesNights,
+	)
+	body, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cheapest dates search failed: %w", err)
+	}
+
+	var resp amadeusFlightDatesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse flight dates: %w", err)
+	}
+	currency := resp.Meta.Currency
+	if currency == "" {
+		currency = "EUR"
+	}
+	options := make([]DateOption, 0, len(resp.Data))
+	for _, d := range resp.Data {
+		price := parsePrice(d.Price.Total)
+		if price <= 0 {
+			continue
+		}
+		options = append(options, DateOption{
+			DepartureDate: d.DepartureDate,
+			ReturnDate:    d.ReturnDate,
+			Price:         NewMoney(price, currency),
+			Currency:      currency,
+		})
+	}
+	sortDateOptions(options)
+	return options, nil
+}
+
+// weekdayFareFactor is how far a fare leaving (or returning) on a given day sits from the
+// route's usual price: midweek is cheapest, Fridays and Sundays dearest.
+var weekdayFareFactor = map[time.Weekday]float64{
+	time.Monday:    1.00,
+	time.Tuesday:   0.88,
+	time.Wednesday: 0.90,
+	time.Thursday:  0.97,
+	time.Friday:    1.15,
+	time.Saturday:  0.98,
+	time.Sunday:    1.12,
+}
+
+// GenerateCheapestDatesFallback estimates the same options without Amadeus: the route's
+// base fare, adjusted for the weekday of the departure and of the return.
+func GenerateCheapestDatesFallback(origin, destination, monthStart string) []DateOption {
+	from, to, err := MonthDates(monthStart)
+	if err != nil {
+		return nil
+	}
+	route, ok := knownRoutes[origin+"-"+destination]
+	if !ok {
+		route = estimateRoute(origin, destination)
+	}
+
+	var options []DateOption
+	for dep := from; !dep.After(to); dep = dep.AddDate(0, 0, 1) {
+		ret := dep.AddDate(0, 0, CheapestDatesNights)
+		factor := (weekdayFareFactor[dep.Weekday()] + weekdayFareFactor[ret.Weekday()]) / 2
+		price := math.Round(float64(route.basePrice)*factor/5) * 5
+		options = append(options, DateOption{
+			DepartureDate: dep.Format("2006-01-02"),
+			ReturnDate:    ret.Format("2006-01-02"),
+			Price:         usd(price),
+			Currency:      "USD",
+			Estimated:     true,
+		})
+	}
+	sortDateOptions(options)
+	return options
+}
+
+// sortDateOptions orders options cheapest first, earlier departures winning ties.
+func sortDateOptions(options []DateOption) {
+	slices.SortStableFunc(options, func(a, b DateOption) int {
+		switch {
+		case a.Price.Less(b.Price):
+			return -1
+		case b.Price.Less(a.Price):
+			return 1
+		}
+		return 0
+	})
+}
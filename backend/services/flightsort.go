@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ─── Flight Sorting ───────────────────────────────────────────────────────────
+
+// Flight sort orders for a search's sort_flights. Empty keeps the provider's order.
+const (
+	SortByPrice    = "price"
+	SortByDuration = "duration"
+	SortByStops    = "stops"
+)
+
+// ValidateFlightSort rejects unknown sort_flights values.
+func ValidateFlightSort(by string) error {
+	switch by {
+	case "", SortByPrice, SortByDuration, SortByStops:
+		return nil
+	}
+	return fmt.Errorf("sort_flights must be %q, %q or %q", SortByPrice, SortByDuration, SortByStops)
+}
+
+// SortFlights orders flights in place: by price, by total travel time of all legs, or by
+// total stops. Duration and stop ties go to the cheaper flight; remaining ties keep their order.
+func SortFlights(flights []Flight, by string) {
+	var key func(Flight) int
+	switch by {
+	case SortByDuration:
+		key = totalMinutes
+	case SortByStops:
+		key = totalStops
+	case SortByPrice:
+		key = func(Flight) int { return 0 }
+	default:
+		return
+	}
+	slices.SortStableFunc(flights, func(a, b Flight) int {
+		if d := key(a) - key(b); d != 0 {
+			return d
+		}
+		switch {
+		case a.Price.Less(b.Price):
+			return -1
+		case b.Price.Less(a.Price):
+			return 1
+		}
+		return 0
+	})
+}
+
+// totalMinutes is a flight's time in the air and at connections, all legs. Flights saved
+// before durations were kept in minutes fall back to parsing the display strings.
+func totalMinutes(f Flight) int {
+	if len(f.Legs) > 0 {
+		total := 0
+		for _, leg := range f.Legs {
+			m := leg.DurationMinutes
+			if m == 0 {
+				m = displayDurationMinutes(leg.Duration)
+			}
+			total += m
+		}
+		return total
+	}
+	out, ret := f.DurationMinutes, f.ReturnDurationMinutes
+	if out == 0 {
+		out = displayDurationMinutes(f.Duration)
+	}
+	if ret == 0 {
+		ret = displayDurationMinutes(f.ReturnDuration)
+	}
+	return out + ret
+}
+
+// totalStops counts connections across all of a flight's legs.
+func totalStops(f Flight) int {
+	if len(f.Legs) > 0 {
+		stops := 0
+		for _, leg := range f.Legs {
+			stops += leg.Stops
+		}
+		return stops
+	}
+	return f.Stops + f.ReturnStops
+}
@@ -0,0 +1,71 @@
+package services
+
+import "math"
+
+// ─── Hotel Distance ───────────────────────────────────────────────────────────
+
+const earthRadiusKM = 6371.0
+
+// cityCenters are reference points (the conventional "city center") for the hotel city
+// codes airportToCity produces. Other cities use the middle of the listed hotels.
+var cityCenters = map[string][2]float64{
+	"LON": {51.5074, -0.1278},
+	"PAR": {48.8566, 2.3522},
+	"NYC": {40.7580, -73.9855},
+	"LAX": {34.0522, -118.2437},
+	"DXB": {25.2048, 55.2708},
+	"IST": {41.0082, 28.9784},
+	"FRA": {50.1109, 8.6821},
+	"AMS": {52.3676, 4.9041},
+	"BER": {52.5200, 13.4050},
+	"MAD": {40.4168, -3.7038},
+	"BCN": {41.3874, 2.1686},
+	"ROM": {41.9028, 12.4964},
+	"TAS": {41.2995, 69.2401},
+	"TYO": {35.6762, 139.6503},
+	"SIN": {1.2903, 103.8520},
+	"BKK": {13.7563, 100.5018},
+}
+
+// haversineKM is the great-circle distance between two points in kilometres.
+func haversineKM(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(a))
+}
+
+// HasLocation reports whether the hotel came with coordinates (fallback hotels don't).
+func (h Hotel) HasLocation() bool {
+	return h.Latitude != 0 || h.Longitude != 0
+}
+
+// setDistances fills DistanceKm (to 0.1 km) for hotels with coordinates, measured from the
+// city's reference point. Unknown cities fall back to the centroid of the hotels, which
+// Amadeus lists around the city center anyway.
+func setDistances(hotels []Hotel, cityCode string) {
+	center, ok := cityCenters[airportToCity(cityCode)]
+	if !ok {
+		n := 0
+		for _, h := range hotels {
+			if h.HasLocation() {
+				center[0] += h.Latitude
+				center[1] += h.Longitude
+				n++
+			}
+		}
+		if n == 0 {
+			return
+		}
+		center[0] /= float64(n)
+		center[1] /= float64(n)
+	}
+	for i := range hotels {
+		if hotels[i].HasLocation() {
+			d := haversineKM(center[0], center[1], hotels[i].Latitude, hotels[i].Longitude)
+			hotels[i].DistanceKm = math.Round(d*10) / 10
+		}
+	}
+}
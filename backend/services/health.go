@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ─── Dependency Health ────────────────────────────────────────────────────────
+
+// Dependency states reported by the deep health check.
+const (
+	HealthOK            = "ok"
+	HealthError         = "error"
+	HealthNotConfigured = "not_configured"
+)
+
+// DependencyHealth is one dependency's result in the deep health check.
+type DependencyHealth struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+func healthResult(start time.Time, err error, detail string) DependencyHealth {
+	h := DependencyHealth{Status: HealthOK, LatencyMS: time.Since(start).Milliseconds(), Detail: detail}
+	if err != nil {
+		h.Status = HealthError
+		h.Detail = err.Error()
+	}
+	return h
+}
+
+// CheckHealth reports whether the client holds an unexpired token, fetching a new one when
+// it doesn't, so a bad credential or an unreachable Amadeus shows up as an error.
+func (c *AmadeusClient) CheckHealth(ctx context.Context) DependencyHealth {
+	if c == nil || c.clientID == "" || c.clientSecret == "" {
+		return DependencyHealth{Status: HealthNotConfigured}
+	}
+	start := time.Now()
+	if _, err := c.getToken(ctx); err != nil {
+		return healthResult(start, err, "")
+	}
+	c.mu.Lock()
+	expiry := c.tokenExpiry
+	c.mu.Unlock()
+	return healthResult(start, nil, "token valid until "+expiry.UTC().Format(time.RFC3339))
+}
+
+// CheckHealth sends a HEAD request to the HuggingFace model endpoint, or lists models on an
+// OpenAI-compatible server. A model that is still loading (503) counts as reachable; the
+// circuit breaker isn't consulted or updated, so health probes never trip or hide it.
+func (c *AIClient) CheckHealth(ctx context.Context) DependencyHealth {
+	if !c.configured() {
+		return DependencyHealth{Status: HealthNotConfigured}
+	}
+	start := time.Now()
+	method, endpoint := "HEAD", "https://api-inference.huggingface.co/models/"+c.model
+	if c.provider == ProviderOpenAI {
+		method, endpoint = "GET", c.chatEndpoint("models")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, nil)
+	if err != nil {
+		return healthResult(start, err, "")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return healthResult(start, err, "")
+	}
+	defer closeBody(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		return healthResult(start, nil, "model is loading")
+	case resp.StatusCode >= 400:
+		return healthResult(start, fmt.Errorf("model endpoint returned %d", resp.StatusCode), "")
+	}
+	return healthResult(start, nil, c.model)
+}
@@ -0,0 +1,71 @@
+package services
+
+import "slices"
+
+// ─── Hotel Filters ────────────────────────────────────────────────────────────
+
+// HotelFilter narrows hotels to a minimum star rating, a maximum nightly price in the
+// search currency and a board type. Zero fields don't restrict.
+type HotelFilter struct {
+	MinRating float64
+	MaxPrice  float64
+	// BoardType is also sent to Amadeus, so live offers come back with that meal plan.
+	BoardType string
+}
+
+// BoardTypes are the meal plans Amadeus hotel offers can be filtered by.
+var BoardTypes = []string{"ROOM_ONLY", "BREAKFAST", "HALF_BOARD", "FULL_BOARD", "ALL_INCLUSIVE"}
+
+// IsValidBoardType reports whether boardType is one of BoardTypes.
+func IsValidBoardType(boardType string) bool {
+	return slices.Contains(BoardTypes, boardType)
+}
+
+// IsZero reports whether the filter restricts nothing.
+func (f HotelFilter) IsZero() bool {
+	return f.MinRating <= 0 && f.MaxPrice <= 0 && f.BoardType == ""
+}
+
+// Matches reports whether a hotel passes the filter. Unrated hotels fail a rating minimum;
+// estimated hotels have no board type and always pass that part.
+func (f HotelFilter) Matches(h Hotel) bool {
+	if f.BoardType != "" && h.BoardType != "" && h.BoardType != f.BoardType {
+		return false
+	}
+	if f.MinRating > 0 && h.Rating < f.MinRating {
+		return false
+	}
+	if f.MaxPrice > 0 && h.Price.Float64() > f.MaxPrice {
+		return false
+	}
+	return true
+}
+
+// FilterHotels keeps the hotels matching f. When none do, the unfiltered list is returned
+// with relaxed set, so a strict filter shows every hotel rather than nothing.
+func FilterHotels(hotels []Hotel, f HotelFilter) ([]Hotel, bool) {
+	if f.IsZero() || len(hotels) == 0 {
+		return hotels, false
+	}
+	kept := make([]Hotel, 0, len(hotels))
+	for _, h := range hotels {
+		if f.Matches(h) {
+			kept = append(kept, h)
+		}
+	}
+	if len(kept) == 0 {
+		return hotels, true
+	}
+	return kept, false
+}
+
+// countMatching is how many hotels pass f.
+func countMatching(hotels []Hotel, f HotelFilter) int {
+	n := 0
+	for _, h := range hotels {
+		if f.Matches(h) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ─── Hotel Photo ──────────────────────────────────────────────────────────────
+// The PDF embeds the hotel's photo when it can be fetched; a slow host, an error or an
+// image that doesn't decode just leaves it out.
+
+const (
+	maxHotelPhotoBytes = 5 << 20
+	hotelPhotoWidthMM  = 70.0
+	hotelPhotoMaxH     = 50.0
+)
+
+// photoClient goes through AMADEUS_PROXY like the Amadeus calls the URLs came from.
+var photoClient = sync.OnceValue(func() *http.Client {
+	return newProviderHTTPClient(envDuration("HOTEL_PHOTO_TIMEOUT", 5*time.Second), "AMADEUS_PROXY")
+})
+
+// fetchHotelPhoto downloads and decodes the photo at uri, re-encoding it as JPEG so gofpdf
+// never sees a format variant it can't embed (interlaced PNG, CMYK…).
+func fetchHotelPhoto(uri string) (jpegData []byte, width, height int, err error) {
+	resp, err := photoClient().Get(uri)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer closeBody(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, 0, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxHotelPhotoBytes+1))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if len(raw) > maxHotelPhotoBytes {
+		return nil, 0, 0, fmt.Errorf("larger than %d bytes", maxHotelPhotoBytes)
+	}
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, 0, 0, err
+	}
+	b := img.Bounds()
+	return buf.Bytes(), b.Dx(), b.Dy(), nil
+}
+
+// drawHotelPhoto places the hotel's photo at the left margin below the current line,
+// starting a new page if it doesn't fit.
+func drawHotelPhoto(pdf *gofpdf.Fpdf, uri string) {
+	data, width, height, err := fetchHotelPhoto(uri)
+	if err != nil || width == 0 || height == 0 {
+		log.Printf("⚠️  Hotel photo %s left out of the PDF: %v", uri, err)
+		return
+	}
+	w := hotelPhotoWidthMM
+	h := w * float64(height) / float64(width)
+	if h > hotelPhotoMaxH {
+		w, h = w*hotelPhotoMaxH/h, hotelPhotoMaxH
+	}
+
+	_, pageH := pdf.GetPageSize()
+	_, bottomMargin := pdf.GetAutoPageBreak()
+	if pdf.GetY()+h > pageH-bottomMargin {
+		pdf.AddPage()
+	}
+	name := "hotel-photo:" + uri
+	opts := gofpdf.ImageOptions{ImageType: "JPG"}
+	pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(data))
+	y := pdf.GetY()
+	pdf.ImageOptions(name, 20, y, w, h, false, opts, 0, "")
+	pdf.SetY(y + h + 2)
+}
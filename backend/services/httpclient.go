@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ─── Outbound HTTP ────────────────────────────────────────────────────────────
+
+// newProviderHTTPClient builds the client used for a provider's API calls. If proxyEnv
+// (e.g. AMADEUS_PROXY) is set, every call goes through that proxy; otherwise the
+// standard HTTP_PROXY / HTTPS_PROXY / NO_PROXY variables apply. An invalid proxy URL
+// stops startup rather than silently bypassing a required egress proxy.
+//
+// Every search hits the same few provider hosts, so the idle pool is sized per host
+// (PROVIDER_MAX_IDLE_CONNS_PER_HOST, default 16 vs Go's 2) to reuse TLS connections
+// across concurrent calls; PROVIDER_IDLE_CONN_TIMEOUT_SECONDS bounds how long they linger.
+func newProviderHTTPClient(timeout time.Duration, proxyEnv string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.MaxIdleConnsPerHost = envInt("PROVIDER_MAX_IDLE_CONNS_PER_HOST", 16)
+	transport.IdleConnTimeout = time.Duration(envInt("PROVIDER_IDLE_CONN_TIMEOUT_SECONDS", 90)) * time.Second
+	if transport.MaxIdleConns < transport.MaxIdleConnsPerHost {
+		transport.MaxIdleConns = transport.MaxIdleConnsPerHost
+	}
+
+	if raw := os.Getenv(proxyEnv); raw != "" {
+		proxyURL, err := parseProxyURL(raw)
+		if err != nil {
+			log.Fatalf("❌ Invalid %s: %v", proxyEnv, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+		log.Printf("🔀 %s: routing provider calls via %s", proxyEnv, proxyURL.Redacted())
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+// closeBody drains what's left of a response body before closing it; a body closed
+// unread can't hand its connection back to the idle pool.
+func closeBody(body io.ReadCloser) {
+	io.Copy(io.Discard, body)
+	body.Close()
+}
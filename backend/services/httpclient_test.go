@@ -0,0 +1,74 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// BenchmarkProviderHTTPClient compares the provider client, which drains bodies with
+// closeBody and keeps 16 idle connections per host, with Go's default transport closing
+// bodies unread, as the Amadeus client used to. conns/op is how many TLS connections each
+// request had to open; the provider client should reuse nearly all of them.
+//
+//	go test ./services -run '^$' -bench ProviderHTTPClient
+func BenchmarkProviderHTTPClient(b *testing.B) {
+	body := `{"data":[` + strings.Repeat(`{"type":"hotel-offers"},`, 200) + `{}]}`
+	var conns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.StartTLS()
+	b.Cleanup(srv.Close)
+	tlsConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig
+
+	clients := []struct {
+		name  string
+		new   func() *http.Client
+		close func(io.ReadCloser)
+	}{
+		{"default", func() *http.Client {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = tlsConfig.Clone()
+			return &http.Client{Transport: transport}
+		}, func(body io.ReadCloser) { body.Close() }},
+		{"provider", func() *http.Client {
+			client := newProviderHTTPClient(30*time.Second, "BENCHMARK_PROXY")
+			client.Transport.(*http.Transport).TLSClientConfig = tlsConfig.Clone()
+			return client
+		}, closeBody},
+	}
+	for _, cl := range clients {
+		b.Run(cl.name, func(b *testing.B) {
+			client := cl.new()
+			defer client.CloseIdleConnections()
+			conns.Store(0)
+			b.SetParallelism(4) // a search's concurrent flight, hotel and rate calls
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					resp, err := client.Get(srv.URL)
+					if err != nil {
+						b.Error(err)
+						return
+					}
+					// Callers decode only the part of the body they need.
+					resp.Body.Read(make([]byte, 64))
+					cl.close(resp.Body)
+				}
+			})
+			b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+		})
+	}
+}
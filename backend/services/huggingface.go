@@ -2,11 +2,16 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
+	"math"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -14,35 +19,90 @@ type AIClient struct {
 	apiKey     string
 	model      string
 	httpClient *http.Client
+	// provider is AI_PROVIDER (huggingface or openai); baseURL is only used by openai.
+	provider string
+	baseURL  string
+	// cache holds recent summaries; nil when AI_CACHE=false.
+	cache *summaryCache
 }
 
 var aiClient *AIClient
 
-func InitAI() {
-	model := os.Getenv("HF_MODEL")
-	if model == "" {
-		model = "mistralai/Mistral-7B-Instruct-v0.3"
-	}
+// estimateNotice is prepended to every summary built on fallback data (override with ESTIMATE_NOTICE).
+var estimateNotice = "⚠ ESTIMATED DATA — live prices were unavailable, so the flights and hotels below are illustrative estimates, not real offers."
 
-	aiClient = &AIClient{
-		apiKey: os.Getenv("HUGGINGFACE_API_KEY"),
-		model:  model,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+func InitAI() {
+	if notice := os.Getenv("ESTIMATE_NOTICE"); notice != "" {
+		estimateNotice = notice
 	}
 
-	if aiClient.apiKey != "" {
-		fmt.Println("✅ AI (HuggingFace) initialized with model:", model)
-	} else {
-		fmt.Println("⚠️  HUGGINGFACE_API_KEY not set — AI summaries will use fallback text")
+	switch provider := strings.ToLower(getEnv("AI_PROVIDER", ProviderHuggingFace)); provider {
+	case ProviderHuggingFace:
+		aiClient = &AIClient{
+			provider:   ProviderHuggingFace,
+			apiKey:     os.Getenv("HUGGINGFACE_API_KEY"),
+			model:      getEnv("HF_MODEL", "mistralai/Mistral-7B-Instruct-v0.3"),
+			httpClient: newProviderHTTPClient(envDuration("AI_TIMEOUT", 60*time.Second), "HF_PROXY"),
+		}
+		if aiClient.apiKey == "" {
+			fmt.Println("⚠️  HUGGINGFACE_API_KEY not set — AI summaries will use fallback text")
+			return
+		}
+	case ProviderOpenAI:
+		aiClient = &AIClient{
+			provider:   ProviderOpenAI,
+			apiKey:     os.Getenv("OPENAI_API_KEY"),
+			model:      getEnv("OPENAI_MODEL", defaultOpenAIModel),
+			baseURL:    getEnv("OPENAI_BASE_URL", defaultOpenAIBaseURL),
+			httpClient: newProviderHTTPClient(envDuration("AI_TIMEOUT", 60*time.Second), "OPENAI_PROXY"),
+		}
+		if !aiClient.configured() {
+			fmt.Println("⚠️  OPENAI_API_KEY not set — AI summaries will use fallback text")
+			return
+		}
+	default:
+		log.Fatalf("❌ Unknown AI_PROVIDER %q (use huggingface or openai)", provider)
 	}
+	aiClient.cache = newSummaryCache()
+	fmt.Printf("✅ AI (%s) initialized with model: %s\n", aiClient.provider, aiClient.model)
 }
 
 func GetAIClient() *AIClient {
 	return aiClient
 }
 
+// AIConfigured reports whether summaries come from the AI provider rather than fallback text.
+func AIConfigured() bool {
+	return aiClient.configured()
+}
+
+// Provider is the AI_PROVIDER in use, which is also its usage and circuit-breaker key.
+func (c *AIClient) Provider() string {
+	if c == nil {
+		return ProviderHuggingFace
+	}
+	return c.provider
+}
+
+// configured reports whether the client can be called. OpenAI-compatible servers such as a
+// local Ollama need no key, so a custom OPENAI_BASE_URL is enough.
+func (c *AIClient) configured() bool {
+	if c == nil {
+		return false
+	}
+	if c.provider == ProviderOpenAI {
+		return c.apiKey != "" || c.baseURL != defaultOpenAIBaseURL
+	}
+	return c.apiKey != ""
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
 type hfRequest struct {
 	Inputs     string       `json:"inputs"`
 	Parameters hfParameters `json:"parameters"`
@@ -54,30 +114,102 @@ type hfParameters struct {
 	ReturnFullText bool    `json:"return_full_text"`
 }
 
+// GenerationOptions tunes the model per request. Zero fields use the defaults (400 tokens,
+// temperature 0.6); anything else is clamped to a sane range rather than rejected.
+type GenerationOptions struct {
+	MaxTokens   int
+	Temperature float64
+}
+
+const (
+	defaultMaxTokens   = 400
+	minMaxTokens       = 50
+	maxMaxTokens       = 800
+	defaultTemperature = 0.6
+	minTemperature     = 0.1
+	maxTemperature     = 1.2
+)
+
+// withDefaults fills unset options and clamps the rest.
+func (o GenerationOptions) withDefaults() GenerationOptions {
+	if o.MaxTokens == 0 {
+		o.MaxTokens = defaultMaxTokens
+	}
+	o.MaxTokens = min(max(o.MaxTokens, minMaxTokens), maxMaxTokens)
+	if o.Temperature == 0 {
+		o.Temperature = defaultTemperature
+	}
+	o.Temperature = math.Min(math.Max(o.Temperature, minTemperature), maxTemperature)
+	return o
+}
+
 type hfResponse []struct {
 	GeneratedText string `json:"generated_text"`
 }
 
+// hfErrorResponse is the object HuggingFace returns instead of the array when a model is
+// loading or the request failed, e.g. {"error":"Model is loading","estimated_time":20.5}.
+type hfErrorResponse struct {
+	Error         string  `json:"error"`
+	EstimatedTime float64 `json:"estimated_time"`
+}
+
+// parseHFError decodes body as an error envelope; ok is false for any other shape.
+func parseHFError(body []byte) (hfErrorResponse, bool) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return hfErrorResponse{}, false
+	}
+	var e hfErrorResponse
+	if err := json.Unmarshal(trimmed, &e); err != nil || e.Error == "" {
+		return hfErrorResponse{}, false
+	}
+	return e, true
+}
+
 func (c *AIClient) GetRecommendations(
-	budget float64,
+	ctx context.Context,
+	budget Money,
 	origin, destination, departureDate, returnDate string,
-	passengers int,
+	pax PassengerMix,
 	flights []Flight,
 	hotels []Hotel,
 	isFallbackData bool,
 	returnOrigin string,
-) (string, error) {
-	if c.apiKey == "" {
-		return "", fmt.Errorf("huggingface API key not configured")
+	opts GenerationOptions,
+) (summary string, err error) {
+	if !c.configured() {
+		return "", fmt.Errorf("%s AI provider not configured", c.Provider())
 	}
+	opts = opts.withDefaults()
 
-	prompt := buildPrompt(budget, origin, destination, departureDate, returnDate, passengers, flights, hotels, isFallbackData, returnOrigin)
+	prompt := BuildPrompt(budget, origin, destination, departureDate, returnDate, pax, flights, hotels, isFallbackData, returnOrigin)
+
+	// A cache hit skips the provider entirely, so it doesn't count as a call or probe.
+	key := summaryKey(c.provider, c.model, prompt, opts)
+	if text, ok := c.cache.get(key); ok {
+		return text, nil
+	}
+	defer func() {
+		if err == nil {
+			c.cache.put(key, summary)
+		}
+	}()
+
+	if err := allowCall(c.provider); err != nil {
+		return "", err
+	}
+	defer func() { RecordProviderCall(c.provider, err) }()
+
+	if c.provider == ProviderOpenAI {
+		return c.chatCompletion(prompt, opts)
+	}
 
 	reqBody := hfRequest{
 		Inputs: prompt,
 		Parameters: hfParameters{
-			MaxNewTokens:   400,
-			Temperature:    0.6,
+			MaxNewTokens:   opts.MaxTokens,
+			Temperature:    opts.Temperature,
 			ReturnFullText: false,
 		},
 	}
@@ -87,10 +219,53 @@ func (c *AIClient) GetRecommendations(
 		return "", err
 	}
 
+	// A cold model answers 503 with an estimated load time. Waiting for it beats handing the
+	// first search after a quiet spell the built-in summary, but every attempt and wait must
+	// fit in the client timeout a single call would have had. ctx only lends its request ID
+	// for logging; the summary is still generated for a client that has gone away.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.httpClient.Timeout)
+	defer cancel()
+	waited := time.Duration(0)
+	for attempt := 0; ; attempt++ {
+		var loadingFor time.Duration
+		summary, loadingFor, err = c.generate(ctx, jsonBody)
+		if loadingFor == 0 || attempt == hfLoadingRetries {
+			return summary, err
+		}
+		wait := min(loadingFor, hfLoadingWaitBudget-waited)
+		if deadline, ok := ctx.Deadline(); ok {
+			// Leave the retry a few seconds to actually generate.
+			wait = min(wait, time.Until(deadline)-hfMinAttemptTime)
+		}
+		if wait <= 0 {
+			return summary, err
+		}
+		Logf(ctx, "ℹ️  AI model is loading — retrying in %s (%d/%d)", wait.Round(time.Second), attempt+1, hfLoadingRetries)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return summary, err
+		}
+		waited += wait
+	}
+}
+
+// Loading retries: up to hfLoadingRetries more attempts, waiting at most hfLoadingWaitBudget
+// in total. hfDefaultLoadingWait is used when a 503 doesn't say how long loading takes.
+const (
+	hfLoadingRetries     = 3
+	hfLoadingWaitBudget  = 20 * time.Second
+	hfDefaultLoadingWait = 5 * time.Second
+	hfMinAttemptTime     = 5 * time.Second
+)
+
+// generate makes one inference call. loadingFor is non-zero when the model is still loading
+// and says how long HuggingFace expects that to take.
+func (c *AIClient) generate(ctx context.Context, jsonBody []byte) (text string, loadingFor time.Duration, err error) {
 	url := fmt.Sprintf("https://api-inference.huggingface.co/models/%s", c.model)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -98,36 +273,92 @@ func (c *AIClient) GetRecommendations(
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp.Body)
 
 	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode == 503 {
-		return "", fmt.Errorf("AI model is loading, please retry in a few seconds")
+	// Check for the object-shaped error envelope before the array decode, so a loading
+	// model surfaces as such instead of as a confusing parse error.
+	if hfErr, ok := parseHFError(body); ok {
+		if hfErr.EstimatedTime > 0 {
+			return "", time.Duration(hfErr.EstimatedTime * float64(time.Second)),
+				fmt.Errorf("AI model is loading (%s), retry in ~%.0fs", hfErr.Error, hfErr.EstimatedTime)
+		}
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			return "", hfDefaultLoadingWait, fmt.Errorf("HuggingFace API error (%d): %s", resp.StatusCode, hfErr.Error)
+		}
+		return "", 0, fmt.Errorf("HuggingFace API error (%d): %s", resp.StatusCode, hfErr.Error)
+	}
+
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return "", hfDefaultLoadingWait, fmt.Errorf("AI model is loading, please retry in a few seconds")
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HuggingFace API error (%d): %s", resp.StatusCode, string(body))
+		return "", 0, fmt.Errorf("HuggingFace API error (%d): %s", resp.StatusCode, string(body))
 	}
 
 	var hfResp hfResponse
 	if err := json.Unmarshal(body, &hfResp); err != nil {
-		return "", fmt.Errorf("failed to parse AI response: %v", err)
+		return "", 0, fmt.Errorf("failed to parse AI response: %v", err)
 	}
 
-	if len(hfResp) == 0 || hfResp[0].GeneratedText == "" {
-		return "", fmt.Errorf("empty response from AI")
+	if len(hfResp) == 0 {
+		return "", 0, fmt.Errorf("empty response from AI")
 	}
+	text = sanitizeAIOutput(hfResp[0].GeneratedText)
+	if text == "" {
+		return "", 0, fmt.Errorf("empty response from AI")
+	}
+	return text, 0, nil
+}
 
-	return hfResp[0].GeneratedText, nil
+// instructionTokens are Mistral chat-template markers the model sometimes echoes.
+var instructionTokens = strings.NewReplacer("[INST]", "", "[/INST]", "", "<s>", "", "</s>", "")
+
+var (
+	// specialTokens are ChatML-style markers such as <|im_end|> or <|assistant|>.
+	specialTokens = regexp.MustCompile(`<\|[^|<>\s]{1,40}\|>`)
+	// roleEcho is a leading speaker label the model writes before its answer.
+	roleEcho = regexp.MustCompile(`(?i)^(?:assistant|ai|answer)\s*:\s*`)
+)
+
+// sanitizeAIOutput cleans generated text before it reaches the response and the PDF. If the
+// model echoed the prompt (an [INST] … [/INST] block), only what follows it is kept; stray
+// instruction and chat-template tokens, a leading "Assistant:" label and surrounding
+// whitespace are removed.
+func sanitizeAIOutput(text string) string {
+	if i := strings.LastIndex(text, "[/INST]"); i >= 0 && strings.Contains(text[:i], "[INST]") {
+		text = text[i+len("[/INST]"):]
+	}
+	text = strings.TrimSpace(specialTokens.ReplaceAllString(instructionTokens.Replace(text), ""))
+	return strings.TrimSpace(roleEcho.ReplaceAllString(text, ""))
 }
 
-func buildPrompt(
-	budget float64,
+// WithEstimateNotice prefixes summary with the estimated-data notice so demo prices
+// can't be mistaken for live ones, regardless of what the model wrote.
+func WithEstimateNotice(summary string) string {
+	return estimateNotice + "\n\n" + summary
+}
+
+// budgetNote annotates a shortlisted offer with the cheapest trip total it allows.
+func budgetNote(priced, overBudget bool, fromTotal Money) string {
+	switch {
+	case !priced:
+		return ""
+	case overBudget:
+		return fmt.Sprintf(" · trip from %s — OVER BUDGET", fromTotal)
+	}
+	return fmt.Sprintf(" · trip from %s", fromTotal)
+}
+
+// BuildPrompt renders the exact instruction prompt sent to the model.
+func BuildPrompt(
+	budget Money,
 	origin, destination, departureDate, returnDate string,
-	passengers int,
+	pax PassengerMix,
 	flights []Flight,
 	hotels []Hotel,
 	isFallbackData bool,
@@ -139,30 +370,33 @@ func buildPrompt(
 	}
 
 	routeDesc := fmt.Sprintf("%s → %s", origin, destination)
-	if returnOrigin != "" && returnOrigin != destination {
+	fareKind := "round-trip"
+	if len(flights) > 0 && len(flights[0].Legs) > 0 {
+		routeDesc = LegsRoute(flights[0].Legs) + " (multi-city)"
+		fareKind = "whole-itinerary"
+	} else if returnOrigin != "" && returnOrigin != destination {
 		routeDesc = fmt.Sprintf("%s → %s (returning from %s → %s, multi-city)", origin, destination, returnOrigin, origin)
 	}
 
 	prompt := fmt.Sprintf(`[INST] You are a helpful travel assistant. Analyze these options and give brief, honest recommendations.
 
-Trip: %s | %s to %s | %d passenger(s) | Budget: $%.0f%s
+Trip: %s | %s to %s | %s | Budget: %s%s
 
-Flights available (price is per person, round-trip total):
-`, routeDesc, departureDate, returnDate, passengers, budget, dataNote)
+Flights available (price is the %s total for all passengers; CO2 is per passenger; "trip from" is the total with the cheapest hotel):
+`, routeDesc, departureDate, returnDate, pax, budget, dataNote, fareKind)
 
-	for i, f := range flights {
-		if i >= 5 {
-			break
-		}
-		prompt += fmt.Sprintf("  %d. %s — $%.0f (%d stop(s), %s)\n", i+1, f.Airline, f.Price, f.Stops, f.Duration)
+	// Only offers that can still make a trip within budget are listed, unless none can.
+	shortFlights, shortHotels := budgetShortlist(budget, tripNights(departureDate, returnDate), flights, hotels)
+	for i, s := range shortFlights {
+		f := s.offer
+		stops, duration := quotedStopsAndDuration(f)
+		prompt += fmt.Sprintf("  %d. %s — %s (%d stop(s), %s, ~%.0f kg CO2)%s\n", i+1, f.Airline, f.Price, stops, duration, f.CO2Kg, budgetNote(s.priced, s.overBudget, s.fromTotal))
 	}
 
-	prompt += "\nHotels (per night):\n"
-	for i, h := range hotels {
-		if i >= 5 {
-			break
-		}
-		prompt += fmt.Sprintf("  %d. %s — $%.0f/night (★%.1f) %s\n", i+1, h.Name, h.Price, h.Rating, h.Location)
+	prompt += "\nHotels (per night; \"trip from\" is the total with the cheapest flight):\n"
+	for i, s := range shortHotels {
+		h := s.offer
+		prompt += fmt.Sprintf("  %d. %s — %s/night (★%.1f) %s%s\n", i+1, h.Name, h.Price, h.Rating, h.Location, budgetNote(s.priced, s.overBudget, s.fromTotal))
 	}
 
 	highlights := DestinationHighlights(destination)
@@ -171,7 +405,7 @@ Flights available (price is per person, round-trip total):
 	}
 
 	prompt += `
-In 150 words or fewer, recommend the best flight and hotel that fit the budget. Explain why briefly. Use sections: "✈ Flight:" and "🏨 Hotel:". If space allows, add a "🗺 Highlights:" line with 2-3 must-see spots. Be direct. [/INST]`
+In 150 words or fewer, recommend the best flight and hotel that fit the budget together; if an option is marked OVER BUDGET, say so plainly. Explain why briefly, noting a lower-emission flight when it costs little more. Use sections: "✈ Flight:" and "🏨 Hotel:". If space allows, add a "🗺 Highlights:" line with 2-3 must-see spots. Be direct. [/INST]`
 
 	return prompt
-}
\ No newline at end of file
+}
@@ -0,0 +1,28 @@
+package services
+
+import "testing"
+
+func TestSanitizeAIOutput(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"clean text unchanged", "✈ Flight: Uzbekistan Airways, nonstop.\n🏨 Hotel: Hyatt Regency.", "✈ Flight: Uzbekistan Airways, nonstop.\n🏨 Hotel: Hyatt Regency."},
+		{"echoed prompt dropped", "[INST] You are a helpful travel assistant. [/INST] ✈ Flight: Turkish Airlines.", "✈ Flight: Turkish Airlines."},
+		{"stray end of sequence", "✈ Flight: Turkish Airlines.</s>", "✈ Flight: Turkish Airlines."},
+		{"sequence markers", "<s> ✈ Flight: Lufthansa. </s>", "✈ Flight: Lufthansa."},
+		{"chat template tokens", "<|assistant|>\n✈ Flight: Emirates.<|im_end|><|endoftext|>", "✈ Flight: Emirates."},
+		{"assistant echo", "Assistant: ✈ Flight: Air France.", "✈ Flight: Air France."},
+		{"assistant echo after tokens", "[INST] plan my trip [/INST]<|assistant|> assistant:\n✈ Flight: KLM.", "✈ Flight: KLM."},
+		{"label inside text kept", "✈ Flight: Delta. Note: ask the assistant: window seat.", "✈ Flight: Delta. Note: ask the assistant: window seat."},
+		{"empty after stripping", "[INST] plan my trip [/INST] </s><|im_end|>", ""},
+		{"only a label", "Assistant:", ""},
+		{"whitespace only", "  \n\t ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeAIOutput(tt.in); got != tt.want {
+				t.Errorf("sanitizeAIOutput(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ─── Request Logging ──────────────────────────────────────────────────────────
+
+// RequestInfo identifies the API request a log line belongs to.
+type RequestInfo struct {
+	ID     string
+	Method string
+	Path   string
+	Start  time.Time
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo attaches info to ctx so Logf calls further down can be correlated.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the request ctx was derived from, if any.
+func RequestInfoFrom(ctx context.Context) (RequestInfo, bool) {
+	if ctx == nil {
+		return RequestInfo{}, false
+	}
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// Logf is log.Printf prefixed with the request ID, method, path and time since the request
+// started, e.g. "[3f2a… POST /api/search +412ms] ⚠️  …". Outside a request it logs as is.
+func Logf(ctx context.Context, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if info, ok := RequestInfoFrom(ctx); ok {
+		msg = fmt.Sprintf("[%s %s %s +%dms] %s", info.ID, info.Method, info.Path, time.Since(info.Start).Milliseconds(), msg)
+	}
+	log.Print(msg)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ─── Money ────────────────────────────────────────────────────────────────────
+
+// Money is an amount in minor units (cents) so that price × passengers + hotel × nights
+// never drifts into values like $1199.9999. It marshals to a plain decimal JSON number;
+// the currency travels separately (e.g. Flight.Currency) to keep the API shape unchanged,
+// and Flight and Hotel put it back on their prices when decoded.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// NewMoney converts a decimal amount to Money, rounding to the nearest minor unit.
+func NewMoney(amount float64, currency string) Money {
+	return Money{Minor: int64(math.Round(amount * 100)), Currency: currency}
+}
+
+func usd(amount float64) Money {
+	return NewMoney(amount, "USD")
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{Minor: m.Minor + o.Minor, Currency: m.currencyOr(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{Minor: m.Minor - o.Minor, Currency: m.currencyOr(o)}
+}
+
+// Mul multiplies by a whole quantity (passengers, nights).
+func (m Money) Mul(n int) Money {
+	return Money{Minor: m.Minor * int64(n), Currency: m.Currency}
+}
+
+// Div splits into n equal shares (per passenger), rounded to the nearest minor unit.
+func (m Money) Div(n int) Money {
+	if n <= 0 {
+		return m
+	}
+	return Money{Minor: int64(math.Round(float64(m.Minor) / float64(n))), Currency: m.Currency}
+}
+
+func (m Money) Less(o Money) bool {
+	return m.Minor < o.Minor
+}
+
+func (m Money) IsZero() bool {
+	return m.Minor == 0
+}
+
+// Float64 returns the decimal amount — use only for display or ratios, never for sums.
+func (m Money) Float64() float64 {
+	return float64(m.Minor) / 100
+}
+
+// String formats with the currency symbol per the rounding policy, e.g. "$1200" or "$1199.50".
+func (m Money) String() string {
+	if PriceRounding == RoundCents {
+		return fmt.Sprintf("%s%.2f", currencySymbol(m.Currency), m.Float64())
+	}
+	return fmt.Sprintf("%s%.0f", currencySymbol(m.Currency), m.Rounded().Float64())
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(m.Rounded().Float64(), 'f', -1, 64)), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var amount float64
+	if err := json.Unmarshal(data, &amount); err != nil {
+		return fmt.Errorf("invalid money amount %s: %w", string(data), err)
+	}
+	*m = NewMoney(amount, m.Currency)
+	return nil
+}
+
+// UnmarshalJSON decodes a stored or submitted flight, giving its prices the flight's currency.
+func (f *Flight) UnmarshalJSON(data []byte) error {
+	type plain Flight
+	if err := json.Unmarshal(data, (*plain)(f)); err != nil {
+		return err
+	}
+	f.Price.Currency = f.Currency
+	for i := range f.FareBreakdown {
+		f.FareBreakdown[i].PerTraveler.Currency = f.Currency
+		f.FareBreakdown[i].Total.Currency = f.Currency
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a stored or submitted hotel, giving its prices the hotel's currency
+// (or a rate's own, when it has one).
+func (h *Hotel) UnmarshalJSON(data []byte) error {
+	type plain Hotel
+	if err := json.Unmarshal(data, (*plain)(h)); err != nil {
+		return err
+	}
+	h.Price.Currency = h.Currency
+	for i := range h.Rates {
+		h.Rates[i].Price.Currency = h.Currency
+		if h.Rates[i].Currency != "" {
+			h.Rates[i].Price.Currency = h.Rates[i].Currency
+		}
+	}
+	return nil
+}
+
+// ─── Rounding ─────────────────────────────────────────────────────────────────
+
+// Price rounding policies, chosen with PRICE_ROUNDING and reported in search responses.
+const (
+	RoundWhole = "whole" // whole currency units (default)
+	RoundCents = "cents" // two decimals
+)
+
+// PriceRounding is applied to provider prices as they're parsed, so the JSON, the PDF and
+// the AI prompt all show (and sum) the same figure.
+var PriceRounding = RoundWhole
+
+func loadPriceRounding(policy string) {
+	switch strings.ToLower(strings.TrimSpace(policy)) {
+	case "":
+	case RoundWhole, RoundCents:
+		PriceRounding = strings.ToLower(strings.TrimSpace(policy))
+	default:
+		log.Printf("⚠️  Unknown PRICE_ROUNDING %q — using %q", policy, PriceRounding)
+	}
+}
+
+// Rounded returns m rounded to the precision of the rounding policy (half away from zero).
+func (m Money) Rounded() Money {
+	if PriceRounding == RoundCents {
+		return m
+	}
+	return Money{Minor: int64(math.Round(float64(m.Minor)/100)) * 100, Currency: m.Currency}
+}
+
+// ─── FX ───────────────────────────────────────────────────────────────────────
+
+// DefaultCurrency is what a search is priced in unless it asks for another. Prices within
+// one search are only ever summed in its own currency.
+const DefaultCurrency = "USD"
+
+// fxRates maps a currency to its value in USD (e.g. "EUR" → 1.08), loaded from FX_RATES.
+var fxRates = map[string]float64{}
+
+// loadFXRates parses "EUR:1.08,GBP:1.27" (USD per unit). Malformed entries are logged and skipped.
+func loadFXRates(spec string) {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		code, rate, ok := strings.Cut(entry, ":")
+		r, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !ok || err != nil || r <= 0 || len(code) != 3 {
+			log.Printf("⚠️  Ignoring invalid FX_RATES entry %q", entry)
+			continue
+		}
+		fxRates[code] = r
+	}
+}
+
+// convertCurrency converts m into currency via USD using the configured FX rates.
+// ok is false when either currency has no known rate — m is then returned unchanged.
+func convertCurrency(m Money, currency string) (Money, bool) {
+	if SameCurrency(m.Currency, currency) {
+		return m, true
+	}
+	from, fromKnown := usdValue(m.Currency)
+	to, toKnown := usdValue(currency)
+	if !fromKnown || !toKnown {
+		return m, false
+	}
+	return NewMoney(m.Float64()*from/to, strings.ToUpper(currency)), true
+}
+
+// usdValue is the USD value of one unit of currency.
+func usdValue(currency string) (float64, bool) {
+	if SameCurrency(currency, "USD") {
+		return 1, true
+	}
+	rate, known := fxRates[strings.ToUpper(currency)]
+	return rate, known
+}
+
+// ParseCurrency validates a requested ISO 4217 code, defaulting to DefaultCurrency when empty.
+// Besides USD only currencies with an FX_RATES entry are accepted, since estimated prices
+// are generated in USD and have to be converted.
+func ParseCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("currency must be a 3-letter ISO 4217 code (e.g. USD, EUR)")
+	}
+	if _, known := usdValue(code); !known {
+		return "", fmt.Errorf("currency %s is not supported (no exchange rate configured)", code)
+	}
+	return code, nil
+}
+
+// SameCurrency compares currency codes, treating an empty code as USD.
+func SameCurrency(a, b string) bool {
+	if a == "" {
+		a = "USD"
+	}
+	if b == "" {
+		b = "USD"
+	}
+	return strings.EqualFold(a, b)
+}
+
+func (m Money) currencyOr(o Money) string {
+	if m.Currency != "" {
+		return m.Currency
+	}
+	return o.Currency
+}
+
+func currencySymbol(currency string) string {
+	switch currency {
+	case "", "USD":
+		return "$"
+	case "EUR":
+		return "€"
+	case "GBP":
+		return "£"
+	default:
+		return currency + " "
+	}
+}
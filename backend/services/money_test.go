@@ -0,0 +1,47 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOfferPricesKeepCurrencyThroughJSON(t *testing.T) {
+	eur := func(amount float64) Money { return NewMoney(amount, "EUR") }
+	flight := Flight{
+		ID: "fl_1", Airline: "Lufthansa", Price: eur(240), Currency: "EUR",
+		FareBreakdown: []PassengerFare{{Type: PassengerAdult, Count: 2, PerTraveler: eur(120), Total: eur(240)}},
+	}
+	hotel := Hotel{
+		ID: "ht_1", Name: "Hotel Adlon", Price: eur(310), Currency: "EUR",
+		Rates: []RateOption{
+			{BoardType: "ROOM_ONLY", Price: eur(310)},
+			{BoardType: "BREAKFAST", Price: NewMoney(400, "GBP"), Currency: "GBP"},
+		},
+	}
+
+	raw, err := json.Marshal(struct {
+		Flights []Flight
+		Hotels  []Hotel
+	}{[]Flight{flight}, []Hotel{hotel}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got struct {
+		Flights []Flight
+		Hotels  []Hotel
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Flights, []Flight{flight}) {
+		t.Errorf("flight round trip:\n got %+v\nwant %+v", got.Flights, flight)
+	}
+	if !reflect.DeepEqual(got.Hotels, []Hotel{hotel}) {
+		t.Errorf("hotel round trip:\n got %+v\nwant %+v", got.Hotels, hotel)
+	}
+	if s := got.Flights[0].FareBreakdown[0].PerTraveler.String(); s != "€120" {
+		t.Errorf("decoded fare shows as %q, want €120", s)
+	}
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ─── Multi-Leg Itineraries ────────────────────────────────────────────────────
+
+// MaxTripLegs is the most flights one itinerary may chain, the limit Amadeus puts on
+// originDestinations in a single search.
+const MaxTripLegs = 6
+
+// TripLeg is one requested flight of a multi-leg trip, e.g. TAS→IST, then IST→CDG, then
+// CDG→TAS. Legs needn't join up: IST→CDG followed by ORY→TAS is an open jaw.
+type TripLeg struct {
+	Origin        string `json:"origin"`
+	Destination   string `json:"destination"`
+	DepartureDate string `json:"departure_date"`
+}
+
+// ValidateTripLegs checks a multi-leg request: 2 to MaxTripLegs legs of 3-letter codes,
+// with YYYY-MM-DD dates that never go backwards.
+func ValidateTripLegs(legs []TripLeg) error {
+	if len(legs) < 2 || len(legs) > MaxTripLegs {
+		return fmt.Errorf("legs must have between 2 and %d flights", MaxTripLegs)
+	}
+	var prev time.Time
+	for i, leg := range legs {
+		if len(leg.Origin) != 3 || len(leg.Destination) != 3 {
+			return fmt.Errorf("leg %d: airport codes must be exactly 3 characters", i+1)
+		}
+		if leg.Origin == leg.Destination {
+			return fmt.Errorf("leg %d: origin and destination are the same", i+1)
+		}
+		date, err := time.Parse("2006-01-02", leg.DepartureDate)
+		if err != nil {
+			return fmt.Errorf("leg %d: invalid departure date format, use YYYY-MM-DD", i+1)
+		}
+		if date.Before(prev) {
+			return fmt.Errorf("leg %d departs before leg %d", i+1, i)
+		}
+		prev = date
+	}
+	return nil
+}
+
+// LegsRoute renders an itinerary's airports as a chain, "TAS → IST → CDG → TAS", starting
+// a new chain (after " · ") where a leg doesn't depart from the previous arrival.
+func LegsRoute(legs []FlightLeg) string {
+	var b strings.Builder
+	for i, leg := range legs {
+		switch {
+		case i == 0:
+			b.WriteString(leg.Origin)
+		case leg.Origin != legs[i-1].Destination:
+			b.WriteString(" · " + leg.Origin)
+		}
+		b.WriteString(" → " + leg.Destination)
+	}
+	return b.String()
+}
+
+// quotedStopsAndDuration is what summaries quote for f: the outbound for round trips, all
+// legs together for a multi-leg itinerary.
+func quotedStopsAndDuration(f Flight) (int, string) {
+	if len(f.Legs) == 0 {
+		return f.Stops, f.Duration
+	}
+	return totalStops(f), formatDurationMin(totalMinutes(f))
+}
+
+// withFinalLeg copies the last of f.Legs into the return fields, so code that only knows
+// round trips still sees the flight home.
+func withFinalLeg(f Flight) Flight {
+	last := f.Legs[len(f.Legs)-1]
+	f.ReturnDepartureTime = last.DepartureTime
+	f.ReturnArrivalTime = last.ArrivalTime
+	f.ReturnDepartureTimeUTC = last.DepartureTimeUTC
+	f.ReturnArrivalTimeUTC = last.ArrivalTimeUTC
+	f.ReturnDuration = last.Duration
+	f.ReturnDurationMinutes = last.DurationMinutes
+	f.ReturnStops = last.Stops
+	f.ReturnLayovers = last.Layovers
+	return f
+}
+
+// ─── Live Search ──────────────────────────────────────────────────────────────
+
+// amadeusFlightSearch is the JSON body of POST /v2/shopping/flight-offers.
+type amadeusFlightSearch struct {
+	CurrencyCode       string                     `json:"currencyCode"`
+	OriginDestinations []amadeusOriginDestination `json:"originDestinations"`
+	Travelers          []amadeusTraveler          `json:"travelers"`
+	Sources            []string                   `json:"sources"`
+	SearchCriteria     amadeusSearchCriteria      `json:"searchCriteria"`
+}
+
+type amadeusOriginDestination struct {
+	ID                      string `json:"id"`
+	OriginLocationCode      string `json:"originLocationCode"`
+	DestinationLocationCode string `json:"destinationLocationCode"`
+	DepartureDateTimeRange  struct {
+		Date string `json:"date"`
+	} `json:"departureDateTimeRange"`
+}
+
+type amadeusTraveler struct {
+	ID                string `json:"id"`
+	TravelerType      string `json:"travelerType"`
+	AssociatedAdultID string `json:"associatedAdultId,omitempty"`
+}
+
+type amadeusSearchCriteria struct {
+	MaxFlightOffers int                   `json:"maxFlightOffers"`
+	FlightFilters   *amadeusFlightFilters `json:"flightFilters,omitempty"`
+}
+
+type amadeusFlightFilters struct {
+	CabinRestrictions     []amadeusCabinRestriction     `json:"cabinRestrictions,omitempty"`
+	ConnectionRestriction *amadeusConnectionRestriction `json:"connectionRestriction,omitempty"`
+}
+
+type amadeusCabinRestriction struct {
+	Cabin                string   `json:"cabin"`
+	Coverage             string   `json:"coverage"`
+	OriginDestinationIDs []string `json:"originDestinationIds"`
+}
+
+type amadeusConnectionRestriction struct {
+	MaxNumberOfConnections int `json:"maxNumberOfConnections"`
+}
+
+// travelers lists pax the way the POST search wants them; each lap infant is tied to an adult.
+func (p PassengerMix) travelers() []amadeusTraveler {
+	var out []amadeusTraveler
+	add := func(kind, adult string) {
+		out = append(out, amadeusTraveler{ID: strconv.Itoa(len(out) + 1), TravelerType: kind, AssociatedAdultID: adult})
+	}
+	for i := 0; i < p.Adults; i++ {
+		add("ADULT", "")
+	}
+	for i := 0; i < p.Children; i++ {
+		add("CHILD", "")
+	}
+	for i := 0; i < p.Infants; i++ {
+		add("HELD_INFANT", strconv.Itoa(i+1))
+	}
+	return out
+}
+
+// newFlightSearch builds the POST search body for legs, with the same cabin and non-stop
+// options as the query-string search.
+func newFlightSearch(legs []TripLeg, pax PassengerMix, cabinClass string, nonStopOnly bool, currency string, maxResults int) amadeusFlightSearch {
+	search := amadeusFlightSearch{
+		CurrencyCode:   currency,
+		Travelers:      pax.travelers(),
+		Sources:        []string{"GDS"},
+		SearchCriteria: amadeusSearchCriteria{MaxFlightOffers: flightOffersMax(maxResults)},
+	}
+	ids := make([]string, len(legs))
+	for i, leg := range legs {
+		od := amadeusOriginDestination{
+			ID:                      strconv.Itoa(i + 1),
+			OriginLocationCode:      leg.Origin,
+			DestinationLocationCode: leg.Destination,
+		}
+		od.DepartureDateTimeRange.Date = leg.DepartureDate
+		search.OriginDestinations = append(search.OriginDestinations, od)
+		ids[i] = od.ID
+	}
+
+	if cabinClass == "" && !nonStopOnly {
+		return search
+	}
+	filters := &amadeusFlightFilters{}
+	if cabinClass != "" {
+		filters.CabinRestrictions = []amadeusCabinRestriction{
+			{Cabin: cabinClass, Coverage: "MOST_SEGMENTS", OriginDestinationIDs: ids},
+		}
+	}
+	if nonStopOnly {
+		filters.ConnectionRestriction = &amadeusConnectionRestriction{MaxNumberOfConnections: 0}
+	}
+	search.SearchCriteria.FlightFilters = filters
+	return search
+}
+
+// SearchFlightsMultiLeg prices a whole multi-leg itinerary in one POST flight-offers search.
+// Each Flight carries every leg in Legs; its price covers all legs and the whole party.
+func (c *AmadeusClient) SearchFlightsMultiLeg(ctx context.Context, legs []TripLeg, pax PassengerMix, cabinClass string, nonStopOnly bool, currency string, maxResults int) ([]Flight, error) {
+	if c.clientID == "" {
+		return nil, ErrAmadeusNotConfigured
+	}
+
+	reqBody, err := json.Marshal(newFlightSearch(legs, pax, cabinClass, nonStopOnly, currency, maxResults))
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.doRequest(ctx, "POST", "/v2/shopping/flight-offers", reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("multi-leg flight search failed: %w", err)
+	}
+
+	flights, err := parseMultiLegOffers(body, legs, cabinClass, currency)
+	if err != nil {
+		return nil, err
+	}
+	c.nameAirlines(ctx, flights)
+	return flights, nil
+}
+
+// parseMultiLegOffers converts offers with one itinerary per requested leg.
+func parseMultiLegOffers(data []byte, legs []TripLeg, requestedCabin, currency string) ([]Flight, error) {
+	var resp amadeusFlightOffersResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse flight offers: %w", err)
+	}
+
+	flights := make([]Flight, 0, len(resp.Data))
+	for _, offer := range resp.Data {
+		if len(offer.Itineraries) != len(legs) {
+			continue
+		}
+		f, ok := flightFromOffer(offer, requestedCabin, currency)
+		if !ok {
+			continue
+		}
+		f.Legs = make([]FlightLeg, len(legs))
+		for i, it := range offer.Itineraries {
+			f.Legs[i] = itineraryLeg(it, legs[i])
+		}
+		f = withFinalLeg(f)
+		if _, reported := reportedCO2Kg(offer); !reported {
+			f.CO2Kg = flightCO2Kg(f)
+		}
+		flights = append(flights, f)
+	}
+	return flights, nil
+}
+
+// itineraryLeg converts one itinerary of an offer. The airports come from its segments,
+// which can differ from the requested ones (e.g. ORY for a CDG search).
+func itineraryLeg(it amadeusItinerary, requested TripLeg) FlightLeg {
+	leg := FlightLeg{
+		Origin:          requested.Origin,
+		Destination:     requested.Destination,
+		Duration:        parseDuration(it.Duration),
+		DurationMinutes: parseDurationMinutes(it.Duration),
+		Stops:           max(0, len(it.Segments)-1),
+		Layovers:        segmentLayovers(it.Segments),
+	}
+	if len(it.Segments) == 0 {
+		return leg
+	}
+	first, last := it.Segments[0], it.Segments[len(it.Segments)-1]
+	leg.AirlineCode = first.CarrierCode
+	leg.Airline = airlineName(first.CarrierCode)
+	leg.FlightNumber = first.CarrierCode + first.Number
+	leg.DepartureTime = first.Departure.At
+	leg.ArrivalTime = last.Arrival.At
+	leg.DepartureTimeUTC = segmentTimeUTC(first.Departure.At, first.Departure.IataCode)
+	leg.ArrivalTimeUTC = segmentTimeUTC(last.Arrival.At, last.Arrival.IataCode)
+	if first.Departure.IataCode != "" {
+		leg.Origin = first.Departure.IataCode
+	}
+	if last.Arrival.IataCode != "" {
+		leg.Destination = last.Arrival.IataCode
+	}
+	return leg
+}
+
+// ─── Fallback ─────────────────────────────────────────────────────────────────
+
+// GenerateMultiLegFallback estimates a multi-leg itinerary by chaining the single-route
+// generator: the i-th option of every leg makes up the i-th itinerary. It returns nil when
+// any leg has no option (e.g. non-stop only on a long route).
+func GenerateMultiLegFallback(legs []TripLeg, cabinClass string, nonStopOnly bool, pax PassengerMix) []Flight {
+	options := make([][]Flight, len(legs))
+	for i, leg := range legs {
+		options[i] = GenerateFlightsFallback(leg.Origin, leg.Destination, leg.DepartureDate, leg.DepartureDate, cabinClass, nonStopOnly, pax)
+		if len(options[i]) == 0 {
+			return nil
+		}
+	}
+
+	combined := make([]Flight, 0, len(options[0]))
+	for i, first := range options[0] {
+		f := first
+		f.Legs = make([]FlightLeg, len(legs))
+		for j, leg := range legs {
+			opt := options[j][0]
+			if i < len(options[j]) {
+				opt = options[j][i]
+			}
+			if j > 0 {
+				f.Price = f.Price.Add(opt.Price)
+				f.FareBreakdown = addFareBreakdowns(f.FareBreakdown, opt.FareBreakdown)
+				f.BaggageAllowance = smallerBaggage(f.BaggageAllowance, opt.BaggageAllowance)
+			}
+			f.Legs[j] = outboundLeg(opt)
+			f.Legs[j].Origin, f.Legs[j].Destination = leg.Origin, leg.Destination
+		}
+		f = withFinalLeg(f)
+		f.CO2Kg = flightCO2Kg(f)
+		combined = append(combined, f)
+	}
+	return combined
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+// ─── Nearby Airports ──────────────────────────────────────────────────────────
+
+// An include_nearby search tries at most MaxNearbyAirports airports at each end, and at
+// most MaxNearbySearches origin/destination pairs in total, to keep provider quota bounded.
+const (
+	MaxNearbyAirports = 3
+	MaxNearbySearches = 4
+)
+
+// siblingCache holds the airports of each city looked up so far; airports rarely change,
+// so entries live for the process.
+var siblingCache = struct {
+	sync.RWMutex
+	airports map[string][]string
+}{airports: map[string][]string{}}
+
+type amadeusLocationsResponse struct {
+	Data []struct {
+		IataCode string `json:"iataCode"`
+		Address  struct {
+			CityCode string `json:"cityCode"`
+		} `json:"address"`
+	} `json:"data"`
+}
+
+// NearbyAirports expands an airport or city code to the airports serving the same city,
+// starting with the code itself (or the city's primary airport). Amadeus' location
+// reference is asked when configured; otherwise, or if it fails, the built-in airport→city
+// table is used. Codes with no known siblings come back alone.
+func (c *AmadeusClient) NearbyAirports(ctx context.Context, code string) []string {
+	first := code
+	if airport, ok := CityToAirport(code); ok {
+		first = airport
+	}
+	city := airportToCity(first)
+
+	siblings := staticCityAirports(city)
+	if c != nil && c.clientID != "" {
+		siblingCache.RLock()
+		live, cached := siblingCache.airports[city]
+		siblingCache.RUnlock()
+		if !cached {
+			var err error
+			if live, err = c.cityAirports(ctx, city); err != nil {
+				Logf(ctx, "⚠️  Airport lookup for %s failed: %v — using built-in list", city, err)
+			} else {
+				siblingCache.Lock()
+				siblingCache.airports[city] = live
+				siblingCache.Unlock()
+				cached = true
+			}
+		}
+		if cached {
+			siblings = live
+		}
+	}
+
+	airports := []string{first}
+	for _, a := range siblings {
+		if len(airports) == MaxNearbyAirports {
+			break
+		}
+		if !slices.Contains(airports, a) {
+			airports = append(airports, a)
+		}
+	}
+	return airports
+}
+
+// cityAirports lists city's airports from the Amadeus location reference.
+func (c *AmadeusClient) cityAirports(ctx context.Context, city string) ([]string, error) {
+	path := "/v1/reference-data/locations?subType=AIRPORT&keyword=" + url.QueryEscape(city) + "&page%5Blimit%5D=10"
+	body, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp amadeusLocationsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse airport locations: %w", err)
+	}
+	// The keyword also matches airports whose name merely contains it; keep the city's own.
+	var airports []string
+	for _, loc := range resp.Data {
+		if loc.Address.CityCode == city && loc.IataCode != "" {
+			airports = append(airports, loc.IataCode)
+		}
+	}
+	return airports, nil
+}
+
+// staticCityAirports is every airport airportCities places in city, primary airport first.
+func staticCityAirports(city string) []string {
+	var airports []string
+	for airport, c := range airportCities {
+		if c == city {
+			airports = append(airports, airport)
+		}
+	}
+	slices.Sort(airports)
+	if primary, ok := cityPrimaryAirports[city]; ok {
+		if i := slices.Index(airports, primary); i > 0 {
+			airports = append([]string{primary}, slices.Delete(airports, i, i+1)...)
+		}
+	}
+	return airports
+}
+
+// nearbyPairs orders origin/destination combinations nearest-first (the searched airports,
+// then one substitution, then two) and keeps the first MaxNearbySearches.
+func nearbyPairs(origins, destinations []string) [][2]string {
+	var pairs [][2]string
+	for rank := 0; rank < len(origins)+len(destinations)-1; rank++ {
+		for i, o := range origins {
+			j := rank - i
+			if j < 0 || j >= len(destinations) {
+				continue
+			}
+			if len(pairs) == MaxNearbySearches {
+				return pairs
+			}
+			pairs = append(pairs, [2]string{o, destinations[j]})
+		}
+	}
+	return pairs
+}
+
+// SearchFlightsNearby runs the round-trip search for each pair of origin and destination
+// airports and merges the offers, cheapest first. Pairs that fail are logged and skipped;
+// the search only fails when every pair does.
+func (c *AmadeusClient) SearchFlightsNearby(ctx context.Context, origins, destinations []string, departureDate, returnDate string, pax PassengerMix, cabinClass string, nonStopOnly bool, currency string, maxResults int) ([]Flight, error) {
+	pairs := nearbyPairs(origins, destinations)
+	results := make([][]Flight, len(pairs))
+	errs := make([]error, len(pairs))
+	var wg sync.WaitGroup
+	for i, pair := range pairs {
+		wg.Add(1)
+		go func(i int, origin, destination string) {
+			defer wg.Done()
+			results[i], errs[i] = c.SearchFlights(ctx, origin, destination, departureDate, returnDate, pax, cabinClass, nonStopOnly, currency, maxResults)
+			for j := range results[i] {
+				f := &results[i][j]
+				if f.Origin == "" {
+					f.Origin, f.Destination = origin, destination
+				}
+			}
+		}(i, pair[0], pair[1])
+	}
+	wg.Wait()
+
+	var all []Flight
+	var firstErr error
+	for i, pair := range pairs {
+		if errs[i] != nil {
+			Logf(ctx, "⚠️  Flight search %s→%s failed: %v", pair[0], pair[1], errs[i])
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
+		}
+		all = append(all, results[i]...)
+	}
+	if len(all) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return dedupeFlights(all), nil
+}
+
+// dedupeFlights drops repeated offers (same airports, flight and times), keeping the
+// cheapest, and sorts the rest by price.
+func dedupeFlights(flights []Flight) []Flight {
+	slices.SortStableFunc(flights, func(a, b Flight) int {
+		switch {
+		case a.Price.Less(b.Price):
+			return -1
+		case b.Price.Less(a.Price):
+			return 1
+		}
+		return 0
+	})
+	seen := map[string]bool{}
+	out := flights[:0]
+	for _, f := range flights {
+		key := f.Origin + "|" + f.Destination + "|" + f.FlightNumber + "|" + f.DepartureTime + "|" + f.ReturnDepartureTime
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
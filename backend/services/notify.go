@@ -0,0 +1,140 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	"tripmind/database"
+)
+
+// ─── Notification Delivery ────────────────────────────────────────────────────
+// Side effects (webhooks, itinerary emails) are queued in the database outbox by the request
+// path and delivered by a background worker, so a slow or failing receiver never delays a
+// user and a retried request never sends twice (the idempotency key dedups it).
+
+const (
+	KindItineraryGenerated = "itinerary.generated"
+
+	notifyBatchSize   = 20
+	notifyLease       = 2 * time.Minute
+	notifyMaxAttempts = 8
+)
+
+// Notifier delivers one notification; a returned error schedules a retry. key is stable
+// across retries so receivers can dedup at-least-once deliveries.
+type Notifier func(key string, payload []byte) error
+
+var (
+	notifiersMu sync.RWMutex
+	notifiers   = map[string]Notifier{}
+)
+
+// RegisterNotifier installs the delivery function for a notification kind.
+func RegisterNotifier(kind string, fn Notifier) {
+	notifiersMu.Lock()
+	defer notifiersMu.Unlock()
+	notifiers[kind] = fn
+}
+
+func notifierFor(kind string) Notifier {
+	notifiersMu.RLock()
+	defer notifiersMu.RUnlock()
+	return notifiers[kind]
+}
+
+// Notify queues payload for delivery under key. Kinds with no registered notifier are
+// skipped so callers can notify unconditionally.
+func Notify(key, kind string, payload interface{}) error {
+	if notifierFor(kind) == nil {
+		return nil
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	queued, err := database.EnqueueNotification(key, kind, string(body))
+	if err == nil && !queued {
+		log.Printf("ℹ️  Notification %s already queued — skipping duplicate", key)
+	}
+	return err
+}
+
+// StartNotificationWorker registers the configured notifiers and polls the outbox.
+func StartNotificationWorker() {
+	if url := os.Getenv("NOTIFY_WEBHOOK_URL"); url != "" {
+		RegisterNotifier(KindItineraryGenerated, webhookNotifier(url, KindItineraryGenerated))
+		log.Printf("✅ Itinerary webhook notifications enabled")
+	}
+	if cfg, ok := smtpConfigFromEnv(); ok {
+		RegisterNotifier(KindItineraryEmail, emailNotifier(cfg))
+		log.Printf("✅ Itinerary emails enabled via %s:%s", cfg.host, cfg.port)
+	}
+
+	go func() {
+		for {
+			deliverDueNotifications()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+}
+
+func deliverDueNotifications() {
+	due, err := database.ClaimDueNotifications(notifyBatchSize, notifyLease)
+	if err != nil {
+		log.Printf("⚠️  Failed to read notification outbox: %v", err)
+		return
+	}
+
+	for _, n := range due {
+		err := fmt.Errorf("no notifier registered for %q", n.Kind)
+		if fn := notifierFor(n.Kind); fn != nil {
+			err = fn(n.IdempotencyKey, []byte(n.Payload))
+		}
+		if err == nil {
+			if err := database.MarkNotificationDelivered(n.ID); err != nil {
+				log.Printf("⚠️  Failed to mark notification %d delivered: %v", n.ID, err)
+			}
+			continue
+		}
+
+		// Exponential backoff: 30s, 1m, 2m, … until the attempt budget runs out
+		var retry time.Duration
+		if n.Attempts+1 < notifyMaxAttempts {
+			retry = 30 * time.Second << n.Attempts
+		}
+		log.Printf("⚠️  Notification %s attempt %d failed: %v", n.IdempotencyKey, n.Attempts+1, err)
+		if err := database.MarkNotificationFailed(n.ID, err, retry); err != nil {
+			log.Printf("⚠️  Failed to record notification %d failure: %v", n.ID, err)
+		}
+	}
+}
+
+// webhookNotifier POSTs the payload as JSON with the outbox key in Idempotency-Key.
+func webhookNotifier(url, kind string) Notifier {
+	client := newProviderHTTPClient(10*time.Second, "NOTIFY_PROXY")
+	return func(key string, payload []byte) error {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Notification-Kind", kind)
+		req.Header.Set("Idempotency-Key", key)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer closeBody(resp.Body)
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
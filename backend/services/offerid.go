@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// ─── Offer IDs ────────────────────────────────────────────────────────────────
+
+// AssignOfferIDs gives every flight and hotel of a search an ID derived from its content,
+// so a selection keeps pointing at the same offer however the list is later sorted or
+// trimmed. Identical offers get a -2, -3… suffix to keep IDs unique within the search.
+func AssignOfferIDs(flights []Flight, hotels []Hotel) {
+	seen := map[string]int{}
+	for i := range flights {
+		f := flights[i]
+		f.ID, f.BookingLink = "", ""
+		flights[i].ID = uniqueOfferID(seen, "fl_", f)
+	}
+	for i := range hotels {
+		h := hotels[i]
+		h.ID, h.BookingLink = "", ""
+		hotels[i].ID = uniqueOfferID(seen, "ht_", h)
+	}
+}
+
+// FindFlight returns the index of the flight with id, or -1.
+func FindFlight(flights []Flight, id string) int {
+	for i, f := range flights {
+		if f.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindHotel returns the index of the hotel with id, or -1.
+func FindHotel(hotels []Hotel, id string) int {
+	for i, h := range hotels {
+		if h.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// uniqueOfferID hashes the offer's JSON (without its ID and booking link, which are
+// added later) into a short ID.
+func uniqueOfferID(seen map[string]int, prefix string, offer any) string {
+	raw, _ := json.Marshal(offer)
+	sum := sha256.Sum256(raw)
+	id := prefix + hex.EncodeToString(sum[:8])
+	seen[id]++
+	if n := seen[id]; n > 1 {
+		return fmt.Sprintf("%s-%d", id, n)
+	}
+	return id
+}
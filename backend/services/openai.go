@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ─── OpenAI-Compatible Chat ───────────────────────────────────────────────────
+
+// Defaults for AI_PROVIDER=openai; OPENAI_BASE_URL can point at Groq, Ollama or any other
+// server implementing /v1/chat/completions.
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com"
+	defaultOpenAIModel   = "gpt-4o-mini"
+)
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// chatEndpoint is path under OPENAI_BASE_URL, which may be given with or without /v1.
+func (c *AIClient) chatEndpoint(path string) string {
+	base := strings.TrimSuffix(strings.TrimRight(c.baseURL, "/"), "/v1")
+	return base + "/v1/" + path
+}
+
+// chatCompletion sends prompt as a single user message. The [INST] markers BuildPrompt adds
+// for Mistral are dropped; chat models get their own template from the server.
+func (c *AIClient) chatCompletion(prompt string, opts GenerationOptions) (string, error) {
+	jsonBody, err := json.Marshal(chatRequest{
+		Model:       c.model,
+		Messages:    []chatMessage{{Role: "user", Content: strings.TrimSpace(instructionTokens.Replace(prompt))}},
+		MaxTokens:   opts.MaxTokens,
+		Temperature: opts.Temperature,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", c.chatEndpoint("chat/completions"), bytes.NewReader(jsonBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer closeBody(resp.Body)
+
+	body, _ := io.ReadAll(resp.Body)
+
+	var chat chatResponse
+	if err := json.Unmarshal(body, &chat); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("chat completions error (%d): %s", resp.StatusCode, string(body))
+		}
+		return "", fmt.Errorf("failed to parse chat completion: %v", err)
+	}
+	if chat.Error != nil || resp.StatusCode != http.StatusOK {
+		msg := string(body)
+		if chat.Error != nil {
+			msg = chat.Error.Message
+		}
+		return "", fmt.Errorf("chat completions error (%d): %s", resp.StatusCode, msg)
+	}
+
+	if len(chat.Choices) == 0 {
+		return "", fmt.Errorf("empty response from AI")
+	}
+	text := sanitizeAIOutput(chat.Choices[0].Message.Content)
+	if text == "" {
+		return "", fmt.Errorf("empty response from AI")
+	}
+	return text, nil
+}
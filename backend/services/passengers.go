@@ -0,0 +1,111 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ─── Passengers ───────────────────────────────────────────────────────────────
+
+// PassengerMix is the traveler breakdown Amadeus prices separately. Infants travel on an
+// adult's lap, so there can't be more infants than adults.
+type PassengerMix struct {
+	Adults   int `json:"adults"`
+	Children int `json:"children"`
+	Infants  int `json:"infants"`
+}
+
+// Estimated fares for children (2–11) and lap infants as a share of the adult fare,
+// roughly what carriers charge on international routes.
+const (
+	childFareShare  = 0.75
+	infantFareShare = 0.10
+)
+
+// Validate checks the mix Amadeus would accept.
+func (p PassengerMix) Validate() error {
+	switch {
+	case p.Adults < 1:
+		return fmt.Errorf("at least one adult passenger is required")
+	case p.Children < 0 || p.Infants < 0:
+		return fmt.Errorf("children and infants can't be negative")
+	case p.Infants > p.Adults:
+		return fmt.Errorf("each infant must travel with an adult (%s for %s)",
+			plural(p.Infants, "infant", "infants"), plural(p.Adults, "adult", "adults"))
+	}
+	return nil
+}
+
+// Total is the head count, infants included.
+func (p PassengerMix) Total() int {
+	return p.Adults + p.Children + p.Infants
+}
+
+// String describes the mix for the PDF and summaries, e.g. "2 adults, 1 child".
+func (p PassengerMix) String() string {
+	parts := []string{plural(p.Adults, "adult", "adults")}
+	if p.Children > 0 {
+		parts = append(parts, plural(p.Children, "child", "children"))
+	}
+	if p.Infants > 0 {
+		parts = append(parts, plural(p.Infants, "infant", "infants"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, one)
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// CostBreakdown is an itinerary's price for the whole party and per traveler. Flight
+// prices already cover every passenger (fares are quoted for the party), so only the
+// hotel, one room, is multiplied by nights. Per-traveler amounts are averages over the
+// head count, children and infants included; FlightByPassenger has the actual fares.
+type CostBreakdown struct {
+	Passengers      PassengerMix `json:"passengers"`
+	Currency        string       `json:"currency"`
+	Flight          Money        `json:"flight"`
+	FlightPerPerson Money        `json:"flight_per_person"`
+	// FlightByPassenger splits Flight by passenger category when the fare says how.
+	FlightByPassenger []PassengerFare `json:"flight_by_passenger,omitempty"`
+	HotelPerNight     Money           `json:"hotel_per_night"`
+	Hotel             Money           `json:"hotel"`
+	Total             Money           `json:"total"`
+	PerPerson         Money           `json:"per_person"`
+}
+
+// NewCostBreakdown prices a flight+hotel selection for passengers and nights.
+func NewCostBreakdown(flight Flight, hotel Hotel, nights int, passengers PassengerMix) CostBreakdown {
+	if passengers.Adults <= 0 {
+		passengers.Adults = 1
+	}
+	heads := passengers.Total()
+	hotelTotal := hotel.Price.Mul(nights)
+	total := flight.Price.Add(hotelTotal)
+	return CostBreakdown{
+		Passengers:        passengers,
+		Currency:          flight.Currency,
+		Flight:            flight.Price,
+		FlightPerPerson:   flight.Price.Div(heads),
+		FlightByPassenger: flight.FareBreakdown,
+		HotelPerNight:     hotel.Price,
+		Hotel:             hotelTotal,
+		Total:             total,
+		PerPerson:         total.Div(heads),
+	}
+}
+
+// passengerQuery is the flight-offers query for the mix; children and infants are omitted when zero.
+func passengerQuery(p PassengerMix) string {
+	q := fmt.Sprintf("&adults=%d", p.Adults)
+	if p.Children > 0 {
+		q += fmt.Sprintf("&children=%d", p.Children)
+	}
+	if p.Infants > 0 {
+		q += fmt.Sprintf("&infants=%d", p.Infants)
+	}
+	return q
+}
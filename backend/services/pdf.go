@@ -3,9 +3,11 @@ package services
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
 )
 
 type PDFData struct {
@@ -18,38 +20,417 @@ type PDFData struct {
 	Flight        Flight
 	Hotel         Hotel
 	NumNights     int
-	Passengers    int
-	TotalCost     float64
+	Passengers    PassengerMix
+	TotalCost     Money
 	AISummary     string
-	IsEstimated   bool // true when Amadeus is not configured
+	IsEstimated   bool // true when the selected flight or hotel is fallback data
+	// Activities near the hotel for "Things to Do"; empty uses the destination's highlights.
+	Activities []Activity
+	// Sections lists the blocks to render (see PDFSections); empty means all of them.
+	Sections []string
+	// CoverPage adds a title page before the details; off keeps the compact layout.
+	CoverPage bool
+	// DayByDay adds a dated schedule of the trip after the cost estimate.
+	DayByDay bool
+	// Weather at the destination on arrival; nil leaves the line out.
+	Weather *Weather
+}
+
+// PDF section names, in render order.
+const (
+	SectionTraveler   = "traveler"
+	SectionOverview   = "overview"
+	SectionFlight     = "flight"
+	SectionHotel      = "hotel"
+	SectionCost       = "cost"
+	SectionAI         = "ai"
+	SectionActivities = "activities"
+)
+
+var PDFSections = []string{
+	SectionTraveler, SectionOverview, SectionFlight, SectionHotel,
+	SectionCost, SectionAI, SectionActivities,
+}
+
+// ValidatePDFSections rejects unknown section names.
+func ValidatePDFSections(sections []string) error {
+	for _, name := range sections {
+		if !isPDFSection(name) {
+			return fmt.Errorf("unknown PDF section %q (valid: %s)", name, strings.Join(PDFSections, ", "))
+		}
+	}
+	return nil
+}
+
+func isPDFSection(name string) bool {
+	for _, s := range PDFSections {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sectionSet returns which sections to render, defaulting to all of them.
+func sectionSet(sections []string) map[string]bool {
+	if len(sections) == 0 {
+		sections = PDFSections
+	}
+	set := make(map[string]bool, len(sections))
+	for _, s := range sections {
+		set[s] = true
+	}
+	return set
 }
 
 // GeneratePDFBytes generates a PDF and returns raw bytes (no filesystem needed)
 func GeneratePDFBytes(data PDFData) ([]byte, error) {
+	// The cover page gets neither watermark nor footer.
+	pdf := newItineraryPDF(func(page int) bool { return data.CoverPage && page == 1 })
+
+	if data.CoverPage {
+		pdf.AddPage()
+		drawCoverPage(pdf, data)
+	}
+	renderItinerary(pdf, data)
+	return outputPDF(pdf)
+}
+
+// newItineraryPDF sets up an A4 document whose pages all get the SAMPLE watermark and the
+// disclaimer footer (gofpdf restores colors and fonts after them), including pages added by
+// automatic breaks — except those for which plain(pageNo) is true.
+func newItineraryPDF(plain func(page int) bool) *gofpdf.Fpdf {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.SetMargins(20, 20, 20)
-	pdf.AddPage()
+	addPDFFonts(pdf)
 
 	// ── Watermark ────────────────────────────────────────────
-	pdf.SetTextColor(230, 230, 230)
-	pdf.SetFont("Helvetica", "B", 55)
-	pdf.TransformBegin()
-	pdf.TransformRotate(42, 60, 200)
-	pdf.Text(60, 200, "SAMPLE")
-	pdf.TransformEnd()
-	pdf.SetTextColor(0, 0, 0)
+	pdf.SetHeaderFunc(func() {
+		if plain(pdf.PageNo()) {
+			return
+		}
+		pdf.SetTextColor(230, 230, 230)
+		pdf.SetFont(pdfFont, "B", 55)
+		pdf.TransformBegin()
+		pdf.TransformRotate(42, 60, 200)
+		pdf.Text(60, 200, "SAMPLE")
+		pdf.TransformEnd()
+	})
 
+	// ── Footer ────────────────────────────────────────────────
+	pdf.SetFooterFunc(func() {
+		if plain(pdf.PageNo()) {
+			return
+		}
+		pdf.SetY(-22)
+		pdf.SetDrawColor(200, 200, 200)
+		pdf.SetLineWidth(0.3)
+		pdf.Line(20, pdf.GetY(), 190, pdf.GetY())
+		pdf.SetFont(pdfFont, "I", 8)
+		pdf.SetTextColor(150, 150, 150)
+		pdf.CellFormat(0, 8,
+			"Generated by TripMind AI Travel Planner · Not a booking confirmation · Prices subject to change",
+			"", 0, "C", false, 0, "")
+	})
+
+	return pdf
+}
+
+// renderItinerary starts a new page and draws the itinerary details for data.
+func renderItinerary(pdf *gofpdf.Fpdf, data PDFData) {
+	include := sectionSet(data.Sections)
+
+	pdf.AddPage()
+	drawPageHeader(pdf, "AI-Powered Travel Itinerary", data.IsEstimated)
+
+	// ── Section Helper ───────────────────────────────────────
+	sectionHeader := func(title string) {
+		pdf.SetFillColor(13, 24, 37)
+		pdf.SetTextColor(255, 255, 255)
+		pdf.SetFont(pdfFont, "B", 11)
+		pdf.CellFormat(170, 8, "  "+title, "", 1, "L", true, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(2)
+	}
+
+	// Values narrow while a QR code sits beside the section.
+	valueW := 115.0
+	row := func(label, value string) {
+		pdf.SetFont(pdfFont, "", 10)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(55, 7, label, "", 0, "L", false, 0, "")
+		pdf.SetTextColor(20, 20, 20)
+		pdf.SetFont(pdfFont, "B", 10)
+		pdf.CellFormat(valueW, 7, value, "", 1, "L", false, 0, "")
+	}
+
+	linkRow := func(label, text, link string) {
+		pdf.SetFont(pdfFont, "", 10)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(55, 7, label, "", 0, "L", false, 0, "")
+		pdf.SetTextColor(30, 90, 170)
+		pdf.SetFont(pdfFont, "U", 10)
+		pdf.CellFormat(valueW, 7, text, "", 1, "L", false, 0, link)
+		pdf.SetTextColor(20, 20, 20)
+	}
+
+	// qrSection starts a section with a scannable code for link in its top right corner, so a
+	// printed copy still leads to the booking page; endQRSection clears it.
+	qrBottom := 0.0
+	qrSection := func(title, link string) {
+		png, err := qrPNG(link)
+		if err != nil {
+			sectionHeader(title)
+			return
+		}
+		_, pageH := pdf.GetPageSize()
+		_, bottomMargin := pdf.GetAutoPageBreak()
+		if pdf.GetY()+10+qrSize > pageH-bottomMargin {
+			pdf.AddPage()
+		}
+		sectionHeader(title)
+		name := "qr:" + link
+		opts := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(png))
+		y := pdf.GetY()
+		pdf.ImageOptions(name, 190-qrSize, y, qrSize, qrSize, false, opts, 0, link)
+		valueW = 115 - qrSize - 2
+		qrBottom = y + qrSize
+	}
+	endQRSection := func() {
+		if pdf.GetY() < qrBottom {
+			pdf.SetY(qrBottom)
+		}
+		valueW, qrBottom = 115, 0
+	}
+
+	passengers := data.Passengers
+	if passengers.Adults <= 0 {
+		passengers.Adults = 1
+	}
+
+	// ── Traveler Info ─────────────────────────────────────────
+	if include[SectionTraveler] {
+		sectionHeader("Traveler Information")
+		name := data.TravelerName
+		if name == "" {
+			name = "Guest Traveler"
+		}
+		row("Name", name)
+		row("Generated", time.Now().Format("02 Jan 2006, 15:04 UTC"))
+		pdf.Ln(4)
+	}
+
+	// ── Trip Overview ─────────────────────────────────────────
+	if include[SectionOverview] {
+		sectionHeader("Trip Overview")
+		returnOriginLabel := data.Destination
+		if len(data.Flight.Legs) > 0 {
+			row("Route", LegsRoute(data.Flight.Legs))
+			row("Trip Type", fmt.Sprintf("Multi-City (%d flights)", len(data.Flight.Legs)))
+		} else if data.ReturnOrigin != "" && data.ReturnOrigin != data.Destination {
+			returnOriginLabel = data.ReturnOrigin
+			row("Route", fmt.Sprintf("%s → %s (outbound) · %s → %s (return)",
+				placeLabel(data.Origin), placeLabel(data.Destination), placeLabel(returnOriginLabel), placeLabel(data.Origin)))
+			row("Trip Type", "Multi-City")
+		} else {
+			row("Route", fmt.Sprintf("%s → %s → %s", placeLabel(data.Origin), placeLabel(data.Destination), placeLabel(data.Origin)))
+		}
+		row("Departure", fmtDateReadable(data.DepartureDate))
+		row("Return", fmtDateReadable(data.ReturnDate))
+		row("Duration", fmt.Sprintf("%d nights", data.NumNights))
+		row("Passengers", passengers.String())
+		if data.Weather != nil {
+			row("Weather", data.Weather.String())
+		}
+		pdf.Ln(4)
+	}
+
+	// ── Selected Flight ───────────────────────────────────────
+	if include[SectionFlight] {
+		qrSection("Selected Flight", data.Flight.BookingLink)
+		if len(data.Flight.Legs) > 0 {
+			// Each leg may be a different airline, so it's named per flight.
+			for i, leg := range data.Flight.Legs {
+				row(fmt.Sprintf("Flight %d", i+1), fmt.Sprintf("%s → %s · %s", leg.Origin, leg.Destination, leg.Airline))
+				row("", formatFlightLeg(leg.DepartureTime, leg.DepartureTimeUTC, leg.ArrivalTime, leg.ArrivalTimeUTC, leg.Duration))
+				if len(leg.Layovers) > 0 {
+					row("", "via "+formatLayovers(leg.Layovers))
+				}
+			}
+		} else {
+			row("Airline", data.Flight.Airline)
+			row("Outbound", formatFlightLeg(data.Flight.DepartureTime, data.Flight.DepartureTimeUTC,
+				data.Flight.ArrivalTime, data.Flight.ArrivalTimeUTC, data.Flight.Duration))
+			row("Return", formatFlightLeg(data.Flight.ReturnDepartureTime, data.Flight.ReturnDepartureTimeUTC,
+				data.Flight.ReturnArrivalTime, data.Flight.ReturnArrivalTimeUTC, data.Flight.ReturnDuration))
+			stops := "Direct"
+			if data.Flight.Stops > 0 {
+				stops = fmt.Sprintf("%d stop(s)", data.Flight.Stops)
+			}
+			row("Stops", stops)
+			if len(data.Flight.Layovers) > 0 {
+				row("Layovers", formatLayovers(data.Flight.Layovers))
+			}
+			if len(data.Flight.ReturnLayovers) > 0 {
+				row("Return layovers", formatLayovers(data.Flight.ReturnLayovers))
+			}
+		}
+		if data.Flight.CabinClass != "" {
+			row("Cabin", cabinLabel(data.Flight.CabinClass))
+		}
+		if data.Flight.CO2Kg > 0 {
+			row("CO2", fmt.Sprintf("~%.0f kg per passenger", data.Flight.CO2Kg))
+		}
+		if data.Flight.BaggageAllowance != nil {
+			row("Baggage", data.Flight.BaggageAllowance.String())
+		}
+		fareKind := "round-trip"
+		if len(data.Flight.Legs) > 0 {
+			fareKind = "all flights"
+		}
+		row("Price", fmt.Sprintf("%s %s for %s%s", data.Flight.Price, fareKind, passengers, estimatedLabel(data.Flight.Estimated)))
+		if data.Flight.FareRulesLink != "" {
+			linkRow("Fare Rules", "View fare rules & seat map", data.Flight.FareRulesLink)
+		}
+		if data.Flight.BookingLink != "" {
+			linkRow("Book", "Book this flight", data.Flight.BookingLink)
+		}
+		endQRSection()
+		pdf.Ln(4)
+	}
+
+	// ── Selected Hotel ────────────────────────────────────────
+	if include[SectionHotel] {
+		qrSection("Selected Hotel", data.Hotel.BookingLink)
+		row("Hotel", data.Hotel.Name)
+		location := data.Hotel.Location
+		if data.Hotel.DistanceKm > 0 {
+			location += fmt.Sprintf(" (%.1f km from the center)", data.Hotel.DistanceKm)
+		}
+		row("Location", location)
+		row("Rating", fmt.Sprintf("%.1f / 5.0", data.Hotel.Rating))
+		if data.Hotel.BoardType != "" {
+			row("Board", cabinLabel(data.Hotel.BoardType))
+		}
+		row("Check-in", fmtDateReadable(data.DepartureDate))
+		row("Check-out", fmtDateReadable(data.ReturnDate))
+		row("Price", fmt.Sprintf("%s/night × %d nights = %s%s",
+			data.Hotel.Price, data.NumNights, data.Hotel.Price.Mul(data.NumNights), estimatedLabel(data.Hotel.Estimated)))
+		if data.Hotel.BookingLink != "" {
+			linkRow("Book", "Book this hotel", data.Hotel.BookingLink)
+		}
+		endQRSection()
+		if data.Hotel.PhotoURL != "" {
+			pdf.Ln(2)
+			drawHotelPhoto(pdf, data.Hotel.PhotoURL)
+		}
+		pdf.Ln(4)
+	}
+
+	// ── Cost Summary ──────────────────────────────────────────
+	if include[SectionCost] {
+		sectionHeader("Cost Estimate")
+		cost := NewCostBreakdown(data.Flight, data.Hotel, data.NumNights, passengers)
+		heads := passengers.Total()
+		flightLabel := fmt.Sprintf("Flight (%s)", passengers)
+		if heads > 1 && len(cost.FlightByPassenger) > 0 {
+			row(flightLabel, fmt.Sprintf("%s = %s", FareBreakdownString(cost.FlightByPassenger), cost.Flight))
+		} else if heads > 1 {
+			row(flightLabel, fmt.Sprintf("%s · %s per traveler", cost.Flight, cost.FlightPerPerson))
+		} else {
+			row(flightLabel, cost.Flight.String())
+		}
+		row(fmt.Sprintf("Hotel (%d nights)", data.NumNights), fmt.Sprintf("%s · %s per night, one room", cost.Hotel, cost.HotelPerNight))
+
+		pdf.SetFillColor(212, 168, 67)
+		pdf.SetTextColor(13, 24, 37)
+		pdf.SetFont(pdfFont, "B", 12)
+		pdf.CellFormat(55, 9, "TOTAL ESTIMATE", "", 0, "L", true, 0, "")
+		pdf.CellFormat(115, 9, data.TotalCost.String(), "", 1, "L", true, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		if heads > 1 {
+			row("Per traveler", fmt.Sprintf("%s (average over %d travelers)", data.TotalCost.Div(heads), heads))
+		}
+		pdf.SetFont(pdfFont, "I", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.MultiCell(170, 4, "The flight price already covers every passenger; the hotel is priced per room per night.", "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.Ln(4)
+	}
+
+	activities := data.Activities
+	if len(activities) == 0 {
+		activities = FallbackActivities(data.Destination)
+	}
+
+	// ── Day by Day ────────────────────────────────────────────
+	var days []ScheduleDay
+	if data.DayByDay {
+		days = DaySchedule(data, activities)
+	}
+	if len(days) > 0 {
+		sectionHeader("Day by Day")
+		for i, day := range days {
+			row(fmtDateReadable(day.Date), fmt.Sprintf("Day %d · %s", i+1, day.Title))
+			pdf.SetFont(pdfFont, "", 9.5)
+			pdf.SetTextColor(70, 70, 70)
+			for _, item := range day.Items {
+				pdf.SetX(75)
+				pdf.MultiCell(115, 5, "· "+item, "", "L", false)
+			}
+			pdf.Ln(1.5)
+		}
+		pdf.Ln(3)
+	}
+
+	// ── AI Summary ────────────────────────────────────────────
+	if include[SectionAI] && data.AISummary != "" {
+		sectionHeader("AI Recommendations")
+		writeSummaryBlocks(pdf, data.AISummary)
+		pdf.Ln(4)
+	}
+
+	// ── Things to Do ──────────────────────────────────────────
+	if include[SectionActivities] && len(activities) > 0 {
+		sectionHeader("Things to Do in " + data.Destination)
+		for _, a := range activities {
+			price := ""
+			if !a.Price.IsZero() {
+				price = "from " + a.Price.String()
+			}
+			pdf.SetFont(pdfFont, "B", 10)
+			pdf.SetTextColor(20, 20, 20)
+			pdf.CellFormat(130, 6, a.Name, "", 0, "L", false, 0, a.BookingLink)
+			pdf.SetFont(pdfFont, "", 10)
+			pdf.SetTextColor(100, 100, 100)
+			pdf.CellFormat(40, 6, price, "", 1, "R", false, 0, "")
+			if a.Description != "" {
+				pdf.SetFont(pdfFont, "", 9)
+				pdf.SetTextColor(70, 70, 70)
+				pdf.MultiCell(170, 4.5, a.Description, "", "L", false)
+				pdf.Ln(1)
+			}
+		}
+		pdf.Ln(4)
+	}
+
+}
+
+// drawPageHeader draws the navy title bar with subtitle and the not-a-booking disclaimer at
+// the top of the current page, leaving the cursor below them.
+func drawPageHeader(pdf *gofpdf.Fpdf, subtitle string, estimated bool) {
 	// ── Header Bar ───────────────────────────────────────────
 	pdf.SetFillColor(13, 24, 37) // --navy-950
 	pdf.Rect(0, 0, 210, 28, "F")
 	pdf.SetTextColor(255, 255, 255)
-	pdf.SetFont("Helvetica", "B", 18)
+	pdf.SetFont(pdfFont, "B", 18)
 	pdf.SetXY(20, 8)
 	pdf.CellFormat(100, 10, "TripMind", "", 0, "L", false, 0, "")
-	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetFont(pdfFont, "", 10)
 	pdf.SetTextColor(212, 168, 67) // gold
 	pdf.SetXY(20, 18)
-	pdf.CellFormat(170, 6, "AI-Powered Travel Itinerary", "", 1, "L", false, 0, "")
+	pdf.CellFormat(170, 6, subtitle, "", 1, "L", false, 0, "")
 
 	pdf.SetY(35)
 	pdf.SetTextColor(0, 0, 0)
@@ -58,14 +439,14 @@ func GeneratePDFBytes(data PDFData) ([]byte, error) {
 	pdf.SetFillColor(255, 248, 225)
 	pdf.SetDrawColor(212, 168, 67)
 	pdf.SetTextColor(130, 90, 20)
-	pdf.SetFont("Helvetica", "I", 8)
+	pdf.SetFont(pdfFont, "I", 8)
 	pdf.SetLineWidth(0.4)
 	y := pdf.GetY()
 	pdf.Rect(20, y, 170, 12, "FD")
 	pdf.SetXY(23, y+2)
 	disclaimer := "⚠ This is NOT a booking confirmation. Prices are estimates and subject to change. Please verify with providers before booking."
-	if data.IsEstimated {
-		disclaimer = "⚠ ESTIMATED PRICES — Amadeus API not configured. This is NOT a booking confirmation. Verify all prices before booking."
+	if estimated {
+		disclaimer = "⚠ ESTIMATED PRICES — live data was unavailable. This is NOT a booking confirmation. Verify all prices before booking."
 	}
 	pdf.MultiCell(164, 4, disclaimer, "", "C", false)
 
@@ -73,130 +454,94 @@ func GeneratePDFBytes(data PDFData) ([]byte, error) {
 	pdf.SetDrawColor(0, 0, 0)
 	pdf.SetLineWidth(0.2)
 	pdf.Ln(6)
+}
 
-	// ── Section Helper ───────────────────────────────────────
-	sectionHeader := func(title string) {
-		pdf.SetFillColor(13, 24, 37)
-		pdf.SetTextColor(255, 255, 255)
-		pdf.SetFont("Helvetica", "B", 11)
-		pdf.CellFormat(170, 8, "  "+title, "", 1, "L", true, 0, "")
-		pdf.SetTextColor(0, 0, 0)
-		pdf.Ln(2)
+// outputPDF writes the document to a buffer (no filesystem needed).
+func outputPDF(pdf *gofpdf.Fpdf) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("PDF output failed: %w", err)
 	}
+	return buf.Bytes(), nil
+}
 
-	row := func(label, value string) {
-		pdf.SetFont("Helvetica", "", 10)
-		pdf.SetTextColor(100, 100, 100)
-		pdf.CellFormat(55, 7, label, "", 0, "L", false, 0, "")
-		pdf.SetTextColor(20, 20, 20)
-		pdf.SetFont("Helvetica", "B", 10)
-		pdf.CellFormat(115, 7, value, "", 1, "L", false, 0, "")
-	}
+// drawCoverPage fills the current page with a navy title page: destination, dates and traveler.
+func drawCoverPage(pdf *gofpdf.Fpdf, data PDFData) {
+	pdf.SetFillColor(13, 24, 37) // --navy-950
+	pdf.Rect(0, 0, 210, 297, "F")
+
+	pdf.SetTextColor(212, 168, 67) // gold
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.SetXY(20, 40)
+	pdf.CellFormat(170, 8, "TripMind", "", 1, "L", false, 0, "")
+
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont(pdfFont, "B", 40)
+	pdf.SetXY(20, 110)
+	pdf.CellFormat(170, 18, "Trip to "+data.Destination, "", 1, "L", false, 0, "")
+
+	pdf.SetFont(pdfFont, "", 16)
+	pdf.SetX(20)
+	pdf.CellFormat(170, 10, fmtDateReadable(data.DepartureDate)+" - "+fmtDateReadable(data.ReturnDate), "", 1, "L", false, 0, "")
+
+	pdf.SetDrawColor(212, 168, 67)
+	pdf.SetLineWidth(0.8)
+	pdf.Line(20, 150, 80, 150)
 
-	// ── Traveler Info ─────────────────────────────────────────
-	sectionHeader("Traveler Information")
 	name := data.TravelerName
 	if name == "" {
 		name = "Guest Traveler"
 	}
-	row("Name", name)
-	row("Generated", time.Now().Format("02 Jan 2006, 15:04 UTC"))
-	pdf.Ln(4)
+	pdf.SetTextColor(200, 200, 200)
+	pdf.SetFont(pdfFont, "", 12)
+	pdf.SetXY(20, 158)
+	pdf.CellFormat(170, 8, "Prepared for "+name, "", 1, "L", false, 0, "")
 
-	// ── Trip Overview ─────────────────────────────────────────
-	sectionHeader("Trip Overview")
-	returnOriginLabel := data.Destination
-	if data.ReturnOrigin != "" && data.ReturnOrigin != data.Destination {
-		returnOriginLabel = data.ReturnOrigin
-		row("Route", fmt.Sprintf("%s → %s (outbound) · %s → %s (return)", data.Origin, data.Destination, returnOriginLabel, data.Origin))
-		row("Trip Type", "Multi-City")
-	} else {
-		row("Route", fmt.Sprintf("%s → %s → %s", data.Origin, data.Destination, data.Origin))
-	}
-	row("Departure", fmtDateReadable(data.DepartureDate))
-	row("Return", fmtDateReadable(data.ReturnDate))
-	row("Duration", fmt.Sprintf("%d nights", data.NumNights))
-	passengers := data.Passengers
-	if passengers <= 0 {
-		passengers = 1
-	}
-	row("Passengers", fmt.Sprintf("%d", passengers))
-	pdf.Ln(4)
+	pdf.SetFont(pdfFont, "I", 9)
+	pdf.SetXY(20, 270)
+	pdf.CellFormat(170, 6, "AI-Powered Travel Itinerary · Not a booking confirmation", "", 1, "L", false, 0, "")
 
-	// ── Selected Flight ───────────────────────────────────────
-	sectionHeader("Selected Flight")
-	row("Airline", data.Flight.Airline)
-	row("Outbound", formatFlightLeg(data.Flight.DepartureTime, data.Flight.ArrivalTime, data.Flight.Duration))
-	row("Return", formatFlightLeg(data.Flight.ReturnDepartureTime, data.Flight.ReturnArrivalTime, data.Flight.ReturnDuration))
-	stops := "Direct"
-	if data.Flight.Stops > 0 {
-		stops = fmt.Sprintf("%d stop(s)", data.Flight.Stops)
-	}
-	row("Stops", stops)
-	row("Price", fmt.Sprintf("$%.0f per person (round-trip)", data.Flight.Price))
-	pdf.Ln(4)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.2)
+}
 
-	// ── Selected Hotel ────────────────────────────────────────
-	sectionHeader("Selected Hotel")
-	row("Hotel", data.Hotel.Name)
-	row("Location", data.Hotel.Location)
-	row("Rating", fmt.Sprintf("%.1f / 5.0", data.Hotel.Rating))
-	row("Check-in", fmtDateReadable(data.DepartureDate))
-	row("Check-out", fmtDateReadable(data.ReturnDate))
-	row("Price", fmt.Sprintf("$%.0f/night × %d nights = $%.0f",
-		data.Hotel.Price, data.NumNights, data.Hotel.Price*float64(data.NumNights)))
-	pdf.Ln(4)
+// qrSize is the printed width of booking QR codes in mm, large enough to scan from paper.
+const qrSize = 28.0
 
-	// ── Cost Summary ──────────────────────────────────────────
-	sectionHeader("Cost Estimate")
-	row("Flight (per person)", fmt.Sprintf("$%.0f", data.Flight.Price))
-	row(fmt.Sprintf("Flight × %d passengers", passengers), fmt.Sprintf("$%.0f", data.Flight.Price*float64(passengers)))
-	row("Hotel total", fmt.Sprintf("$%.0f", data.Hotel.Price*float64(data.NumNights)))
-
-	pdf.SetFillColor(212, 168, 67)
-	pdf.SetTextColor(13, 24, 37)
-	pdf.SetFont("Helvetica", "B", 12)
-	pdf.CellFormat(55, 9, "TOTAL ESTIMATE", "", 0, "L", true, 0, "")
-	pdf.CellFormat(115, 9, fmt.Sprintf("$%.0f", data.TotalCost), "", 1, "L", true, 0, "")
-	pdf.SetTextColor(0, 0, 0)
-	pdf.Ln(4)
+// qrPNG encodes link as a QR code image; an empty link has none.
+func qrPNG(link string) ([]byte, error) {
+	if link == "" {
+		return nil, fmt.Errorf("no link")
+	}
+	return qrcode.Encode(link, qrcode.Medium, 256)
+}
 
-	// ── AI Summary ────────────────────────────────────────────
-	if data.AISummary != "" {
-		sectionHeader("AI Recommendations")
-		pdf.SetFont("Helvetica", "", 10)
-		pdf.SetTextColor(40, 40, 40)
-		pdf.MultiCell(170, 5, data.AISummary, "", "L", false)
-		pdf.Ln(4)
+// cabinLabel turns "PREMIUM_ECONOMY" into "Premium Economy".
+func cabinLabel(cabin string) string {
+	words := strings.Split(strings.ToLower(cabin), "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
 	}
+	return strings.Join(words, " ")
+}
 
-	// ── Destination Highlights ────────────────────────────────
-	highlights := DestinationHighlights(data.Destination)
-	if highlights != "" {
-		sectionHeader("Things to Do in " + data.Destination)
-		pdf.SetFont("Helvetica", "", 10)
-		pdf.SetTextColor(40, 40, 40)
-		pdf.MultiCell(170, 6, highlights, "", "L", false)
-		pdf.Ln(4)
+// formatLayovers lists connections as "IST 2h 15m, DOH 55m".
+func formatLayovers(layovers []Layover) string {
+	parts := make([]string, len(layovers))
+	for i, l := range layovers {
+		parts[i] = l.Airport + " " + l.Duration
 	}
+	return strings.Join(parts, ", ")
+}
 
-	// ── Footer ────────────────────────────────────────────────
-	pdf.SetY(-22)
-	pdf.SetDrawColor(200, 200, 200)
-	pdf.SetLineWidth(0.3)
-	pdf.Line(20, pdf.GetY(), 190, pdf.GetY())
-	pdf.SetFont("Helvetica", "I", 8)
-	pdf.SetTextColor(150, 150, 150)
-	pdf.CellFormat(0, 8,
-		"Generated by TripMind AI Travel Planner · Not a booking confirmation · Prices subject to change",
-		"", 0, "C", false, 0, "")
-
-	// ── Write to buffer ───────────────────────────────────────
-	var buf bytes.Buffer
-	if err := pdf.Output(&buf); err != nil {
-		return nil, fmt.Errorf("PDF output failed: %w", err)
+func estimatedLabel(estimated bool) string {
+	if estimated {
+		return " (estimated)"
 	}
-	return buf.Bytes(), nil
+	return ""
 }
 
 func fmtDateReadable(iso string) string {
@@ -207,20 +552,27 @@ func fmtDateReadable(iso string) string {
 	return t.Format("02 Jan 2006 (Mon)")
 }
 
-func formatFlightLeg(dep, arr, dur string) string {
-	depT, err1 := time.Parse(time.RFC3339, dep)
-	arrT, err2 := time.Parse(time.RFC3339, arr)
-	if err1 != nil || err2 != nil {
-		if dep != "" && arr != "" {
-			return dep + " → " + arr
-		}
+// formatFlightLeg renders e.g. "10 Jun 14:20 local (09:20 UTC) → 10 Jun 17:15 local (14:15 UTC) (4h 55m)".
+// The UTC part is omitted when it isn't known.
+func formatFlightLeg(dep, depUTC, arr, arrUTC, dur string) string {
+	if dep == "" || arr == "" {
 		return "N/A"
 	}
-	result := fmt.Sprintf("%s → %s",
-		depT.Format("02 Jan 15:04"),
-		arrT.Format("02 Jan 15:04"))
+	result := fmt.Sprintf("%s → %s", formatLegTime(dep, depUTC), formatLegTime(arr, arrUTC))
 	if dur != "" {
 		result += fmt.Sprintf(" (%s)", dur)
 	}
 	return result
-}
\ No newline at end of file
+}
+
+func formatLegTime(raw, utc string) string {
+	t, _, ok := parseSegmentTime(raw)
+	if !ok {
+		return raw
+	}
+	result := t.Format("02 Jan 15:04")
+	if u, err := time.Parse(time.RFC3339, utc); err == nil {
+		result += fmt.Sprintf(" local (%s UTC)", u.Format("15:04"))
+	}
+	return result
+}
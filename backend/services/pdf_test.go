@@ -0,0 +1,50 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestGeneratePDFBytesNonLatinNames renders names outside Latin-1. A PDF set in a core font
+// (cp1252) would still come out, just with mangled names, so the test also checks that the
+// text is set in the embedded UTF-8 font and no core font is used.
+func TestGeneratePDFBytesNonLatinNames(t *testing.T) {
+	for _, tt := range []struct{ traveler, hotel, location string }{
+		{"Şükrü Ağaoğlu", "Pera Palace Hotel Beyoğlu", "Meşrutiyet Cd., Beyoğlu, İstanbul"},
+		{"Анна Сергеевна Кузнецова", "Гостиница «Узбекистан»", "ул. Мирабад, Ташкент"},
+		{"Zoë Brønnum-Dvořák", "Hôtel Römerhof Zürich", "Römerstraße 7 · Zürich"},
+	} {
+		t.Run(tt.traveler, func(t *testing.T) {
+			data := PDFData{
+				TravelerName:  tt.traveler,
+				Origin:        "TAS",
+				Destination:   "IST",
+				DepartureDate: "2026-11-10",
+				ReturnDate:    "2026-11-17",
+				Flight:        GenerateFlightsFallback("TAS", "IST", "2026-11-10", "2026-11-17", "ECONOMY", false, PassengerMix{Adults: 1})[0],
+				Hotel:         Hotel{Name: tt.hotel, Location: tt.location, Price: usd(120), Rating: 4.5, Currency: "USD"},
+				NumNights:     7,
+				Passengers:    PassengerMix{Adults: 1},
+				TotalCost:     usd(1150),
+				AISummary:     "✈ Flight: nonstop → " + tt.hotel,
+				CoverPage:     true,
+				DayByDay:      true,
+			}
+			out, err := GeneratePDFBytes(data)
+			if err != nil {
+				t.Fatalf("GeneratePDFBytes: %v", err)
+			}
+			if !bytes.HasPrefix(out, []byte("%PDF")) {
+				t.Fatalf("output starts with %q, want %%PDF", out[:min(len(out), 8)])
+			}
+			if !bytes.Contains(out, []byte("/Subtype /Type0")) {
+				t.Error("no embedded UTF-8 (Type0) font in the PDF")
+			}
+			for _, coreFont := range []string{"/BaseFont /Helvetica", "/BaseFont /Arial", "/Encoding /WinAnsiEncoding"} {
+				if bytes.Contains(out, []byte(coreFont)) {
+					t.Errorf("PDF uses a core cp1252 font (%s)", coreFont)
+				}
+			}
+		})
+	}
+}
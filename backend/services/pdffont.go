@@ -0,0 +1,30 @@
+package services
+
+import (
+	_ "embed"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ─── PDF Font ─────────────────────────────────────────────────────────────────
+
+// pdfFont is the family every PDF is set in: DejaVu Sans Condensed, embedded so names like
+// "Beyoğlu" or "Römer" and the → · — separators print as written. The core Helvetica only
+// covers Latin-1 and needs no files, but mangles anything else.
+const pdfFont = "DejaVu"
+
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	fontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	fontBold []byte
+	//go:embed fonts/DejaVuSansCondensed-Oblique.ttf
+	fontOblique []byte
+)
+
+// addPDFFonts registers pdfFont's regular, bold and italic styles with pdf.
+func addPDFFonts(pdf *gofpdf.Fpdf) {
+	pdf.AddUTF8FontFromBytes(pdfFont, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", fontBold)
+	pdf.AddUTF8FontFromBytes(pdfFont, "I", fontOblique)
+}
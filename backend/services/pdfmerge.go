@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+)
+
+// ─── Merged PDF ───────────────────────────────────────────────────────────────
+// The comparison download puts the PDFs already generated for a search into one document.
+// Their pages are copied as stored rather than rendered again, so every option shows the
+// sections, cover page and prices it was generated with.
+
+// MaxMergedVariants caps how many itineraries GenerateMergedPDF puts in one document.
+const MaxMergedVariants = 10
+
+// MergedVariant is one generated itinerary in a merged document. Summary is the selection it
+// shows, for the separator page; comparisons and itineraries generated before selections
+// were stored have none.
+type MergedVariant struct {
+	ID           string
+	PDF          []byte
+	TravelerName string
+	CreatedAt    time.Time
+	Summary      *PDFData
+}
+
+// GenerateMergedPDF copies the stored PDFs of several itineraries of a trip to destination
+// into a single document, each introduced by an "Option N of M" separator page, for
+// side-by-side comparison. A stored PDF that can't be read is left out.
+func GenerateMergedPDF(destination string, variants []MergedVariant) ([]byte, error) {
+	if len(variants) > MaxMergedVariants {
+		variants = variants[:MaxMergedVariants]
+	}
+
+	// Copied pages carry their own watermark and footer, and separators get none.
+	pdf := newItineraryPDF(func(int) bool { return true })
+	importer := gofpdi.NewImporter()
+	type importedVariant struct {
+		MergedVariant
+		pages []importedPage
+	}
+	var imported []importedVariant
+	for _, v := range variants {
+		pages, err := importPDFPages(pdf, importer, v.PDF)
+		if err != nil {
+			log.Printf("⚠️  Skipping itinerary %s in merged PDF: %v", v.ID, err)
+			continue
+		}
+		imported = append(imported, importedVariant{v, pages})
+	}
+	if len(imported) == 0 {
+		return nil, fmt.Errorf("no itineraries to merge")
+	}
+
+	for i, v := range imported {
+		pdf.AddPage()
+		drawVariantSeparator(pdf, i+1, len(imported), destination, v.MergedVariant)
+		for _, page := range v.pages {
+			orientation := "P"
+			if page.w > page.h {
+				orientation = "L"
+			}
+			pdf.AddPageFormat(orientation, gofpdf.SizeType{Wd: page.w, Ht: page.h})
+			importer.UseImportedTemplate(pdf, page.tpl, 0, 0, page.w, page.h)
+		}
+	}
+	return outputPDF(pdf)
+}
+
+// importedPage is a page of a stored PDF registered as a template, with its size in mm.
+type importedPage struct {
+	tpl  int
+	w, h float64
+}
+
+// importPDFPages registers every page of doc with pdf. gofpdi panics on a PDF it can't
+// parse; that comes back as an error.
+func importPDFPages(pdf *gofpdf.Fpdf, importer *gofpdi.Importer, doc []byte) (pages []importedPage, err error) {
+	if len(doc) == 0 {
+		return nil, fmt.Errorf("no PDF")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unreadable PDF: %v", r)
+		}
+	}()
+
+	// The importer tells sources apart by the address of their ReadSeeker.
+	rs := io.ReadSeeker(bytes.NewReader(doc))
+	first := importer.ImportPageFromStream(pdf, &rs, 1, "/MediaBox")
+	sizes := importer.GetPageSizes()
+	const mmPerPt = 25.4 / 72
+	for n := 1; n <= len(sizes); n++ {
+		tpl := first
+		if n > 1 {
+			tpl = importer.ImportPageFromStream(pdf, &rs, n, "/MediaBox")
+		}
+		box := sizes[n]["/MediaBox"]
+		pages = append(pages, importedPage{tpl: tpl, w: box["w"] * mmPerPt, h: box["h"] * mmPerPt})
+	}
+	return pages, pdf.Error()
+}
+
+// drawVariantSeparator fills the current page with a navy divider naming the option and its
+// flight, hotel and total (or, without a known selection, its traveler and date), so merged
+// variants can be told apart at a glance.
+func drawVariantSeparator(pdf *gofpdf.Fpdf, n, total int, destination string, v MergedVariant) {
+	pdf.SetFillColor(13, 24, 37) // --navy-950
+	pdf.Rect(0, 0, 210, 297, "F")
+
+	pdf.SetTextColor(212, 168, 67) // gold
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.SetXY(20, 40)
+	pdf.CellFormat(170, 8, fmt.Sprintf("TripMind · Trip to %s", destination), "", 1, "L", false, 0, "")
+
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont(pdfFont, "B", 40)
+	pdf.SetXY(20, 110)
+	pdf.CellFormat(170, 18, fmt.Sprintf("Option %d of %d", n, total), "", 1, "L", false, 0, "")
+
+	pdf.SetDrawColor(212, 168, 67)
+	pdf.SetLineWidth(0.8)
+	pdf.Line(20, 134, 80, 134)
+
+	pdf.SetTextColor(200, 200, 200)
+	pdf.SetFont(pdfFont, "", 13)
+	pdf.SetXY(20, 142)
+	if data := v.Summary; data != nil {
+		pdf.CellFormat(170, 8, "Flight: "+data.Flight.Airline+" · "+data.Flight.Price.String()+" for "+data.Passengers.String(), "", 1, "L", false, 0, "")
+		pdf.SetX(20)
+		pdf.CellFormat(170, 8, "Hotel: "+data.Hotel.Name+" · "+data.Hotel.Price.String()+"/night", "", 1, "L", false, 0, "")
+		pdf.SetX(20)
+		pdf.SetFont(pdfFont, "B", 13)
+		pdf.SetTextColor(212, 168, 67)
+		pdf.CellFormat(170, 8, "Total estimate: "+data.TotalCost.String(), "", 1, "L", false, 0, "")
+	} else {
+		if v.TravelerName != "" {
+			pdf.CellFormat(170, 8, "Traveler: "+v.TravelerName, "", 1, "L", false, 0, "")
+			pdf.SetX(20)
+		}
+		pdf.CellFormat(170, 8, "Generated "+v.CreatedAt.UTC().Format("02 Jan 2006, 15:04 UTC"), "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetLineWidth(0.2)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ─── PDF AI Summary ───────────────────────────────────────────────────────────
+
+// summaryBlock is one titled part of the AI summary as the PDF lays it out. Text before
+// the first header (e.g. the estimate notice) has no title.
+type summaryBlock struct {
+	Title string
+	Body  string
+}
+
+// pdfSummaryHeader matches the same headers as aiSectionHeader, plus the fallback
+// summary's "🗺 What to see in IST:" line.
+var pdfSummaryHeader = regexp.MustCompile(`(?im)^[\s#>\-]*(\*\*)?\s*(?:[^\p{L}\p{N}\s:*]+\s*)?(flight|hotel|budget summary|budget|highlights|what to see in [^:\n]{1,40})\s*(?:\*\*)?\s*:[ \t]*`)
+
+// splitSummaryBlocks splits summary on its section headers. A summary without any comes
+// back as a single untitled block.
+func splitSummaryBlocks(summary string) []summaryBlock {
+	matches := pdfSummaryHeader.FindAllStringSubmatchIndex(summary, -1)
+	if len(matches) == 0 {
+		return []summaryBlock{{Body: strings.TrimSpace(summary)}}
+	}
+
+	var blocks []summaryBlock
+	if lead := strings.TrimSpace(summary[:matches[0][0]]); lead != "" {
+		blocks = append(blocks, summaryBlock{Body: lead})
+	}
+	for i, m := range matches {
+		end := len(summary)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := strings.TrimSpace(summary[m[1]:end])
+		if m[2] >= 0 {
+			body = strings.TrimSpace(strings.TrimPrefix(body, "**"))
+		}
+		name := summary[m[4]:m[5]]
+		blocks = append(blocks, summaryBlock{Title: strings.ToUpper(name[:1]) + name[1:], Body: body})
+	}
+	return blocks
+}
+
+// pdfSymbols are symbols with a plain-text stand-in; other emoji are dropped.
+var pdfSymbols = strings.NewReplacer("✓", "-", "✔", "-", "•", "-")
+
+var spaceBeforePunct = regexp.MustCompile(` ([.,;:!?])`)
+
+// stripEmoji removes the emoji the PDF fonts can't draw, ratings' ★ included, and tidies
+// the spaces they leave behind.
+func stripEmoji(s string) string {
+	s = pdfSymbols.Replace(s)
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u200d' || r == '\ufe0e' || r == '\ufe0f': // joiner, variation selectors
+			return -1
+		case r >= 0x1f3fb && r <= 0x1f3ff: // skin tone modifiers
+			return -1
+		case r >= 0x2000 && unicode.Is(unicode.So, r):
+			return -1
+		}
+		return r
+	}, s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = spaceBeforePunct.ReplaceAllString(strings.Join(strings.Fields(line), " "), "$1")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeSummaryBlocks draws the AI summary: a bold subheading per section, then its text,
+// with **bold** spans kept and list items indented.
+func writeSummaryBlocks(pdf *gofpdf.Fpdf, summary string) {
+	for i, block := range splitSummaryBlocks(stripEmoji(summary)) {
+		if i > 0 {
+			pdf.Ln(2)
+		}
+		if block.Title != "" {
+			pdf.SetFont(pdfFont, "B", 10.5)
+			pdf.SetTextColor(13, 24, 37)
+			pdf.CellFormat(170, 6, block.Title, "", 1, "L", false, 0, "")
+		}
+		for _, line := range strings.Split(block.Body, "\n") {
+			if line == "" {
+				continue
+			}
+			if rest, ok := cutListMarker(line); ok {
+				line = "  - " + rest
+			}
+			writeBoldSpans(pdf, line)
+			pdf.Ln(5)
+		}
+	}
+}
+
+// cutListMarker strips a markdown bullet ("- ", "* ") from line.
+func cutListMarker(line string) (string, bool) {
+	for _, marker := range []string{"- ", "* "} {
+		if rest, ok := strings.CutPrefix(line, marker); ok {
+			return rest, true
+		}
+	}
+	return line, false
+}
+
+// writeBoldSpans writes one wrapped paragraph, switching to bold between ** pairs.
+func writeBoldSpans(pdf *gofpdf.Fpdf, line string) {
+	pdf.SetTextColor(40, 40, 40)
+	for i, span := range strings.Split(line, "**") {
+		if span == "" {
+			continue
+		}
+		style := ""
+		if i%2 == 1 {
+			style = "B"
+		}
+		pdf.SetFont(pdfFont, style, 10)
+		pdf.Write(5, span)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// ─── Day-by-Day Schedule ──────────────────────────────────────────────────────
+
+// ScheduleDay is one dated day of the trip and what happens on it.
+type ScheduleDay struct {
+	Date  string // YYYY-MM-DD
+	Title string
+	Items []string
+}
+
+// DaySchedule breaks the trip into its NumNights+1 days: the arrival day, each full day
+// with one of activities suggested (a free day once they run out) or a multi-leg trip's
+// flight onwards, and the departure day. It is empty when the dates don't parse.
+func DaySchedule(data PDFData, activities []Activity) []ScheduleDay {
+	start, err := time.Parse("2006-01-02", data.DepartureDate)
+	if err != nil || data.NumNights <= 0 {
+		return nil
+	}
+	f, h := data.Flight, data.Hotel
+
+	arrival := ScheduleDay{Title: "Arrival in " + data.Destination}
+	if len(f.Legs) > 0 {
+		arrival.Title = "Arrival in " + f.Legs[0].Destination
+		leg := f.Legs[0]
+		arrival.Items = append(arrival.Items, fmt.Sprintf("Fly %s → %s, %s", leg.Origin, leg.Destination,
+			formatFlightLeg(leg.DepartureTime, "", leg.ArrivalTime, "", leg.Duration)))
+	} else if f.DepartureTime != "" {
+		arrival.Items = append(arrival.Items, fmt.Sprintf("Fly %s → %s with %s, %s", data.Origin, data.Destination, f.Airline,
+			formatFlightLeg(f.DepartureTime, "", f.ArrivalTime, "", f.Duration)))
+	}
+	if h.Name != "" {
+		arrival.Items = append(arrival.Items, "Check in at "+h.Name)
+	}
+	arrival.Date = start.Format("2006-01-02")
+	days := []ScheduleDay{arrival}
+
+	// Multi-leg trips move on between cities; activities are only suggested where the hotel is.
+	city := data.Destination
+	if len(f.Legs) > 0 {
+		city = f.Legs[0].Destination
+	}
+	next := 0
+	for i := 1; i < data.NumNights; i++ {
+		day := ScheduleDay{Date: start.AddDate(0, 0, i).Format("2006-01-02")}
+		if leg, ok := middleLegOn(f, day.Date); ok {
+			day.Title = "Travel to " + leg.Destination
+			day.Items = []string{fmt.Sprintf("Fly %s → %s, %s", leg.Origin, leg.Destination,
+				formatFlightLeg(leg.DepartureTime, "", leg.ArrivalTime, "", leg.Duration))}
+			city = leg.Destination
+			days = append(days, day)
+			continue
+		}
+		day.Title = "Full day in " + city
+		if city == data.Destination && next < len(activities) {
+			a := activities[next]
+			next++
+			item := "Suggested: " + a.Name
+			if !a.Price.IsZero() {
+				item += " (from " + a.Price.String() + ")"
+			}
+			day.Items = append(day.Items, item)
+		} else {
+			day.Items = append(day.Items, "Free day to explore at your own pace")
+		}
+		days = append(days, day)
+	}
+
+	departure := ScheduleDay{Title: "Departure"}
+	if h.Name != "" {
+		departure.Items = append(departure.Items, "Check out of "+h.Name)
+	}
+	from := data.Destination
+	if data.ReturnOrigin != "" {
+		from = data.ReturnOrigin
+	}
+	if len(f.Legs) > 0 {
+		leg := f.Legs[len(f.Legs)-1]
+		departure.Items = append(departure.Items, fmt.Sprintf("Fly %s → %s, %s", leg.Origin, leg.Destination,
+			formatFlightLeg(leg.DepartureTime, "", leg.ArrivalTime, "", leg.Duration)))
+	} else if f.ReturnDepartureTime != "" {
+		departure.Items = append(departure.Items, fmt.Sprintf("Fly %s → %s, %s", from, data.Origin,
+			formatFlightLeg(f.ReturnDepartureTime, "", f.ReturnArrivalTime, "", f.ReturnDuration)))
+	}
+	departure.Date = start.AddDate(0, 0, data.NumNights).Format("2006-01-02")
+	return append(days, departure)
+}
+
+// middleLegOn finds the leg of a multi-leg itinerary, other than the first and last, that
+// departs on date.
+func middleLegOn(f Flight, date string) (FlightLeg, bool) {
+	for i := 1; i < len(f.Legs)-1; i++ {
+		if dateOf(f.Legs[i].DepartureTime) == date {
+			return f.Legs[i], true
+		}
+	}
+	return FlightLeg{}, false
+}
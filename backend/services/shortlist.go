@@ -0,0 +1,100 @@
+package services
+
+import "time"
+
+// ─── Budget Shortlist ─────────────────────────────────────────────────────────
+// The AI prompt only has room for a few offers, so instead of the first ones it gets those
+// that can still make a trip within budget, each with the cheapest total it can be part of.
+
+// promptShortlistSize is how many flights and how many hotels the prompt lists.
+const promptShortlistSize = 5
+
+// shortlisted is one offer in the prompt: fromTotal is the cheapest trip it can be part of
+// (with the cheapest offer of the other kind), and overBudget flags that even that is too
+// much. priced is false when the total couldn't be worked out.
+type shortlisted[T any] struct {
+	offer      T
+	fromTotal  Money
+	priced     bool
+	overBudget bool
+}
+
+// budgetShortlist keeps up to promptShortlistSize flights and hotels whose cheapest trip
+// fits the budget, in their original order. When none of one kind fit, the one with the
+// cheapest trip is kept, flagged over budget, so the model always has something to
+// recommend. Without nights (unparseable dates) the first offers are kept unannotated.
+func budgetShortlist(budget Money, nights int, flights []Flight, hotels []Hotel) ([]shortlisted[Flight], []shortlisted[Hotel]) {
+	if len(flights) == 0 || len(hotels) == 0 || nights <= 0 {
+		return firstOffers(flights), firstOffers(hotels)
+	}
+	cheapestFlight, cheapestHotel := flights[0].Price, hotels[0].Price
+	for _, f := range flights {
+		if f.Price.Less(cheapestFlight) {
+			cheapestFlight = f.Price
+		}
+	}
+	for _, h := range hotels {
+		if h.Price.Less(cheapestHotel) {
+			cheapestHotel = h.Price
+		}
+	}
+
+	fs := make([]shortlisted[Flight], len(flights))
+	for i, f := range flights {
+		fs[i] = priceShortlisted(f, budget, f.Price, cheapestHotel.Mul(nights))
+	}
+	hs := make([]shortlisted[Hotel], len(hotels))
+	for i, h := range hotels {
+		hs[i] = priceShortlisted(h, budget, cheapestFlight, h.Price.Mul(nights))
+	}
+	return withinBudget(fs), withinBudget(hs)
+}
+
+// priceShortlisted totals flight and hotel prices when both are in the budget's currency.
+func priceShortlisted[T any](offer T, budget, flight, hotel Money) shortlisted[T] {
+	s := shortlisted[T]{offer: offer}
+	if SameCurrency(flight.Currency, hotel.Currency) && SameCurrency(flight.Currency, budget.Currency) {
+		s.fromTotal, s.priced = flight.Add(hotel), true
+		s.overBudget = budget.Less(s.fromTotal)
+	}
+	return s
+}
+
+// withinBudget keeps the offers that fit (or couldn't be priced), falling back to the one
+// with the cheapest trip.
+func withinBudget[T any](all []shortlisted[T]) []shortlisted[T] {
+	var kept []shortlisted[T]
+	for _, s := range all {
+		if !s.overBudget && len(kept) < promptShortlistSize {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) > 0 {
+		return kept
+	}
+	cheapest := all[0]
+	for _, s := range all {
+		if s.fromTotal.Less(cheapest.fromTotal) {
+			cheapest = s
+		}
+	}
+	return []shortlisted[T]{cheapest}
+}
+
+func firstOffers[T any](offers []T) []shortlisted[T] {
+	kept := make([]shortlisted[T], 0, min(len(offers), promptShortlistSize))
+	for _, o := range offers[:min(len(offers), promptShortlistSize)] {
+		kept = append(kept, shortlisted[T]{offer: o})
+	}
+	return kept
+}
+
+// tripNights is the number of nights between two YYYY-MM-DD dates, or 0 when they don't parse.
+func tripNights(departureDate, returnDate string) int {
+	dep, err1 := time.Parse("2006-01-02", departureDate)
+	ret, err2 := time.Parse("2006-01-02", returnDate)
+	if err1 != nil || err2 != nil || !ret.After(dep) {
+		return 0
+	}
+	return int(ret.Sub(dep).Hours() / 24)
+}
@@ -0,0 +1,123 @@
+package services
+
+import "fmt"
+
+// ─── Trip Summary ─────────────────────────────────────────────────────────────
+
+// TripSummary is the deterministic, structured counterpart of the AI summary: it is
+// computed from the offers alone so the client has highlight data even when the model fails.
+type TripSummary struct {
+	CheapestFlight TripPick  `json:"cheapest_flight"`
+	BestRatedHotel TripPick  `json:"best_rated_hotel"`
+	BestValueCombo TripCombo `json:"best_value_combo"`
+	WithinBudget   bool      `json:"within_budget"`
+}
+
+// TripPick points at one entry of the response's flights or hotels list.
+type TripPick struct {
+	Index int   `json:"index"`
+	Price Money `json:"price"`
+}
+
+// TripCombo is a flight+hotel pairing with its total for all passengers and nights.
+// Flight prices already cover every passenger, so only the hotel is multiplied.
+type TripCombo struct {
+	FlightIndex int   `json:"flight_index"`
+	HotelIndex  int   `json:"hotel_index"`
+	Total       Money `json:"total"`
+}
+
+// BuildTripSummary picks the same best-value combo as SmartFallbackRecommendation.
+// Returns nil when there are no flights or hotels to choose from.
+func BuildTripSummary(budget Money, numNights int, flights []Flight, hotels []Hotel) *TripSummary {
+	if len(flights) == 0 || len(hotels) == 0 {
+		return nil
+	}
+
+	cheapest := 0
+	for i, f := range flights {
+		if f.Price.Less(flights[cheapest].Price) {
+			cheapest = i
+		}
+	}
+	bestRated := 0
+	for i, h := range hotels {
+		if h.Rating > hotels[bestRated].Rating {
+			bestRated = i
+		}
+	}
+
+	fi, hi := bestValueFlight(flights), bestValueHotel(hotels)
+	total := flights[fi].Price.Add(hotels[hi].Price.Mul(numNights))
+
+	return &TripSummary{
+		CheapestFlight: TripPick{Index: cheapest, Price: flights[cheapest].Price},
+		BestRatedHotel: TripPick{Index: bestRated, Price: hotels[bestRated].Price},
+		BestValueCombo: TripCombo{FlightIndex: fi, HotelIndex: hi, Total: total},
+		WithinBudget:   !budget.Less(total),
+	}
+}
+
+// bestValueFlight returns the index of the cheapest non-stop flight, or 0 if none beats the first.
+func bestValueFlight(flights []Flight) int {
+	best := 0
+	for i, f := range flights {
+		if f.Stops == 0 && f.Price.Less(flights[best].Price) {
+			best = i
+		}
+	}
+	return best
+}
+
+// bestValueHotel returns the index of the hotel with the highest rating per unit of price.
+func bestValueHotel(hotels []Hotel) int {
+	best := 0
+	for i, h := range hotels {
+		if h.Rating/h.Price.Float64() > hotels[best].Rating/hotels[best].Price.Float64() {
+			best = i
+		}
+	}
+	return best
+}
+
+// ─── Budget Check ─────────────────────────────────────────────────────────────
+
+// BudgetWarning reports that even the cheapest flight+hotel combination costs more than
+// the stated budget, so the client can say so before the user starts selecting options.
+type BudgetWarning struct {
+	Budget       Money  `json:"budget"`
+	MinimumTotal Money  `json:"minimum_total"`
+	Shortfall    Money  `json:"shortfall"`
+	Message      string `json:"message"`
+}
+
+// CheckBudget compares the budget with the cheapest flight (priced for all passengers) plus the
+// cheapest hotel for all nights. Returns nil when the budget covers it or there's nothing to price.
+func CheckBudget(budget Money, numNights int, flights []Flight, hotels []Hotel) *BudgetWarning {
+	if len(flights) == 0 || len(hotels) == 0 {
+		return nil
+	}
+
+	cheapestFlight, cheapestHotel := flights[0].Price, hotels[0].Price
+	for _, f := range flights {
+		if f.Price.Less(cheapestFlight) {
+			cheapestFlight = f.Price
+		}
+	}
+	for _, h := range hotels {
+		if h.Price.Less(cheapestHotel) {
+			cheapestHotel = h.Price
+		}
+	}
+
+	minimum := cheapestFlight.Add(cheapestHotel.Mul(numNights))
+	if !budget.Less(minimum) {
+		return nil
+	}
+	return &BudgetWarning{
+		Budget:       budget,
+		MinimumTotal: minimum,
+		Shortfall:    minimum.Sub(budget),
+		Message:      fmt.Sprintf("Your %s budget is below the cheapest option for this trip; the minimum is %s", budget, minimum),
+	}
+}
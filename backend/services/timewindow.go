@@ -0,0 +1,115 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+// ─── Departure Time Windows ───────────────────────────────────────────────────
+
+// TimeWindow is an inclusive range of local departure clock times, in minutes since
+// midnight. From > To wraps past midnight (e.g. 22:00–02:00).
+type TimeWindow struct {
+	From int
+	To   int
+}
+
+// ParseTimeWindow parses "HH:MM" bounds; a missing bound is open (00:00 or 23:59).
+// Returns nil when both are empty, meaning no restriction.
+func ParseTimeWindow(from, to string) (*TimeWindow, error) {
+	if from == "" && to == "" {
+		return nil, nil
+	}
+	w := &TimeWindow{From: 0, To: 23*60 + 59}
+	if from != "" {
+		m, err := parseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		w.From = m
+	}
+	if to != "" {
+		m, err := parseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		w.To = m
+	}
+	return w, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether a segment time's local clock falls in the window. A nil window
+// or an unparseable time always matches, so bad provider data is never silently dropped.
+func (w *TimeWindow) Contains(raw string) bool {
+	if w == nil {
+		return true
+	}
+	t, _, ok := parseSegmentTime(raw)
+	if !ok {
+		return true
+	}
+	m := t.Hour()*60 + t.Minute()
+	if w.From <= w.To {
+		return m >= w.From && m <= w.To
+	}
+	return m >= w.From || m <= w.To
+}
+
+// FilterFlightsByTime keeps flights whose outbound and return departures fall in their
+// windows and returns how many were removed.
+func FilterFlightsByTime(flights []Flight, outbound, ret *TimeWindow) ([]Flight, int) {
+	kept := make([]Flight, 0, len(flights))
+	for _, f := range flights {
+		if outbound.Contains(f.DepartureTime) && (f.ReturnDepartureTime == "" || ret.Contains(f.ReturnDepartureTime)) {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(flights) - len(kept)
+}
+
+// FitFallbackToTimeWindows moves generated departures that fall outside the windows into
+// them, keeping each flight's duration, so estimated results respect the traveler's times.
+func FitFallbackToTimeWindows(flights []Flight, outbound, ret *TimeWindow) []Flight {
+	for i := range flights {
+		f := &flights[i]
+		if !f.Estimated {
+			continue
+		}
+		if !outbound.Contains(f.DepartureTime) {
+			f.DepartureTime, f.ArrivalTime = shiftIntoWindow(f.DepartureTime, f.DurationMinutes, outbound, i)
+		}
+		if f.ReturnDepartureTime != "" && !ret.Contains(f.ReturnDepartureTime) {
+			f.ReturnDepartureTime, f.ReturnArrivalTime = shiftIntoWindow(f.ReturnDepartureTime, f.ReturnDurationMinutes, ret, i)
+		}
+	}
+	return flights
+}
+
+// shiftIntoWindow re-times a departure on the same date inside w, staggering flights by
+// slot so they don't all leave at the same minute.
+func shiftIntoWindow(dep string, durationMin int, w *TimeWindow, slot int) (string, string) {
+	t, _, ok := parseSegmentTime(dep)
+	if !ok {
+		return dep, ""
+	}
+	span := w.To - w.From
+	if span < 0 {
+		span += 24 * 60
+	}
+	offset := 0
+	if span > 0 {
+		offset = (slot * 35) % (span + 1)
+	}
+	m := (w.From + offset) % (24 * 60)
+	newDep := time.Date(t.Year(), t.Month(), t.Day(), m/60, m%60, 0, 0, t.Location())
+	newArr := newDep.Add(time.Duration(durationMin) * time.Minute)
+	return newDep.Format(time.RFC3339), newArr.Format(time.RFC3339)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ─── Provider Usage ───────────────────────────────────────────────────────────
+
+// Provider keys used for usage tracking.
+const (
+	ProviderAmadeusFlights    = "amadeus_flights"
+	ProviderAmadeusHotels     = "amadeus_hotels"
+	ProviderAmadeusLocations  = "amadeus_locations"
+	ProviderAmadeusActivities = "amadeus_activities"
+	ProviderAmadeusAuth       = "amadeus_auth"
+	ProviderHuggingFace       = "huggingface"
+	ProviderOpenAI            = "openai"
+	ProviderWeather           = "weather"
+)
+
+type UsageStats struct {
+	Calls     int `json:"calls"`
+	Failures  int `json:"failures"`
+	Fallbacks int `json:"fallbacks"`
+}
+
+type usageTracker struct {
+	mu    sync.Mutex
+	day   string
+	stats map[string]*UsageStats
+}
+
+var usage = &usageTracker{stats: map[string]*UsageStats{}}
+
+// entry returns the counters for provider, resetting everything when the UTC day rolls over.
+// Caller must hold t.mu.
+func (t *usageTracker) entry(provider string) *UsageStats {
+	t.rollover()
+	s, ok := t.stats[provider]
+	if !ok {
+		s = &UsageStats{}
+		t.stats[provider] = s
+	}
+	return s
+}
+
+func (t *usageTracker) rollover() {
+	today := time.Now().UTC().Format("2006-01-02")
+	if t.day != today {
+		t.day = today
+		t.stats = map[string]*UsageStats{}
+	}
+}
+
+// RecordProviderCall counts one outbound call to provider; err marks it as failed.
+// The outcome also feeds provider's circuit breaker.
+func RecordProviderCall(provider string, err error) {
+	// A call aborted because our own client went away says nothing about the provider.
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	recordOutcome(provider, err)
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+	s := usage.entry(provider)
+	s.Calls++
+	if err != nil {
+		s.Failures++
+	}
+}
+
+// RecordFallback counts a search that had to use built-in data instead of provider.
+func RecordFallback(provider string) {
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+	usage.entry(provider).Fallbacks++
+}
+
+// UsageSnapshot returns today's UTC date and a copy of the per-provider counters.
+func UsageSnapshot() (string, map[string]UsageStats) {
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+	usage.rollover()
+	out := make(map[string]UsageStats, len(usage.stats))
+	for k, v := range usage.stats {
+		out[k] = *v
+	}
+	return usage.day, out
+}
+
+// amadeusProvider maps an Amadeus API path to its usage bucket.
+func amadeusProvider(path string) string {
+	switch {
+	case strings.Contains(path, "/security/oauth2"):
+		return ProviderAmadeusAuth
+	case strings.Contains(path, "flight"):
+		return ProviderAmadeusFlights
+	case strings.Contains(path, "hotel"):
+		return ProviderAmadeusHotels
+	case strings.Contains(path, "activities"):
+		return ProviderAmadeusActivities
+	default:
+		return ProviderAmadeusLocations
+	}
+}
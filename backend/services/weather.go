@@ -0,0 +1,248 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// ─── Weather ──────────────────────────────────────────────────────────────────
+// The PDF shows the forecast for the arrival day at the hotel. Live forecasts come from an
+// Open-Meteo compatible API (WEATHER_API_URL) when WEATHER_API_KEY is set and the date is
+// within its forecast range; otherwise the destination's seasonal average is shown.
+
+const (
+	defaultWeatherAPIURL = "https://customer-api.open-meteo.com/v1/forecast"
+	// weatherForecastDays is how far ahead the API forecasts; later dates use averages.
+	weatherForecastDays = 16
+)
+
+// ErrWeatherNotConfigured is returned by FetchWeather when WEATHER_API_KEY is not set.
+var ErrWeatherNotConfigured = errors.New("weather API not configured")
+
+// Weather is the forecast (or, when Estimated, the seasonal average) for one day.
+// PrecipitationChance is a percentage, or -1 when unknown.
+type Weather struct {
+	Date                string  `json:"date"`
+	Summary             string  `json:"summary"`
+	HighC               float64 `json:"high_c"`
+	LowC                float64 `json:"low_c"`
+	PrecipitationChance int     `json:"precipitation_chance"`
+	Estimated           bool    `json:"estimated"`
+}
+
+func (w Weather) String() string {
+	s := fmt.Sprintf("%s · %.0f°C / %.0f°C", w.Summary, w.HighC, w.LowC)
+	if w.PrecipitationChance >= 0 {
+		s += fmt.Sprintf(" · %d%% chance of rain", w.PrecipitationChance)
+	}
+	if w.Estimated {
+		s += " (seasonal average)"
+	}
+	return s
+}
+
+// weatherClient goes through WEATHER_PROXY when set, like the other providers.
+var weatherClient = sync.OnceValue(func() *http.Client {
+	return newProviderHTTPClient(envDuration("WEATHER_TIMEOUT", 5*time.Second), "WEATHER_PROXY")
+})
+
+type openMeteoResponse struct {
+	Daily struct {
+		Time          []string   `json:"time"`
+		WeatherCode   []*int     `json:"weather_code"`
+		High          []*float64 `json:"temperature_2m_max"`
+		Low           []*float64 `json:"temperature_2m_min"`
+		Precipitation []*int     `json:"precipitation_probability_max"`
+	} `json:"daily"`
+}
+
+// FetchWeather asks the weather API for the daily forecast at lat/lon on date (YYYY-MM-DD).
+func FetchWeather(lat, lon float64, date string) (w Weather, err error) {
+	key := os.Getenv("WEATHER_API_KEY")
+	if key == "" {
+		return Weather{}, ErrWeatherNotConfigured
+	}
+	if err := allowCall(ProviderWeather); err != nil {
+		return Weather{}, err
+	}
+	defer func() { RecordProviderCall(ProviderWeather, err) }()
+
+	endpoint := os.Getenv("WEATHER_API_URL")
+	if endpoint == "" {
+		endpoint = defaultWeatherAPIURL
+	}
+	q := url.Values{}
+	q.Set("latitude", fmt.Sprintf("%.4f", lat))
+	q.Set("longitude", fmt.Sprintf("%.4f", lon))
+	q.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_probability_max")
+	q.Set("timezone", "auto")
+	q.Set("start_date", date)
+	q.Set("end_date", date)
+	q.Set("apikey", key)
+
+	resp, err := weatherClient().Get(endpoint + "?" + q.Encode())
+	if err != nil {
+		// The URL carries the key; keep it out of the logs.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return Weather{}, fmt.Errorf("weather request failed: %w", err)
+	}
+	defer closeBody(resp.Body)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Weather{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Weather{}, fmt.Errorf("weather API error (%d): %s", resp.StatusCode, shortText(string(body), 200))
+	}
+
+	var parsed openMeteoResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return Weather{}, fmt.Errorf("failed to parse weather: %w", err)
+	}
+	d := parsed.Daily
+	if len(d.Time) == 0 || len(d.High) == 0 || len(d.Low) == 0 || d.High[0] == nil || d.Low[0] == nil {
+		return Weather{}, fmt.Errorf("no forecast for %s", date)
+	}
+	w = Weather{Date: d.Time[0], Summary: "Forecast", HighC: *d.High[0], LowC: *d.Low[0], PrecipitationChance: -1}
+	if len(d.WeatherCode) > 0 && d.WeatherCode[0] != nil {
+		w.Summary = weatherCodeSummary(*d.WeatherCode[0])
+	}
+	if len(d.Precipitation) > 0 && d.Precipitation[0] != nil {
+		w.PrecipitationChance = *d.Precipitation[0]
+	}
+	return w, nil
+}
+
+// weatherCodeSummary describes a WMO weather interpretation code.
+func weatherCodeSummary(code int) string {
+	switch {
+	case code == 0:
+		return "Clear sky"
+	case code <= 2:
+		return "Partly cloudy"
+	case code == 3:
+		return "Overcast"
+	case code == 45 || code == 48:
+		return "Fog"
+	case code >= 51 && code <= 57:
+		return "Drizzle"
+	case code >= 61 && code <= 67, code >= 80 && code <= 82:
+		return "Rain"
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return "Snow"
+	case code >= 95:
+		return "Thunderstorms"
+	}
+	return "Mixed conditions"
+}
+
+// ─── Trip Weather ─────────────────────────────────────────────────────────────
+
+type weatherEntry struct {
+	weather *Weather
+	expires time.Time
+}
+
+// weatherCache keeps answers per (city, date) for WEATHER_CACHE_TTL (default 3h), so every
+// itinerary to the same place on the same day shares one call.
+var weatherCache = struct {
+	sync.Mutex
+	entries map[string]weatherEntry
+}{entries: map[string]weatherEntry{}}
+
+// TripWeather is the weather for date at the destination: the live forecast at the hotel
+// (or the city center when the hotel has no coordinates) when one is available, else the
+// seasonal average. It returns nil for a destination with neither.
+func TripWeather(destination string, hotel Hotel, date string) *Weather {
+	city := airportToCity(destination)
+	key := city + "|" + date
+	weatherCache.Lock()
+	entry, ok := weatherCache.entries[key]
+	weatherCache.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.weather
+	}
+
+	w := tripWeather(city, hotel, date)
+	now := time.Now()
+	weatherCache.Lock()
+	for k, e := range weatherCache.entries {
+		if now.After(e.expires) {
+			delete(weatherCache.entries, k)
+		}
+	}
+	weatherCache.entries[key] = weatherEntry{weather: w, expires: now.Add(envDuration("WEATHER_CACHE_TTL", 3*time.Hour))}
+	weatherCache.Unlock()
+	return w
+}
+
+func tripWeather(city string, hotel Hotel, date string) *Weather {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	lat, lon := hotel.Latitude, hotel.Longitude
+	if !hotel.HasLocation() {
+		center := cityCenters[city] // zero for unknown cities
+		lat, lon = center[0], center[1]
+	}
+	inRange := time.Until(day) < weatherForecastDays*24*time.Hour && time.Since(day) < 24*time.Hour
+	if (lat != 0 || lon != 0) && inRange && os.Getenv("WEATHER_API_KEY") != "" {
+		w, err := FetchWeather(lat, lon, date)
+		if err == nil {
+			return &w
+		}
+		log.Printf("⚠️  Weather forecast for %s on %s failed: %v — using the seasonal average", city, date, err)
+		RecordFallback(ProviderWeather)
+	}
+	return seasonalWeather(city, day)
+}
+
+// seasonalWeather is the average for the city in day's month, or nil for an unknown city.
+func seasonalWeather(city string, day time.Time) *Weather {
+	avg, ok := seasonalAverages[city]
+	if !ok {
+		return nil
+	}
+	m := day.Month() - 1
+	return &Weather{
+		Date:                day.Format("2006-01-02"),
+		Summary:             "Typical for " + day.Month().String(),
+		HighC:               avg.high[m],
+		LowC:                avg.low[m],
+		PrecipitationChance: -1,
+		Estimated:           true,
+	}
+}
+
+// seasonalAverages are monthly mean highs and lows (°C, January first) for the cities in
+// cityCenters.
+var seasonalAverages = map[string]struct{ high, low [12]float64 }{
+	"LON": {[12]float64{8, 9, 12, 15, 18, 21, 23, 23, 20, 16, 11, 9}, [12]float64{3, 2, 4, 6, 9, 12, 14, 14, 12, 9, 5, 3}},
+	"PAR": {[12]float64{7, 9, 13, 16, 20, 23, 25, 25, 21, 16, 11, 8}, [12]float64{3, 3, 5, 7, 11, 14, 16, 16, 13, 10, 6, 3}},
+	"NYC": {[12]float64{4, 6, 10, 17, 22, 27, 29, 29, 25, 18, 12, 6}, [12]float64{-3, -2, 2, 8, 13, 18, 21, 21, 17, 11, 5, 0}},
+	"LAX": {[12]float64{20, 20, 21, 22, 23, 25, 28, 29, 28, 26, 23, 20}, [12]float64{9, 10, 11, 13, 15, 17, 19, 19, 18, 16, 11, 9}},
+	"DXB": {[12]float64{24, 25, 28, 33, 38, 40, 41, 41, 39, 35, 30, 26}, [12]float64{15, 16, 18, 22, 26, 28, 31, 31, 28, 24, 20, 17}},
+	"IST": {[12]float64{9, 9, 11, 16, 21, 26, 28, 29, 25, 20, 15, 11}, [12]float64{3, 3, 4, 8, 12, 17, 20, 21, 17, 13, 9, 5}},
+	"FRA": {[12]float64{4, 6, 11, 15, 20, 23, 25, 25, 20, 14, 8, 5}, [12]float64{-1, -1, 2, 5, 9, 12, 14, 14, 10, 6, 3, 0}},
+	"AMS": {[12]float64{6, 7, 10, 14, 18, 20, 22, 22, 19, 15, 10, 7}, [12]float64{1, 1, 3, 5, 8, 11, 13, 13, 11, 8, 4, 2}},
+	"BER": {[12]float64{3, 5, 9, 15, 19, 22, 24, 24, 19, 13, 7, 4}, [12]float64{-2, -2, 1, 4, 9, 12, 14, 14, 10, 6, 2, -1}},
+	"MAD": {[12]float64{10, 12, 16, 18, 22, 28, 32, 31, 26, 19, 13, 10}, [12]float64{3, 3, 6, 8, 11, 16, 19, 19, 15, 11, 6, 3}},
+	"BCN": {[12]float64{14, 15, 17, 19, 22, 26, 28, 29, 26, 22, 17, 14}, [12]float64{5, 6, 8, 10, 14, 18, 21, 21, 18, 14, 9, 6}},
+	"ROM": {[12]float64{12, 13, 16, 19, 23, 28, 31, 31, 27, 22, 16, 13}, [12]float64{3, 4, 6, 8, 12, 16, 18, 19, 15, 12, 7, 4}},
+	"TAS": {[12]float64{6, 9, 15, 22, 27, 33, 36, 34, 29, 21, 14, 8}, [12]float64{-3, -1, 5, 10, 14, 19, 21, 19, 14, 8, 3, -1}},
+	"TYO": {[12]float64{10, 10, 14, 19, 23, 26, 30, 31, 27, 22, 17, 12}, [12]float64{1, 2, 5, 10, 15, 19, 23, 24, 20, 15, 9, 4}},
+	"SIN": {[12]float64{30, 31, 32, 32, 32, 31, 31, 31, 31, 31, 31, 30}, [12]float64{24, 24, 25, 25, 26, 26, 25, 25, 25, 25, 24, 24}},
+	"BKK": {[12]float64{32, 33, 34, 35, 34, 33, 33, 32, 32, 32, 32, 31}, [12]float64{22, 24, 26, 27, 27, 26, 26, 26, 25, 25, 24, 22}},
+}
@@ -1,5 +1,5 @@
 import { useState } from "react";
-import { searchFlightsAndHotels } from "../services/api";
+import { streamFlightsAndHotels } from "../services/api";
 import {
   Plane,
   ArrowLeftRight,
@@ -25,7 +25,8 @@ function validate(form, isMultiCity) {
   if (!form.departure_date) return "Please select a departure date.";
   if (!form.return_date) return "Please select a return date.";
   if (form.return_date <= form.departure_date) return "Return date must be after departure date.";
-  if (!form.budget || Number(form.budget) <= 0) return "Enter a valid budget amount (USD).";
+  if (!form.budget || Number(form.budget) <= 0) return `Enter a valid budget amount (${form.currency}).`;
+  if (Number(form.infants) > Number(form.passengers)) return "Each infant must travel with an adult.";
   if (isMultiCity && form.return_origin && form.return_origin.length !== 3)
     return "Return departure airport must be exactly 3 letters (e.g. CDG).";
   return null;
@@ -34,10 +35,11 @@ function validate(form, isMultiCity) {
 export default function Home({ onResults }) {
   const [form, setForm] = useState({
     origin: "", destination: "", departure_date: "", return_date: "",
-    budget: "", passengers: "1", return_origin: "",
+    budget: "", currency: "USD", passengers: "1", children: "0", infants: "0", return_origin: "",
   });
   const [isMultiCity, setIsMultiCity] = useState(false);
   const [loading, setLoading] = useState(false);
+  const [stage, setStage] = useState("Searching…");
   const [error, setError] = useState(null);
 
   const set = (key) => (e) => setForm((prev) => ({ ...prev, [key]: e.target.value }));
@@ -48,10 +50,18 @@ export default function Home({ onResults }) {
     if (err) { setError(err); return; }
     setError(null);
     setLoading(true);
+    setStage("Searching flights & hotels…");
     try {
       const payload = { ...form };
       if (!isMultiCity || !form.return_origin) delete payload.return_origin;
-      const data = await searchFlightsAndHotels(payload);
+      const ready = new Set();
+      const data = await streamFlightsAndHotels(payload, (name) => {
+        ready.add(name);
+        if (name === "ai_ready") setStage("Saving your trip…");
+        else if (ready.has("flights_ready") && ready.has("hotels_ready")) setStage("Writing recommendations…");
+        else if (name === "flights_ready") setStage("Flights found — checking hotels…");
+        else if (name === "hotels_ready") setStage("Hotels found — checking flights…");
+      });
       onResults(data, { ...form, isMultiCity });
     } catch (e) {
       setError(e.message);
@@ -144,10 +154,26 @@ export default function Home({ onResults }) {
               </div>
             )}
             <div className="form-group home__pax-group">
-              <label className="form-label">Passengers</label>
+              <label className="form-label">Adults</label>
               <select className="form-select" value={form.passengers} onChange={set("passengers")}>
                 {[1, 2, 3, 4, 5, 6].map((n) => (
-                  <option key={n} value={n}>{n} {n === 1 ? "Traveler" : "Travelers"}</option>
+                  <option key={n} value={n}>{n} {n === 1 ? "Adult" : "Adults"}</option>
+                ))}
+              </select>
+            </div>
+            <div className="form-group home__pax-group">
+              <label className="form-label">Children</label>
+              <select className="form-select" value={form.children} onChange={set("children")} title="Aged 2–11">
+                {[0, 1, 2, 3, 4].map((n) => (
+                  <option key={n} value={n}>{n} {n === 1 ? "Child" : "Children"}</option>
+                ))}
+              </select>
+            </div>
+            <div className="form-group home__pax-group">
+              <label className="form-label">Infants</label>
+              <select className="form-select" value={form.infants} onChange={set("infants")} title="Under 2, on an adult's lap">
+                {Array.from({ length: Number(form.passengers) + 1 }, (_, n) => (
+                  <option key={n} value={n}>{n} {n === 1 ? "Infant" : "Infants"}</option>
                 ))}
               </select>
             </div>
@@ -163,9 +189,17 @@ export default function Home({ onResults }) {
               <input className="form-input" type="date" value={form.return_date} onChange={set("return_date")} />
             </div>
             <div className="form-group">
-              <label className="form-label">Budget (USD)</label>
+              <label className="form-label">Budget ({form.currency})</label>
               <input className="form-input" type="number" placeholder="e.g. 1500" value={form.budget} onChange={set("budget")} min={1} />
             </div>
+            <div className="form-group home__pax-group">
+              <label className="form-label">Currency</label>
+              <select className="form-select" value={form.currency} onChange={set("currency")}>
+                {["USD", "EUR", "GBP"].map((code) => (
+                  <option key={code} value={code}>{code}</option>
+                ))}
+              </select>
+            </div>
           </div>
 
           {error && (
@@ -176,7 +210,7 @@ export default function Home({ onResults }) {
 
           <button className="btn btn--gold btn--full" style={{ marginTop: 24 }} onClick={handleSearch} disabled={loading}>
             {loading ? (
-              <><span className="spinner spinner--sm" /> Searching…</>
+              <><span className="spinner spinner--sm" /> {stage}</>
             ) : (
               <><Search size={17} /> Search Flights &amp; Hotels</>
             )}
@@ -18,6 +18,14 @@ import {
 } from "lucide-react";
 import "./Results.css";
 
+// Why an estimated search had no live data (fallback_reason in the search response)
+const FALLBACK_REASONS = {
+  no_results: "No live offers were found for this trip",
+  rate_limited: "The travel provider is busy right now; try again in a minute",
+  auth_failed: "Live prices are unavailable right now",
+  upstream_error: "The travel provider didn't respond",
+};
+
 function fmtDate(iso) {
   if (!iso) return "—";
   return new Date(iso + "T00:00:00").toLocaleDateString("en-US", {
@@ -32,8 +40,9 @@ function fmtTime(iso) {
   });
 }
 
-function fmtPrice(n) {
+function fmtPrice(n, currency = "USD") {
   return Number(n).toLocaleString("en-US", {
+    style: "currency", currency: currency || "USD",
     minimumFractionDigits: 0, maximumFractionDigits: 0,
   });
 }
@@ -52,6 +61,19 @@ function renderMarkdown(text) {
 
 function FlightCard({ flight, index, selected, onSelect }) {
   const isNaN_price = isNaN(flight.price) || flight.price <= 0;
+  // Multi-leg itineraries list every flight, tagged with where it departs from
+  const legs = flight.legs?.length
+    ? flight.legs.map((l) => ({
+        tag: l.origin,
+        title: `${l.origin} → ${l.destination} · ${l.airline}`,
+        dep: l.departure_time,
+        arr: l.arrival_time,
+        dur: l.duration,
+      }))
+    : [
+        { tag: "Out", dep: flight.departure_time, arr: flight.arrival_time, dur: flight.duration },
+        { tag: "Ret", dep: flight.return_departure_time, arr: flight.return_arrival_time, dur: flight.return_duration },
+      ];
   return (
     <article
       className={`result-card result-card--flight ${selected ? "card--selected" : ""}`}
@@ -67,33 +89,37 @@ function FlightCard({ flight, index, selected, onSelect }) {
       <div className="fc__airline-col">
         <div className="fc__airline-name">{flight.airline}</div>
         {flight.flight_number && <div className="fc__flight-num">{flight.flight_number}</div>}
-        <span className={`badge ${flight.stops === 0 ? "badge--direct" : "badge--stop"}`}>
+        {flight.origin && flight.destination && !flight.legs?.length && (
+          <div className="fc__flight-num">{flight.origin} → {flight.destination}</div>
+        )}
+        <span
+          className={`badge ${flight.stops === 0 ? "badge--direct" : "badge--stop"}`}
+          title={flight.layovers?.map((l) => `${l.airport} ${l.duration}`).join(", ")}
+        >
           {flight.stops === 0 ? "Direct" : `${flight.stops} stop${flight.stops > 1 ? "s" : ""}`}
         </span>
       </div>
       <div className="fc__legs">
-        <div className="fc__leg">
-          <span className="fc__leg-tag">Out</span>
-          <span className="fc__time">{fmtTime(flight.departure_time)}</span>
-          <span className="fc__arrow"><ArrowRight size={13} /></span>
-          <span className="fc__time">{fmtTime(flight.arrival_time)}</span>
-          <span className="fc__dur">{flight.duration}</span>
-        </div>
-        <div className="fc__leg">
-          <span className="fc__leg-tag">Ret</span>
-          <span className="fc__time">{fmtTime(flight.return_departure_time)}</span>
-          <span className="fc__arrow"><ArrowRight size={13} /></span>
-          <span className="fc__time">{fmtTime(flight.return_arrival_time)}</span>
-          <span className="fc__dur">{flight.return_duration}</span>
-        </div>
+        {legs.map((leg, i) => (
+          <div className="fc__leg" key={i} title={leg.title}>
+            <span className="fc__leg-tag">{leg.tag}</span>
+            <span className="fc__time">{fmtTime(leg.dep)}</span>
+            <span className="fc__arrow"><ArrowRight size={13} /></span>
+            <span className="fc__time">{fmtTime(leg.arr)}</span>
+            <span className="fc__dur">{leg.dur}</span>
+          </div>
+        ))}
       </div>
       <div className="fc__price-col">
         {isNaN_price ? (
           <div className="price-na">N/A</div>
         ) : (
           <>
-            <div className="price-amount">${fmtPrice(flight.price)}</div>
-            <div className="price-label">per person</div>
+            <div className="price-amount">{fmtPrice(flight.price, flight.currency)}</div>
+            <div className="price-label">all travelers</div>
+            {flight.co2_kg > 0 && (
+              <div className="price-total-hint">~{Math.round(flight.co2_kg)} kg CO2 / person</div>
+            )}
           </>
         )}
       </div>
@@ -127,6 +153,7 @@ function HotelCard({ hotel, index, selected, onSelect, nights }) {
         <div className="hc__location">
           <span className="hc__pin"><MapPin size={12} /></span>
           {hotel.location}
+          {hotel.distance_km > 0 && ` · ${hotel.distance_km.toFixed(1)} km from center`}
         </div>
         <div className="hc__stars">
           <Stars rating={hotel.rating} />
@@ -142,10 +169,10 @@ function HotelCard({ hotel, index, selected, onSelect, nights }) {
       <div className="hc__price-col">
         {price ? (
           <>
-            <div className="price-amount">${fmtPrice(price)}</div>
+            <div className="price-amount">{fmtPrice(price, hotel.currency)}</div>
             <div className="price-label">/ night</div>
             {nights && (
-              <div className="price-total-hint">${fmtPrice(price * nights)} total</div>
+              <div className="price-total-hint">{fmtPrice(price * nights, hotel.currency)} total</div>
             )}
           </>
         ) : (
@@ -167,15 +194,17 @@ export default function Results({ data, searchForm, onBack }) {
   const depD   = new Date(searchForm.departure_date + "T00:00:00");
   const retD   = new Date(searchForm.return_date    + "T00:00:00");
   const nights = Math.round((retD - depD) / 86400000);
-  const passengers = Number(searchForm.passengers) || 1;
+  const passengers = (Number(searchForm.passengers) || 1)
+    + (Number(searchForm.children) || 0) + (Number(searchForm.infants) || 0);
 
   const flight = data.flights?.[selFlight];
   const hotel  = data.hotels?.[selHotel];
 
   const flightPrice = flight ? Number(flight.price) || 0 : 0;
   const hotelPrice  = hotel  ? sanitizeHotelPrice(hotel.price) || 0 : 0;
-  // Flight price is per-person round-trip; multiply by passengers for real total
-  const totalCost   = flightPrice * passengers + hotelPrice * nights;
+  // Flight price is the round-trip total for every traveler, as Amadeus prices it
+  const totalCost   = flightPrice + hotelPrice * nights;
+  const currency    = flight?.currency || searchForm.currency || "USD";
 
   const handleGenerate = async () => {
     setGenError(null);
@@ -184,6 +213,9 @@ export default function Results({ data, searchForm, onBack }) {
     try {
       const res = await generateItinerary({
         search_id:             data.search_id,
+        flight_id:             flight?.id,
+        hotel_id:              hotel?.id,
+        // Searches saved before offers had IDs still select by position
         selected_flight_index: selFlight,
         selected_hotel_index:  selHotel,
         traveler_name:         travelerName || "Guest Traveler",
@@ -205,9 +237,9 @@ export default function Results({ data, searchForm, onBack }) {
           <ArrowLeft size={14} /> Back
         </button>
         <div className="results__route">
-          <span className="route-chip">{searchForm.origin?.toUpperCase()}</span>
+          <span className="route-chip" title={searchForm.origin?.toUpperCase()}>{data.origin_name || searchForm.origin?.toUpperCase()}</span>
           <span className="route-arrow"><ArrowRight size={16} /></span>
-          <span className="route-chip">{searchForm.destination?.toUpperCase()}</span>
+          <span className="route-chip" title={searchForm.destination?.toUpperCase()}>{data.destination_name || searchForm.destination?.toUpperCase()}</span>
           {data.return_origin && (
             <>
               <span className="route-arrow" style={{ fontSize: 11, color: "var(--text-muted)" }}>↩ from</span>
@@ -216,7 +248,7 @@ export default function Results({ data, searchForm, onBack }) {
           )}
           <span className="route-meta">
             {fmtDate(searchForm.departure_date)} – {fmtDate(searchForm.return_date)}
-            &nbsp;·&nbsp;{searchForm.passengers} pax
+            &nbsp;·&nbsp;{passengers} pax
             &nbsp;·&nbsp;{nights} nights
           </span>
         </div>
@@ -231,7 +263,12 @@ export default function Results({ data, searchForm, onBack }) {
             </div>
             <span className="ai-box__title">AI Recommendations</span>
             {data.source === "estimated" && (
-              <span className="ai-box__badge">Estimated data</span>
+              <span className="ai-box__badge" title={FALLBACK_REASONS[data.fallback_reason]}>
+                Estimated data
+              </span>
+            )}
+            {data.source === "partial" && (
+              <span className="ai-box__badge">Partly estimated</span>
             )}
           </div>
           <div className="ai-box__body">
@@ -244,7 +281,7 @@ export default function Results({ data, searchForm, onBack }) {
       <div className="results__section">
         <div className="results__section-head">
           <h2 className="heading-section">Flights</h2>
-          <span className="text-label">Round-trip · per person · select one</span>
+          <span className="text-label">Round-trip · all travelers · select one</span>
         </div>
         <div className="results__list">
           {data.flights?.map((f, i) => (
@@ -277,7 +314,7 @@ export default function Results({ data, searchForm, onBack }) {
               Flight
             </span>
             <span className="confirm-panel__row-value">
-              {flight?.airline} — ${fmtPrice(flightPrice)}/person × {passengers} = ${fmtPrice(flightPrice * passengers)}
+              {flight?.airline} — {fmtPrice(flightPrice, currency)} for {passengers} {passengers === 1 ? "traveler" : "travelers"}
             </span>
           </div>
           <div className="confirm-panel__row">
@@ -292,7 +329,7 @@ export default function Results({ data, searchForm, onBack }) {
               <DollarSign size={13} style={{ display: "inline", marginRight: 6, verticalAlign: "middle" }} />
               Hotel rate
             </span>
-            <span className="confirm-panel__row-value">${fmtPrice(hotelPrice)} / night</span>
+            <span className="confirm-panel__row-value">{fmtPrice(hotelPrice, currency)} / night</span>
           </div>
           <div className="confirm-panel__row">
             <span className="confirm-panel__row-label">
@@ -306,13 +343,13 @@ export default function Results({ data, searchForm, onBack }) {
               <Hotel size={13} style={{ display: "inline", marginRight: 6, verticalAlign: "middle" }} />
               Hotel total
             </span>
-            <span className="confirm-panel__row-value">${fmtPrice(hotelPrice * nights)}</span>
+            <span className="confirm-panel__row-value">{fmtPrice(hotelPrice * nights, currency)}</span>
           </div>
         </div>
 
         <div className="confirm-panel__total">
           <span className="confirm-panel__total-label">Estimated Total</span>
-          <span className="confirm-panel__total-value">${fmtPrice(totalCost)}</span>
+          <span className="confirm-panel__total-value">{fmtPrice(totalCost, currency)}</span>
         </div>
 
         <div className="form-group" style={{ marginTop: 24 }}>
@@ -1,10 +1,13 @@
 const BASE_URL = import.meta.env.VITE_API_BASE_URL || "http://localhost:8080/api";
 
+// Sent when the backend sets API_KEY; search, generate, share and delete are rejected without it
+const AUTH_HEADERS = import.meta.env.VITE_API_KEY ? { "X-API-Key": import.meta.env.VITE_API_KEY } : {};
+
 // ─── Core fetcher ────────────────────────────────────────────────────────────
 async function request(endpoint, options = {}) {
   const url = `${BASE_URL}${endpoint}`;
   const response = await fetch(url, {
-    headers: { "Content-Type": "application/json" },
+    headers: { "Content-Type": "application/json", ...AUTH_HEADERS },
     ...options,
   });
 
@@ -25,21 +28,75 @@ async function request(endpoint, options = {}) {
  * @param {string} payload.destination - Destination airport code (e.g., "IST")
  * @param {string} payload.departure_date - ISO date string
  * @param {string} payload.return_date - ISO date string
- * @param {number} payload.budget - Total budget in USD
- * @param {number} payload.passengers - Number of passengers
+ * @param {number} payload.budget - Total budget, in payload.currency
+ * @param {string} [payload.currency] - ISO 4217 code for all prices (defaults to USD)
+ * @param {number} payload.passengers - Number of adults
+ * @param {number} [payload.children] - Children aged 2–11
+ * @param {number} [payload.infants] - Lap infants (no more than adults)
  */
 export async function searchFlightsAndHotels(payload) {
+  return request("/search", {
+    method: "POST",
+    body: JSON.stringify(searchBody(payload)),
+  });
+}
+
+/**
+ * Same search, streamed: onEvent(name, data) is called for flights_ready, hotels_ready
+ * and ai_ready as each stage finishes. Resolves with the full response from the done event.
+ * @param {Object} payload - Search parameters, as for searchFlightsAndHotels
+ * @param {(name: string, data: Object) => void} onEvent
+ */
+export async function streamFlightsAndHotels(payload, onEvent) {
+  const response = await fetch(`${BASE_URL}/search/stream`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json", Accept: "text/event-stream", ...AUTH_HEADERS },
+    body: JSON.stringify(searchBody(payload)),
+  });
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: "Unknown error" }));
+    throw new Error(error.error || `Request failed: ${response.status}`);
+  }
+
+  const reader = response.body.getReader();
+  const decoder = new TextDecoder();
+  let buffer = "";
+  for (;;) {
+    const { value, done } = await reader.read();
+    if (done) break;
+    buffer += decoder.decode(value, { stream: true });
+
+    // Events are separated by a blank line
+    let end;
+    while ((end = buffer.indexOf("\n\n")) >= 0) {
+      const raw = buffer.slice(0, end);
+      buffer = buffer.slice(end + 2);
+      let name = "message";
+      let data = "";
+      for (const line of raw.split("\n")) {
+        if (line.startsWith("event:")) name = line.slice(6).trim();
+        else if (line.startsWith("data:")) data += line.slice(5).trim();
+      }
+      const parsed = data ? JSON.parse(data) : {};
+      if (name === "done") return parsed;
+      if (name === "error") throw new Error(parsed.error || "Search failed");
+      onEvent?.(name, parsed);
+    }
+  }
+  throw new Error("Search ended unexpectedly");
+}
+
+function searchBody(payload) {
   const body = {
     ...payload,
     budget: Number(payload.budget),
     passengers: Number(payload.passengers),
+    children: Number(payload.children) || 0,
+    infants: Number(payload.infants) || 0,
   };
   // Only include return_origin if it's a non-empty string
   if (!body.return_origin) delete body.return_origin;
-  return request("/search", {
-    method: "POST",
-    body: JSON.stringify(body),
-  });
+  return body;
 }
 
 /**
@@ -70,6 +127,35 @@ export function downloadItineraryPDF(id) {
   document.body.removeChild(a);
 }
 
+/**
+ * URL that opens a generated PDF in the browser's viewer instead of downloading it
+ * @param {string} id - Itinerary ID
+ */
+export function itineraryPreviewURL(id) {
+  return `${BASE_URL}/download/${id}?inline=true`;
+}
+
+/**
+ * Create a read-only share link for an itinerary (replaces any earlier link)
+ * @param {string} id - Itinerary ID
+ * @param {number} [ttlHours] - Link lifetime in hours (default 72, at most 720)
+ */
+export async function createShareLink(id, ttlHours) {
+  const query = ttlHours ? `?ttl_hours=${ttlHours}` : "";
+  return request(`/itinerary/${id}/share${query}`, { method: "POST" });
+}
+
+/**
+ * Cheapest week-long round trips departing in a month, cheapest first
+ * @param {string} origin - Origin airport or city code
+ * @param {string} destination - Destination airport or city code
+ * @param {string} month - "YYYY-MM"
+ */
+export async function getCheapestDates(origin, destination, month) {
+  const params = new URLSearchParams({ origin, destination, month });
+  return request(`/cheapest-dates?${params}`);
+}
+
 /**
  * Health check endpoint
  */